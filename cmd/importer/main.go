@@ -0,0 +1,79 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"lunar-backend-challenge/internal/deadletter"
+	"lunar-backend-challenge/internal/importer"
+	"lunar-backend-challenge/internal/ingest"
+	"lunar-backend-challenge/internal/storage"
+)
+
+func main() {
+	flags := flag.NewFlagSet("importer", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: importer [flags] [file.ndjson ...]")
+		fmt.Fprintln(flags.Output(), "Applies recorded rocket messages, one JSON message per line, from files or standard input.")
+		fmt.Fprintln(flags.Output(), "Without -server this is a dry run: messages are applied to an in-memory repository that is discarded on exit.")
+		flags.PrintDefaults()
+	}
+	server := flags.String("server", "", "post messages to the server at this URL (e.g. http://localhost:8088); without it messages are applied to an in-memory repository as a dry run")
+	dryRun := flags.Bool("dry-run", false, "only decode and validate messages, without applying them even to the in-memory repository")
+	progressEvery := flags.Int("progress", importer.DefaultProgressEvery, "lines between progress reports on stderr, 0 to disable")
+	jsonReport := flags.Bool("json", false, "print the final report as JSON")
+	verbose := flags.Bool("verbose", false, "log every message processed by the local repository")
+	flags.Parse(os.Args[1:])
+
+	paths := flags.Args()
+	if len(paths) == 0 {
+		paths = []string{"-"}
+	}
+
+	// The local pipeline logs every message, which drowns the progress reports
+	if !*verbose {
+		log.SetOutput(io.Discard)
+	}
+
+	var repository *storage.RocketRepository
+	imp := &importer.Importer{ProgressEvery: *progressEvery}
+	if *progressEvery > 0 {
+		imp.Progress = os.Stderr
+	}
+	switch {
+	case *dryRun:
+		imp.Sink = importer.DryRunSink{}
+	case *server != "":
+		imp.Sink = importer.NewHTTPSink(*server)
+	default:
+		fmt.Fprintln(os.Stderr, "Dry run: no -server given, messages are applied to an in-memory repository that is discarded on exit")
+		repository = storage.NewRocketRepository()
+		imp.Sink = importer.RepositorySink{Ingest: ingest.NewService(repository, deadletter.NewStore(deadletter.DefaultCapacity))}
+	}
+
+	report := importer.NewReport()
+	err := imp.ImportFiles(paths, report)
+
+	if *jsonReport {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		encoder.Encode(report)
+	} else {
+		for _, failure := range report.Failures {
+			fmt.Printf("%s:%d: %s: %s\n", failure.Source, failure.Line, failure.Outcome, failure.Error)
+		}
+		fmt.Println(report)
+		if repository != nil {
+			fmt.Printf("%d rockets in the dry run, not kept\n", len(repository.GetAllRockets()))
+		}
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Import failed: %v\n", err)
+		os.Exit(1)
+	}
+}
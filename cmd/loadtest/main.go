@@ -0,0 +1,188 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"os/signal"
+	"strings"
+	"text/tabwriter"
+
+	"lunar-backend-challenge/internal/api"
+	"lunar-backend-challenge/internal/loadtest"
+	"lunar-backend-challenge/internal/middleware"
+	"lunar-backend-challenge/internal/simulator"
+)
+
+func main() {
+	flags := flag.NewFlagSet("loadtest", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: loadtest [url] [flags]")
+		fmt.Fprintln(flags.Output(), "Posts simulated messages to the server at url (e.g. http://localhost:8088) as fast as possible")
+		fmt.Fprintln(flags.Output(), "while listing its rockets, and reports latency percentiles. Without url the server runs in")
+		fmt.Fprintln(flags.Output(), "this process and its allocations are reported too.")
+		flags.PrintDefaults()
+	}
+	defaults := simulator.DefaultConfig()
+	messages := flags.Int("messages", 10000, "messages to post, not counting duplicates")
+	concurrency := flags.Int("concurrency", 8, "number of concurrent senders")
+	rockets := flags.Int("rockets", 100, "rockets in flight at any time")
+	explosionRate := flags.Float64("explosion-rate", defaults.ExplosionRate, "chance that a message is the explosion of its rocket")
+	reorderRate := flags.Float64("reorder-rate", 0, "chance that a message is delivered after later ones")
+	duplicateRate := flags.Float64("duplicate-rate", 0, "chance that a message is delivered twice")
+	reorderWindow := flags.Int("reorder-window", defaults.ReorderWindow, "how many later messages a reordered or duplicate message may be delivered after")
+	seed := flags.Uint64("seed", 1, "seed of the simulated messages, 0 for a random one")
+	preload := flags.Int("preload", 0, "rockets to launch before the measured run")
+	listConcurrency := flags.Int("list-concurrency", 1, "concurrent GET /rockets clients during the run")
+	listQuery := flags.String("list-query", "sortBy=speed&sortOrder=desc", "query of the GET /rockets requests")
+	jsonReport := flags.Bool("json", false, "print the report as JSON")
+	output := flags.String("output", "", "also write the report as JSON to this file, e.g. to use as a later baseline")
+	baseline := flags.String("baseline", "", "compare with the JSON report of an earlier run")
+
+	positional := parseInterspersed(flags, os.Args[1:])
+	if len(positional) > 1 {
+		flags.Usage()
+		os.Exit(2)
+	}
+	query, err := url.ParseQuery(*listQuery)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid list query: %v\n", err)
+		os.Exit(2)
+	}
+
+	config := loadtest.Config{
+		Simulation: simulator.Config{
+			Rockets:       *rockets,
+			Messages:      *messages,
+			Concurrency:   *concurrency,
+			ExplosionRate: *explosionRate,
+			ReorderRate:   *reorderRate,
+			DuplicateRate: *duplicateRate,
+			ReorderWindow: *reorderWindow,
+			Seed:          *seed,
+		},
+		Preload:         *preload,
+		ListConcurrency: *listConcurrency,
+		ListQuery:       query,
+	}
+	if err := config.Validate(); err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid configuration: %v\n", err)
+		os.Exit(2)
+	}
+
+	var baseURL string
+	if len(positional) == 1 {
+		baseURL = strings.TrimSuffix(positional[0], "/")
+	} else {
+		// The handler logs every message, which would be measured too
+		log.SetOutput(io.Discard)
+		server := localServer()
+		defer server.Close()
+		baseURL = server.URL
+		config.MeasureAllocations = true
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	report, err := loadtest.Run(ctx, baseURL, config)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Load test failed: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *jsonReport {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		encoder.Encode(report)
+	} else {
+		printReport(os.Stdout, report)
+	}
+
+	if *output != "" {
+		data, _ := json.MarshalIndent(report, "", "  ")
+		if err := os.WriteFile(*output, append(data, '\n'), 0o644); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to write report: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	if *baseline != "" {
+		data, err := os.ReadFile(*baseline)
+		var previous loadtest.Report
+		if err == nil {
+			err = json.Unmarshal(data, &previous)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to read baseline: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Fprintln(os.Stderr)
+		printComparison(os.Stderr, loadtest.Compare(previous, report))
+	}
+}
+
+// localServer serves the message and rocket list endpoints from a new repository
+func localServer() *httptest.Server {
+	handler := api.NewAPIHandler()
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /messages", handler.HandleMessage)
+	mux.HandleFunc("GET /rockets", handler.HandleGetRockets)
+	return httptest.NewServer(middleware.ChainMiddleware(mux, middleware.ErrorHandler, middleware.ContentTypeJSON))
+}
+
+// printReport prints the latencies as a table
+func printReport(w io.Writer, report loadtest.Report) {
+	fmt.Fprintf(w, "%d messages generated, %d sent in %.2fs, %d rejected\n",
+		report.Simulation.Generated, report.Simulation.Sent, report.Elapsed, report.Simulation.Errors)
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(table, "REQUEST\tREQUESTS\tERRORS\tPER SECOND\tMEAN MS\tP50 MS\tP90 MS\tP95 MS\tP99 MS\tMAX MS\t")
+	for _, row := range []struct {
+		name    string
+		summary loadtest.Summary
+	}{{"POST /messages", report.Ingest}, {"GET /rockets", report.List}} {
+		s := row.summary
+		fmt.Fprintf(table, "%s\t%d\t%d\t%.1f\t%.3f\t%.3f\t%.3f\t%.3f\t%.3f\t%.3f\t\n",
+			row.name, s.Requests, s.Errors, s.Throughput, s.Mean, s.P50, s.P90, s.P95, s.P99, s.Max)
+	}
+	table.Flush()
+	if allocations := report.Allocations; allocations != nil {
+		fmt.Fprintf(w, "%.1f allocations and %.0f bytes per request (%d allocations, %d bytes in total)\n",
+			allocations.AllocsPerRequest, allocations.BytesPerRequest, allocations.Allocs, allocations.Bytes)
+	}
+}
+
+// printComparison prints the changes since a baseline as a table
+func printComparison(w io.Writer, deltas []loadtest.Delta) {
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(table, "METRIC\tBASELINE\tCURRENT\tCHANGE\t\t")
+	for _, delta := range deltas {
+		verdict := "worse"
+		if delta.Better {
+			verdict = "better"
+		}
+		fmt.Fprintf(table, "%s\t%.3f\t%.3f\t%+.1f%%\t%s\t\n", delta.Metric, delta.Baseline, delta.Current, 100*delta.Change, verdict)
+	}
+	table.Flush()
+}
+
+// parseInterspersed parses flags placed before or after the positional arguments and returns
+// the positional arguments
+func parseInterspersed(flags *flag.FlagSet, args []string) []string {
+	var positional []string
+	for {
+		flags.Parse(args)
+		args = flags.Args()
+		if len(args) == 0 {
+			return positional
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
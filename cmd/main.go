@@ -1,20 +1,70 @@
 package main
 
 import (
+	"context"
 	"log"
+	"net"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	_ "lunar-backend-challenge/docs"
+	"lunar-backend-challenge/internal/alerting"
 	"lunar-backend-challenge/internal/api"
+	"lunar-backend-challenge/internal/config"
+	"lunar-backend-challenge/internal/graphapi"
+	"lunar-backend-challenge/internal/grpcapi"
+	"lunar-backend-challenge/internal/grpcapi/rocketsv1"
+	"lunar-backend-challenge/internal/importer"
+	"lunar-backend-challenge/internal/ingest"
 	"lunar-backend-challenge/internal/middleware"
+	"lunar-backend-challenge/internal/recording"
+	"lunar-backend-challenge/internal/storage"
 
 	httpSwagger "github.com/swaggo/http-swagger"
+	"google.golang.org/grpc"
 )
 
 func main() {
-	// Create the API handler
-	apiHandler := api.NewAPIHandler()
+	cfg, err := config.Load(os.Args[1:])
+	if err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+
+	// Create the repository and the API handler serving it
+	repository := storage.NewRocketRepositoryWithConfig(cfg.Storage())
+	apiHandler := api.NewAPIHandlerWithRepository(repository)
+
+	// Load alerting rules, more can be added through /admin/rules
+	if cfg.RulesFile != "" {
+		rules, err := alerting.LoadRules(cfg.RulesFile)
+		if err != nil {
+			log.Fatalf("Failed to load alerting rules: %v", err)
+		}
+		for _, rule := range rules {
+			if err := apiHandler.Alerts.AddRule(rule); err != nil {
+				log.Fatalf("Failed to add alerting rule %s: %v", rule.ID, err)
+			}
+		}
+		log.Printf("Loaded %d alerting rules from %s", len(rules), cfg.RulesFile)
+	}
+
+	// Seed the repository from recorded messages, rejected ones end up in the dead-letter store
+	if len(cfg.ImportFiles) > 0 {
+		seeder := &importer.Importer{Sink: importer.RepositorySink{Ingest: ingest.NewService(repository, apiHandler.DeadLetters)}}
+		report := importer.NewReport()
+		if err := seeder.ImportFiles(cfg.ImportFiles, report); err != nil {
+			log.Fatalf("Failed to import messages: %v", err)
+		}
+		log.Printf("Imported %s from %d files", report, len(cfg.ImportFiles))
+	}
+
+	// Run time based housekeeping, e.g. bootstrapping rockets whose launch was never received
+	// and detecting rockets that went silent
+	go repository.RunMaintenance(context.Background(), cfg.MaintenanceInterval)
+	go apiHandler.Alerts.Run(context.Background(), cfg.MaintenanceInterval)
 
 	// Create a new ServeMux
 	mux := http.NewServeMux()
@@ -23,25 +73,103 @@ func main() {
 	mux.HandleFunc("POST /messages", apiHandler.HandleMessage)
 	mux.HandleFunc("GET /rockets", apiHandler.HandleGetRockets)
 	mux.HandleFunc("GET /rockets/{id}", apiHandler.HandleGetRocket)
+	mux.HandleFunc("GET /rockets/{id}/launches", apiHandler.HandleGetRocketLaunches)
+	mux.HandleFunc("GET /rockets/{id}/speed", apiHandler.HandleGetRocketSpeed)
+	mux.HandleFunc("GET /rockets/{id}/events", apiHandler.HandleGetRocketEvents)
+	mux.HandleFunc("GET /stats", apiHandler.HandleGetStats)
+	mux.HandleFunc("GET /missions", apiHandler.HandleGetMissions)
+	mux.HandleFunc("GET /missions/{name}", apiHandler.HandleGetMission)
+	mux.HandleFunc("GET /alerts", apiHandler.HandleGetAlerts)
 	mux.HandleFunc("GET /debug/rockets", apiHandler.HandleDebugAll)
 	mux.HandleFunc("GET /debug/rockets/{id}", apiHandler.HandleDebugRocket)
+	mux.HandleFunc("GET /metrics", apiHandler.HandleMetrics)
+	mux.Handle("/graphql", graphapi.NewHandler(repository, cfg.GraphQL()))
+
+	// Admin routes
+	mux.HandleFunc("GET /admin/dead-letters", apiHandler.HandleListDeadLetters)
+	mux.HandleFunc("GET /admin/dead-letters/{id}", apiHandler.HandleGetDeadLetter)
+	mux.HandleFunc("POST /admin/dead-letters/{id}/replay", apiHandler.HandleReplayDeadLetter)
+	mux.HandleFunc("DELETE /admin/dead-letters/{id}", apiHandler.HandleDiscardDeadLetter)
+	mux.HandleFunc("GET /admin/rules", apiHandler.HandleListRules)
+	mux.HandleFunc("POST /admin/rules", apiHandler.HandleCreateRule)
+	mux.HandleFunc("GET /admin/rules/{id}", apiHandler.HandleGetRule)
+	mux.HandleFunc("PUT /admin/rules/{id}", apiHandler.HandlePutRule)
+	mux.HandleFunc("DELETE /admin/rules/{id}", apiHandler.HandleDeleteRule)
+	mux.HandleFunc("GET /admin/webhooks", apiHandler.HandleListWebhooks)
+	mux.HandleFunc("POST /admin/webhooks", apiHandler.HandleCreateWebhook)
+	mux.HandleFunc("GET /admin/webhooks/{id}", apiHandler.HandleGetWebhook)
+	mux.HandleFunc("DELETE /admin/webhooks/{id}", apiHandler.HandleDeleteWebhook)
+	mux.HandleFunc("GET /admin/webhooks/{id}/deliveries", apiHandler.HandleGetWebhookDeliveries)
+	mux.HandleFunc("POST /admin/webhooks/{id}/enable", apiHandler.HandleEnableWebhook)
+
 	mux.Handle("/swagger/", httpSwagger.WrapHandler)
 
 	// Apply middleware
-	handler := middleware.ChainMiddleware(mux,
+	middlewares := []func(http.Handler) http.Handler{
 		middleware.ErrorHandler,
 		middleware.ContentTypeJSON,
-	)
+	}
+
+	// Record the raw ingested requests for replay
+	var recorder *recording.Recorder
+	if cfg.RecordFile != "" {
+		recorder, err = recording.NewRecorder(cfg.RecordFile, int64(cfg.RecordMaxSize), cfg.RecordMaxFiles)
+		if err != nil {
+			log.Fatalf("Failed to open recording: %v", err)
+		}
+		middlewares = append(middlewares, middleware.Recording(recorder))
+		log.Printf("Recording POST /messages requests to %s", cfg.RecordFile)
+	}
+	handler := middleware.ChainMiddleware(mux, middlewares...)
 
 	// Simple server setup
 	server := &http.Server{
-		Addr:         ":8088",
+		Addr:         cfg.Addr,
 		Handler:      handler,
 		ReadTimeout:  10 * time.Second,
 		WriteTimeout: 10 * time.Second,
 		IdleTimeout:  120 * time.Second,
 	}
 
-	log.Println("Starting Lunar Rocket Tracking API on :8088")
-	log.Fatal(server.ListenAndServe())
+	// Serve the gRPC API from the same repository, rejected messages share the dead-letter store
+	if cfg.GRPCAddr != "" {
+		listener, err := net.Listen("tcp", cfg.GRPCAddr)
+		if err != nil {
+			log.Fatalf("Failed to listen for gRPC on %s: %v", cfg.GRPCAddr, err)
+		}
+		grpcServer := grpc.NewServer()
+		rocketsv1.RegisterRocketServiceServer(grpcServer, grpcapi.NewServer(repository, apiHandler.DeadLetters))
+		go func() {
+			log.Printf("Starting gRPC API on %s", cfg.GRPCAddr)
+			log.Fatal(grpcServer.Serve(listener))
+		}()
+	}
+
+	// Stop accepting requests on SIGINT or SIGTERM and let those in progress finish
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			log.Printf("Failed to shut down: %v", err)
+		}
+	}()
+
+	log.Printf("Starting Lunar Rocket Tracking API on %s (bootstrap policy: %s)", cfg.Addr, cfg.BootstrapPolicy)
+	if err := server.ListenAndServe(); err != http.ErrServerClosed {
+		log.Fatal(err)
+	}
+	<-stopped
+
+	// Close the recording once no request can write to it anymore
+	if recorder != nil {
+		if err := recorder.Close(); err != nil {
+			log.Printf("Failed to close recording: %v", err)
+		}
+	}
+	log.Printf("Server stopped")
 }
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+
+	"lunar-backend-challenge/internal/deadletter"
+	"lunar-backend-challenge/internal/importer"
+	"lunar-backend-challenge/internal/ingest"
+	"lunar-backend-challenge/internal/recording"
+	"lunar-backend-challenge/internal/storage"
+)
+
+func main() {
+	flags := flag.NewFlagSet("replay", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: replay [flags] recording.ndjson ...")
+		fmt.Fprintln(flags.Output(), "Re-sends the POST /messages bodies recorded by a server started with -record-file, in the order of the files.")
+		flags.PrintDefaults()
+	}
+	server := flags.String("server", "", "post to the server at this URL (e.g. http://localhost:8088) instead of applying to a local repository")
+	speed := flags.Float64("speed", 1, "pacing relative to the recording: 1 for the original pace, 10 for ten times faster, 0 for as fast as possible")
+	preserveOrder := flags.Bool("preserve-order", false, "send each request only after the previous one was answered, keeping the original arrival order exactly")
+	concurrency := flags.Int("concurrency", 1, "concurrent senders without -preserve-order")
+	jsonReport := flags.Bool("json", false, "print the final report as JSON")
+	verbose := flags.Bool("verbose", false, "log every message processed by the local repository")
+	flags.Parse(os.Args[1:])
+
+	if flags.NArg() == 0 || *speed < 0 {
+		flags.Usage()
+		os.Exit(2)
+	}
+	records, err := recording.ReadFiles(flags.Args())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to read recording: %v\n", err)
+		os.Exit(1)
+	}
+
+	// The local pipeline logs every message, which drowns the report
+	if !*verbose {
+		log.SetOutput(io.Discard)
+	}
+
+	var repository *storage.RocketRepository
+	replayer := &recording.Replayer{Speed: *speed, PreserveOrder: *preserveOrder, Concurrency: *concurrency}
+	if *server != "" {
+		replayer.Sink = importer.NewHTTPSink(*server)
+	} else {
+		repository = storage.NewRocketRepository()
+		replayer.Sink = importer.RepositorySink{Ingest: ingest.NewService(repository, deadletter.NewStore(deadletter.DefaultCapacity))}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	report := importer.NewReport()
+	err = replayer.Replay(ctx, strings.Join(flags.Args(), ","), records, report)
+
+	if *jsonReport {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		encoder.Encode(report)
+	} else {
+		for _, failure := range report.Failures {
+			fmt.Printf("record %d: %s: %s\n", failure.Line, failure.Outcome, failure.Error)
+		}
+		fmt.Printf("%d of %d records replayed: %s\n", report.Lines, len(records), report)
+		if repository != nil {
+			fmt.Printf("%d rockets\n", len(repository.GetAllRockets()))
+		}
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Replay failed: %v\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// defaultURL is the server used when no URL is configured
+const defaultURL = "http://localhost:8088"
+
+// Config is the server to talk to, read from the config file and overridden by
+// ROCKETCTL_URL and ROCKETCTL_TOKEN, then by the -url and -token flags
+type Config struct {
+	URL   string `yaml:"url"`
+	Token string `yaml:"token"` // Sent as a bearer token
+}
+
+// configPath returns $ROCKETCTL_CONFIG, or rocketctl/config.yaml in the user config directory
+func configPath() string {
+	if path := os.Getenv("ROCKETCTL_CONFIG"); path != "" {
+		return path
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "rocketctl", "config.yaml")
+}
+
+// loadConfig reads the config file, if there is one, and applies the environment
+func loadConfig() (Config, error) {
+	config := Config{URL: defaultURL}
+
+	if path := configPath(); path != "" {
+		data, err := os.ReadFile(path)
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+		case err != nil:
+			return config, err
+		default:
+			if err := yaml.Unmarshal(data, &config); err != nil {
+				return config, fmt.Errorf("invalid config file %s: %w", path, err)
+			}
+		}
+	}
+
+	if url := os.Getenv("ROCKETCTL_URL"); url != "" {
+		config.URL = url
+	}
+	if token := os.Getenv("ROCKETCTL_TOKEN"); token != "" {
+		config.Token = token
+	}
+	return config, nil
+}
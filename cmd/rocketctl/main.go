@@ -0,0 +1,337 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"os/signal"
+	"slices"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"lunar-backend-challenge/internal/api"
+	"lunar-backend-challenge/internal/client"
+	"lunar-backend-challenge/internal/models"
+)
+
+const usage = `Usage: rocketctl <command> [flags] [arguments]
+
+Commands:
+  list              list rockets, with filtering and sorting
+  get <id>          show a rocket
+  watch             print rocket changes as they happen
+  debug <id>        show the messages a rocket is waiting for
+  send              post a message built from flags
+
+Every command accepts -url, -token and -o table|json|yaml. The server and token are read from
+$ROCKETCTL_CONFIG (default: rocketctl/config.yaml in the user config directory, with the keys
+url and token), then from ROCKETCTL_URL and ROCKETCTL_TOKEN, then from the flags.
+Run "rocketctl <command> -h" for the flags of a command.
+`
+
+// command is a subcommand, run with the arguments left after its flags
+type command struct {
+	flags *flag.FlagSet
+	run   func(ctx context.Context, c *client.Client, output string, args []string) error
+}
+
+func main() {
+	if len(os.Args) < 2 || os.Args[1] == "-h" || os.Args[1] == "help" {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	config, err := loadConfig()
+	if err != nil {
+		fail(err)
+	}
+
+	commands := map[string]func() command{
+		"list":  listCommand,
+		"get":   getCommand,
+		"watch": watchCommand,
+		"debug": debugCommand,
+		"send":  sendCommand,
+	}
+	newCommand, exists := commands[os.Args[1]]
+	if !exists {
+		fmt.Fprintf(os.Stderr, "rocketctl: unknown command %q\n\n%s", os.Args[1], usage)
+		os.Exit(2)
+	}
+
+	cmd := newCommand()
+	baseURL := cmd.flags.String("url", config.URL, "server URL")
+	token := cmd.flags.String("token", config.Token, "bearer token")
+	output := cmd.flags.String("o", outputTable, "output format: table, json or yaml")
+	args := parseInterspersed(cmd.flags, os.Args[2:])
+	if err := validOutput(*output); err != nil {
+		fail(err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	if err := cmd.run(ctx, client.New(*baseURL, *token), *output, args); err != nil {
+		fail(err)
+	}
+}
+
+// fail prints an error and exits
+func fail(err error) {
+	fmt.Fprintf(os.Stderr, "rocketctl: %v\n", err)
+	os.Exit(1)
+}
+
+// parseInterspersed parses flags placed before or after the positional arguments,
+// e.g. "get <id> -o json", and returns the positional arguments
+func parseInterspersed(flags *flag.FlagSet, args []string) []string {
+	var positional []string
+	for {
+		flags.Parse(args)
+		args = flags.Args()
+		if len(args) == 0 {
+			return positional
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// newFlagSet creates the flag set of a command
+func newFlagSet(name, arguments, description string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: rocketctl %s [flags] %s\n%s\n", name, arguments, description)
+		flags.PrintDefaults()
+	}
+	return flags
+}
+
+// oneArgument returns the only positional argument of a command
+func oneArgument(args []string, name string) (string, error) {
+	if len(args) != 1 {
+		return "", fmt.Errorf("expected exactly one argument: %s", name)
+	}
+	return args[0], nil
+}
+
+func listCommand() command {
+	flags := newFlagSet("list", "", "Lists rockets, like GET /rockets.")
+	rocketType := flags.String("type", "", "only rockets of this type (case-insensitive)")
+	mission := flags.String("mission", "", "only rockets on this mission (case-insensitive)")
+	status := flags.String("status", "", "only rockets with this lifecycle status")
+	silent := flags.String("silent", "", "only silent (true) or communicating (false) rockets")
+	sortBy := flags.String("sort", "", "sort by id, type, speed, mission, exploded, status or updatedAt")
+	sortOrder := flags.String("order", "", "sort order: asc or desc")
+
+	return command{flags: flags, run: func(ctx context.Context, c *client.Client, output string, args []string) error {
+		rockets, err := c.ListRockets(ctx, listQuery(map[string]string{
+			"type":      *rocketType,
+			"mission":   *mission,
+			"status":    *status,
+			"silent":    *silent,
+			"sortBy":    *sortBy,
+			"sortOrder": *sortOrder,
+		}))
+		if err != nil {
+			return err
+		}
+		return write(os.Stdout, output, rockets, func(w *tabwriter.Writer) {
+			fmt.Fprintln(w, "ID\tTYPE\tSPEED\tMISSION\tSTATUS\tSILENT\tUPDATED")
+			for _, rocket := range rockets {
+				fmt.Fprintf(w, "%s\t%s\t%d\t%s\t%s\t%t\t%s\n", rocket.ID, rocket.Type, rocket.Speed, rocket.Mission, rocket.Status, rocket.Silent, formatTime(rocket.UpdatedAt))
+			}
+		})
+	}}
+}
+
+// listQuery builds the query of GET /rockets from the non-empty parameters
+func listQuery(parameters map[string]string) url.Values {
+	query := url.Values{}
+	for name, value := range parameters {
+		if value != "" {
+			query.Set(name, value)
+		}
+	}
+	return query
+}
+
+func getCommand() command {
+	flags := newFlagSet("get", "<id>", "Shows a rocket, like GET /rockets/{id}.")
+
+	return command{flags: flags, run: func(ctx context.Context, c *client.Client, output string, args []string) error {
+		id, err := oneArgument(args, "the rocket ID")
+		if err != nil {
+			return err
+		}
+		rocket, err := c.GetRocket(ctx, id)
+		if err != nil {
+			return err
+		}
+		return write(os.Stdout, output, rocket, func(w *tabwriter.Writer) {
+			fmt.Fprintf(w, "ID:\t%s\n", rocket.ID)
+			fmt.Fprintf(w, "Type:\t%s\n", rocket.Type)
+			fmt.Fprintf(w, "Speed:\t%d\n", rocket.Speed)
+			fmt.Fprintf(w, "Mission:\t%s\n", rocket.Mission)
+			fmt.Fprintf(w, "Status:\t%s\n", rocket.Status)
+			if rocket.Reason != "" {
+				fmt.Fprintf(w, "Reason:\t%s\n", rocket.Reason)
+			}
+			fmt.Fprintf(w, "Launches:\t%d\n", rocket.LaunchCount)
+			fmt.Fprintf(w, "Partial:\t%t\n", rocket.Partial)
+			fmt.Fprintf(w, "Silent:\t%t\n", rocket.Silent)
+			fmt.Fprintf(w, "Created:\t%s\n", formatTime(rocket.CreatedAt))
+			fmt.Fprintf(w, "Updated:\t%s\n", formatTime(rocket.UpdatedAt))
+			fmt.Fprintf(w, "Last seen:\t%s\n", formatTime(rocket.LastSeen))
+		})
+	}}
+}
+
+func watchCommand() command {
+	flags := newFlagSet("watch", "", "Prints rocket changes until interrupted, from the GraphQL subscription or by polling GET /rockets.")
+	rocketID := flags.String("rocket", "", "only changes of this rocket")
+	mission := flags.String("mission", "", "only changes of rockets on this mission")
+	poll := flags.Bool("poll", false, "poll GET /rockets instead of subscribing")
+	interval := flags.Duration("interval", 2*time.Second, "polling interval")
+
+	return command{flags: flags, run: func(ctx context.Context, c *client.Client, output string, args []string) error {
+		print := func(change client.Change) {
+			if *rocketID != "" && change.RocketID != *rocketID {
+				return
+			}
+			printChange(os.Stdout, output, change)
+		}
+		if output == outputTable {
+			fmt.Println("TIME\tKIND\tROCKET\tMESSAGE\tSPEED\tMISSION\tSTATUS")
+		}
+
+		if *poll {
+			if *interval <= 0 {
+				return fmt.Errorf("the polling interval must be positive")
+			}
+			return c.Poll(ctx, listQuery(map[string]string{"mission": *mission}), *interval, print)
+		}
+		return c.Watch(ctx, *rocketID, *mission, print)
+	}}
+}
+
+// printChange prints a change as soon as it arrives: a table row, a JSON line or a YAML document
+func printChange(w io.Writer, output string, change client.Change) {
+	switch output {
+	case outputJSON:
+		json.NewEncoder(w).Encode(change)
+	case outputYAML:
+		fmt.Fprintln(w, "---")
+		writeYAML(w, change)
+	default:
+		message := "-"
+		if change.MessageType != "" {
+			message = fmt.Sprintf("#%d %s", change.MessageNumber, change.MessageType)
+		}
+		// Rows are not aligned, as a tabwriter would hold them back until the end
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%s\t%s\n", formatTime(change.OccurredAt), change.Kind, change.RocketID, message, change.After.Speed, change.After.Mission, change.After.Status)
+	}
+}
+
+// debugReport is the output of the debug command
+type debugReport struct {
+	*api.DebugInfo
+	MissingMessages []client.Gap `json:"missingMessages"` // Message numbers holding back the pending ones
+}
+
+func debugCommand() command {
+	flags := newFlagSet("debug", "<id>", "Shows the pending messages of a rocket and the gaps holding them back, like GET /debug/rockets/{id}.")
+
+	return command{flags: flags, run: func(ctx context.Context, c *client.Client, output string, args []string) error {
+		id, err := oneArgument(args, "the rocket ID")
+		if err != nil {
+			return err
+		}
+		debugInfo, err := c.GetDebugInfo(ctx, id)
+		if err != nil {
+			return err
+		}
+		report := debugReport{DebugInfo: debugInfo, MissingMessages: client.Gaps(debugInfo.LastProcessedMessage, debugInfo.PendingMessageNumbers)}
+
+		return write(os.Stdout, output, report, func(w *tabwriter.Writer) {
+			pending := make([]string, 0, len(debugInfo.PendingMessageNumbers))
+			for _, number := range slices.Sorted(slices.Values(debugInfo.PendingMessageNumbers)) {
+				pending = append(pending, strconv.Itoa(number))
+			}
+			missing := make([]string, 0, len(report.MissingMessages))
+			for _, gap := range report.MissingMessages {
+				if gap.From == gap.To {
+					missing = append(missing, strconv.Itoa(gap.From))
+				} else {
+					missing = append(missing, fmt.Sprintf("%d-%d", gap.From, gap.To))
+				}
+			}
+			fmt.Fprintf(w, "Rocket:\t%s\n", debugInfo.RocketID)
+			fmt.Fprintf(w, "Processed messages:\t%d\n", debugInfo.ProcessedMessageCount)
+			fmt.Fprintf(w, "Last processed:\t%d\n", debugInfo.LastProcessedMessage)
+			fmt.Fprintf(w, "Pending messages:\t%s\n", formatNumbers(pending))
+			fmt.Fprintf(w, "Missing messages:\t%s\n", formatNumbers(missing))
+			fmt.Fprintf(w, "Conflicts:\t%d\n", debugInfo.ConflictCount)
+			fmt.Fprintf(w, "Anomalies:\t%d\n", debugInfo.AnomalyCount)
+		})
+	}}
+}
+
+func sendCommand() command {
+	flags := newFlagSet("send", "", "Posts a message to /messages.")
+	channel := flags.String("rocket", "", "rocket ID (the message channel)")
+	number := flags.Int("number", 0, "message number")
+	messageType := flags.String("type", "", "message type, e.g. RocketLaunched")
+	messageTime := flags.String("time", "", "message time in RFC 3339 (default now)")
+	rocketType := flags.String("rocket-type", "", "rocket type, for RocketLaunched")
+	speed := flags.Int("speed", 0, "launch speed, for RocketLaunched")
+	mission := flags.String("mission", "", "mission, for RocketLaunched")
+	by := flags.Int("by", 0, "speed change, for RocketSpeedIncreased and RocketSpeedDecreased")
+	reason := flags.String("reason", "", "explosion reason, for RocketExploded")
+	newMission := flags.String("new-mission", "", "new mission, for RocketMissionChanged")
+	payload := flags.String("payload", "", "message content as JSON, replacing the content flags (for custom message types)")
+
+	return command{flags: flags, run: func(ctx context.Context, c *client.Client, output string, args []string) error {
+		msg := &models.RocketMessage{}
+		msg.Metadata.Channel = *channel
+		msg.Metadata.MessageNumber = *number
+		msg.Metadata.MessageType = *messageType
+		msg.Metadata.MessageTime = time.Now()
+		if *messageTime != "" {
+			parsed, err := time.Parse(time.RFC3339Nano, *messageTime)
+			if err != nil {
+				return fmt.Errorf("invalid -time: %w", err)
+			}
+			msg.Metadata.MessageTime = parsed
+		}
+		msg.Message = models.MessageContent{Type: *rocketType, LaunchSpeed: *speed, Mission: *mission, By: *by, Reason: *reason, NewMission: *newMission}
+
+		var body any = msg
+		if *payload != "" {
+			if !json.Valid([]byte(*payload)) {
+				return fmt.Errorf("invalid -payload: not JSON")
+			}
+			body = struct {
+				Metadata any             `json:"metadata"`
+				Message  json.RawMessage `json:"message"`
+			}{msg.Metadata, json.RawMessage(*payload)}
+		}
+
+		response, err := c.SendMessage(ctx, body)
+		if err != nil {
+			return err
+		}
+		return write(os.Stdout, output, response, func(w *tabwriter.Writer) {
+			fmt.Fprintf(w, "Rocket:\t%s\n", response.RocketID)
+			fmt.Fprintf(w, "Message:\t%d\n", response.MessageNumber)
+			fmt.Fprintf(w, "Outcome:\t%s\n", response.Outcome)
+			if response.Reason != "" {
+				fmt.Fprintf(w, "Reason:\t%s\n", response.Reason)
+			}
+		})
+	}}
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+
+	"lunar-backend-challenge/internal/deadletter"
+	"lunar-backend-challenge/internal/importer"
+	"lunar-backend-challenge/internal/ingest"
+	"lunar-backend-challenge/internal/simulator"
+	"lunar-backend-challenge/internal/storage"
+)
+
+func main() {
+	flags := flag.NewFlagSet("simulator", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: simulator [launch] [url] [flags]")
+		fmt.Fprintln(flags.Output(), "Posts the messages of a simulated rocket fleet to url (e.g. http://localhost:8088/messages),")
+		fmt.Fprintln(flags.Output(), "or applies them to a local repository and checks the resulting rockets if url is omitted.")
+		flags.PrintDefaults()
+	}
+	defaults := simulator.DefaultConfig()
+	messageDelay := flags.Duration("message-delay", defaults.MessageDelay, "delay between the messages of each sender")
+	concurrency := flags.Int("concurrency-level", defaults.Concurrency, "number of concurrent senders")
+	rockets := flags.Int("rockets", defaults.Rockets, "rockets in flight at any time; exploded rockets are replaced")
+	messages := flags.Int("messages", 0, "messages to generate, 0 to run until interrupted")
+	explosionRate := flags.Float64("explosion-rate", defaults.ExplosionRate, "chance that a message is the explosion of its rocket")
+	reorderRate := flags.Float64("reorder-rate", 0, "chance that a message is delivered after later ones")
+	duplicateRate := flags.Float64("duplicate-rate", 0, "chance that a message is delivered twice")
+	reorderWindow := flags.Int("reorder-window", defaults.ReorderWindow, "how many later messages a reordered or duplicate message may be delivered after")
+	seed := flags.Uint64("seed", 0, "seed for repeatable runs, 0 for a random one")
+	jsonReport := flags.Bool("json", false, "print the final report as JSON")
+	verbose := flags.Bool("verbose", false, "log every message processed by the local repository")
+
+	// Accept the launch test program's syntax: launch <url> --flag=value
+	args := os.Args[1:]
+	if len(args) > 0 && args[0] == "launch" {
+		args = args[1:]
+	}
+	positional := parseInterspersed(flags, args)
+	if len(positional) > 1 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	config := simulator.Config{
+		Rockets:       *rockets,
+		Messages:      *messages,
+		MessageDelay:  *messageDelay,
+		Concurrency:   *concurrency,
+		ExplosionRate: *explosionRate,
+		ReorderRate:   *reorderRate,
+		DuplicateRate: *duplicateRate,
+		ReorderWindow: *reorderWindow,
+		Seed:          *seed,
+	}
+	if err := config.Validate(); err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid configuration: %v\n", err)
+		os.Exit(2)
+	}
+
+	// The local pipeline logs every message, which drowns the report
+	if !*verbose {
+		log.SetOutput(io.Discard)
+	}
+
+	var repository *storage.RocketRepository
+	var sink importer.Sink
+	if len(positional) == 1 {
+		// The launch test program takes the /messages URL, the HTTP sink the server URL
+		sink = importer.NewHTTPSink(strings.TrimSuffix(strings.TrimSuffix(positional[0], "/"), "/messages"))
+	} else {
+		repository = storage.NewRocketRepository()
+		sink = importer.RepositorySink{Ingest: ingest.NewService(repository, deadletter.NewStore(deadletter.DefaultCapacity))}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	sim := simulator.New(config)
+	report, err := sim.Run(ctx, sink)
+
+	if *jsonReport {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		encoder.Encode(report)
+	} else {
+		fmt.Printf("%d messages generated, %d reordered, %d duplicated, %d sent, %d rejected\n",
+			report.Generated, report.Reordered, report.Duplicated, report.Sent, report.Errors)
+		outcomes := make([]string, 0, len(report.Outcomes))
+		for outcome := range report.Outcomes {
+			outcomes = append(outcomes, outcome)
+		}
+		sort.Strings(outcomes)
+		for _, outcome := range outcomes {
+			fmt.Printf("  %s: %d\n", outcome, report.Outcomes[outcome])
+		}
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Simulation failed: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Without the network in between, every generated message reaches the repository, so its
+	// rockets must match the simulated ones once the run completed
+	if repository != nil && ctx.Err() == nil {
+		mismatches := 0
+		for _, expected := range sim.Rockets() {
+			rocket, exists := repository.GetRocket(expected.ID)
+			if !exists || rocket.Type != expected.Type || rocket.Speed != expected.Speed || rocket.Mission != expected.Mission || rocket.Status != expected.Status {
+				mismatches++
+				fmt.Fprintf(os.Stderr, "Rocket %s: expected %+v, got %+v\n", expected.ID, expected, rocket)
+			}
+		}
+		fmt.Fprintf(os.Stderr, "%d rockets, %d mismatches\n", len(sim.Rockets()), mismatches)
+		if mismatches > 0 {
+			os.Exit(1)
+		}
+	}
+}
+
+// parseInterspersed parses flags placed before or after the positional arguments and returns
+// the positional arguments
+func parseInterspersed(flags *flag.FlagSet, args []string) []string {
+	var positional []string
+	for {
+		flags.Parse(args)
+		args = flags.Args()
+		if len(args) == 0 {
+			return positional
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
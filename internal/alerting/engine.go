@@ -0,0 +1,360 @@
+package alerting
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"lunar-backend-challenge/internal/metrics"
+	"lunar-backend-challenge/internal/models"
+	"lunar-backend-challenge/internal/storage"
+)
+
+// Alert states
+const (
+	StateFiring   = "firing"
+	StateResolved = "resolved"
+)
+
+// DefaultResolvedHistory is the number of resolved alerts kept for inspection
+const DefaultResolvedHistory = 1000
+
+// ErrRuleExists is returned when adding a rule whose ID is already in use
+var ErrRuleExists = errors.New("rule already exists")
+
+// Alert is raised by a rule for a rocket. While firing, repeated matches of the rule
+// update the alert instead of raising a new one.
+type Alert struct {
+	ID          string     `json:"id" example:"fast-rockets/193270a9-c9cf-404a-8f83-838e71d9ae67"`
+	RuleID      string     `json:"ruleId" example:"fast-rockets"`
+	Kind        string     `json:"kind" example:"speed_above"`
+	RocketID    string     `json:"rocketId" example:"193270a9-c9cf-404a-8f83-838e71d9ae67"`
+	State       string     `json:"state" example:"firing"`
+	Message     string     `json:"message" example:"speed 12000 is above 10000"`
+	FiredAt     time.Time  `json:"firedAt" example:"2024-03-14T19:39:05.86337+01:00"`
+	LastSeenAt  time.Time  `json:"lastSeenAt" example:"2024-03-14T19:40:05.86337+01:00"` // Last time the condition matched
+	ResolvedAt  *time.Time `json:"resolvedAt,omitempty" example:"2024-03-14T19:45:12.12345+01:00"`
+	Occurrences int        `json:"occurrences" example:"3"` // Times the condition matched while firing
+}
+
+// Engine evaluates alerting rules on rocket state changes
+type Engine struct {
+	rules         map[string]Rule
+	active        map[string]*Alert    // Firing alerts by alert ID
+	resolved      []Alert              // Recently resolved alerts, oldest first
+	silentSince   map[string]time.Time // When the repository marked each silent rocket silent
+	firedTotal    int
+	resolvedTotal int
+	mutex         sync.Mutex
+}
+
+// NewEngine creates an engine without rules
+func NewEngine() *Engine {
+	return &Engine{
+		rules:       make(map[string]Rule),
+		active:      make(map[string]*Alert),
+		silentSince: make(map[string]time.Time),
+	}
+}
+
+// AddRule adds a rule, failing if a rule with the same ID exists
+func (e *Engine) AddRule(rule Rule) error {
+	if err := rule.Validate(); err != nil {
+		return err
+	}
+
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	if _, exists := e.rules[rule.ID]; exists {
+		return ErrRuleExists
+	}
+	e.rules[rule.ID] = rule
+	return nil
+}
+
+// PutRule adds or replaces a rule and reports whether it was added. Alerts raised by
+// a replaced rule are resolved, as its condition may no longer hold.
+func (e *Engine) PutRule(rule Rule, now time.Time) (bool, error) {
+	if err := rule.Validate(); err != nil {
+		return false, err
+	}
+
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	_, exists := e.rules[rule.ID]
+	if exists {
+		e.resolveRule(rule.ID, now)
+	}
+	e.rules[rule.ID] = rule
+	return !exists, nil
+}
+
+// RemoveRule removes a rule and resolves its alerts, reporting whether the rule existed
+func (e *Engine) RemoveRule(id string, now time.Time) bool {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	if _, exists := e.rules[id]; !exists {
+		return false
+	}
+	delete(e.rules, id)
+	e.resolveRule(id, now)
+	return true
+}
+
+// Rule returns a rule by ID
+func (e *Engine) Rule(id string) (Rule, bool) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	rule, exists := e.rules[id]
+	return rule, exists
+}
+
+// Rules returns every rule ordered by ID
+func (e *Engine) Rules() []Rule {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	rules := make([]Rule, 0, len(e.rules))
+	for _, rule := range e.rules {
+		rules = append(rules, rule)
+	}
+	sort.Slice(rules, func(i, j int) bool { return rules[i].ID < rules[j].ID })
+	return rules
+}
+
+// Active returns the firing alerts, oldest first
+func (e *Engine) Active() []Alert {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	alerts := make([]Alert, 0, len(e.active))
+	for _, alert := range e.active {
+		alerts = append(alerts, *alert)
+	}
+	sort.Slice(alerts, func(i, j int) bool {
+		if !alerts[i].FiredAt.Equal(alerts[j].FiredAt) {
+			return alerts[i].FiredAt.Before(alerts[j].FiredAt)
+		}
+		return alerts[i].ID < alerts[j].ID
+	})
+	return alerts
+}
+
+// Resolved returns the recently resolved alerts, most recent first
+func (e *Engine) Resolved() []Alert {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	alerts := make([]Alert, len(e.resolved))
+	for i, alert := range e.resolved {
+		alerts[len(e.resolved)-1-i] = alert
+	}
+	return alerts
+}
+
+// Observe evaluates the rules on a rocket state change. It is meant to be
+// subscribed to the repository with RocketRepository.Subscribe.
+func (e *Engine) Observe(change storage.StateChange) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	before, after, now := change.Before, change.After, change.At
+
+	// Silence is detected by the repository, so silent alerts agree with the rockets it reports silent.
+	// Going silent or recovering does not change what the other rules look at.
+	switch change.Kind {
+	case storage.ChangeSilent:
+		since := now
+		if after.SilentSince != nil {
+			since = *after.SilentSince
+		}
+		e.silentSince[change.RocketID] = since
+		for _, rule := range e.rules {
+			if rule.Kind == KindSilent && rule.appliesTo(change.RocketID) {
+				e.fireSilent(rule, change.RocketID, since, now)
+			}
+		}
+		return
+
+	case storage.ChangeRecovered:
+		delete(e.silentSince, change.RocketID)
+		for _, rule := range e.rules {
+			if rule.Kind == KindSilent && rule.appliesTo(change.RocketID) {
+				e.resolve(rule.ID, change.RocketID, now)
+			}
+		}
+		return
+
+	// Neither is a conflicting message, which leaves the state untouched
+	case storage.ChangeConflict:
+		for _, rule := range e.rules {
+			if rule.Kind == KindConflict && rule.appliesTo(change.RocketID) {
+				e.fire(rule, change.RocketID, fmt.Sprintf("message %d was received again with a different payload", change.Message.GetMessageNumber()), now)
+			}
+		}
+		return
+	}
+
+	for _, rule := range e.rules {
+		if !rule.appliesTo(change.RocketID) {
+			continue
+		}
+
+		switch rule.Kind {
+		case KindSpeedAbove:
+			if after.Speed > rule.Threshold {
+				e.fire(rule, change.RocketID, fmt.Sprintf("speed %d is above %d", after.Speed, rule.Threshold), now)
+			} else if after.Speed <= rule.Threshold-rule.Hysteresis {
+				e.resolve(rule.ID, change.RocketID, now)
+			}
+
+		case KindExploded:
+			if after.Status == models.RocketStatusExploded {
+				if before == nil || before.Status != models.RocketStatusExploded {
+					e.fire(rule, change.RocketID, "rocket exploded: "+after.Reason, now)
+				}
+			} else {
+				e.resolve(rule.ID, change.RocketID, now)
+			}
+
+		case KindMissionChanged:
+			if before == nil || before.Mission == models.UnknownValue || models.MissionKey(before.Mission) == models.MissionKey(after.Mission) {
+				continue
+			}
+			if expectedMission(rule, after.Mission) {
+				e.resolve(rule.ID, change.RocketID, now)
+			} else {
+				e.fire(rule, change.RocketID, fmt.Sprintf("mission changed from %s to %s", before.Mission, after.Mission), now)
+			}
+
+		case KindConflict:
+			e.resolve(rule.ID, change.RocketID, now)
+		}
+	}
+}
+
+// Evaluate checks time based rules, i.e. silent rules whose silenceAfter delay elapsed
+// since the repository marked a rocket silent
+func (e *Engine) Evaluate(now time.Time) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	for _, rule := range e.rules {
+		if rule.Kind != KindSilent {
+			continue
+		}
+		for rocketID, since := range e.silentSince {
+			if rule.appliesTo(rocketID) {
+				e.fireSilent(rule, rocketID, since, now)
+			}
+		}
+	}
+}
+
+// Run evaluates time based rules every interval until ctx is cancelled
+func (e *Engine) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			e.Evaluate(now)
+		}
+	}
+}
+
+// Collect returns alerting metrics, see metrics.Collector
+func (e *Engine) Collect() []metrics.Sample {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	return []metrics.Sample{
+		{Name: "rocket_alerts_active", Help: "Alerts currently firing", Type: metrics.TypeGauge, Value: float64(len(e.active))},
+		{Name: "rocket_alerts_fired_total", Help: "Alerts that started firing", Type: metrics.TypeCounter, Value: float64(e.firedTotal)},
+		{Name: "rocket_alerts_resolved_total", Help: "Alerts that were resolved", Type: metrics.TypeCounter, Value: float64(e.resolvedTotal)},
+	}
+}
+
+// fire raises an alert or, if it is already firing, records that the condition still holds
+func (e *Engine) fire(rule Rule, rocketID, message string, now time.Time) {
+	id := alertID(rule.ID, rocketID)
+	if alert, firing := e.active[id]; firing {
+		alert.Message = message
+		alert.LastSeenAt = now
+		alert.Occurrences++
+		return
+	}
+
+	e.active[id] = &Alert{
+		ID:          id,
+		RuleID:      rule.ID,
+		Kind:        rule.Kind,
+		RocketID:    rocketID,
+		State:       StateFiring,
+		Message:     message,
+		FiredAt:     now,
+		LastSeenAt:  now,
+		Occurrences: 1,
+	}
+	e.firedTotal++
+}
+
+// fireSilent fires a silent rule for a rocket once it has been silent for the rule's silenceAfter delay
+func (e *Engine) fireSilent(rule Rule, rocketID string, since, now time.Time) {
+	if now.Sub(since) >= time.Duration(rule.SilenceAfter) {
+		e.fire(rule, rocketID, fmt.Sprintf("silent since %s", since.Format(time.RFC3339)), now)
+	}
+}
+
+// resolve resolves the alert of a rule for a rocket, if it is firing
+func (e *Engine) resolve(ruleID, rocketID string, now time.Time) {
+	id := alertID(ruleID, rocketID)
+	alert, firing := e.active[id]
+	if !firing {
+		return
+	}
+	delete(e.active, id)
+
+	resolvedAt := now
+	alert.State = StateResolved
+	alert.ResolvedAt = &resolvedAt
+	e.resolved = append(e.resolved, *alert)
+	if len(e.resolved) > DefaultResolvedHistory {
+		e.resolved = e.resolved[len(e.resolved)-DefaultResolvedHistory:]
+	}
+	e.resolvedTotal++
+}
+
+// resolveRule resolves every alert raised by a rule
+func (e *Engine) resolveRule(ruleID string, now time.Time) {
+	for _, alert := range e.active {
+		if alert.RuleID == ruleID {
+			e.resolve(ruleID, alert.RocketID, now)
+		}
+	}
+}
+
+// alertID identifies the alert of a rule for a rocket, so a condition raises at most one alert at a time
+func alertID(ruleID, rocketID string) string {
+	return ruleID + "/" + rocketID
+}
+
+// expectedMission reports whether a mission is one the rule expects, with any mission unexpected if none are listed
+func expectedMission(rule Rule, mission string) bool {
+	for _, expected := range rule.ExpectedMissions {
+		if models.MissionKey(expected) == models.MissionKey(mission) {
+			return true
+		}
+	}
+	return false
+}
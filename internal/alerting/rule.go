@@ -0,0 +1,119 @@
+package alerting
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// Rule kinds
+const (
+	// KindSpeedAbove fires when a rocket's speed exceeds Threshold and resolves once it
+	// drops to Threshold - Hysteresis or below
+	KindSpeedAbove = "speed_above"
+	// KindExploded fires when a rocket explodes and resolves when it is relaunched
+	KindExploded = "exploded"
+	// KindMissionChanged fires when a rocket changes to a mission outside ExpectedMissions
+	// (any change if the list is empty) and resolves on a change to an expected mission,
+	// so without expected missions it keeps firing until the rule is replaced or removed
+	KindMissionChanged = "mission_changed"
+	// KindSilent fires when the repository marks a rocket silent, or SilenceAfter later if set,
+	// and resolves when the rocket recovers
+	KindSilent = "silent"
+	// KindConflict fires when a rocket sends a message number it already sent with a different
+	// payload and resolves on its next state change. Conflicts are only reported with the
+	// repository's alert conflict policy.
+	KindConflict = "conflict"
+)
+
+// ValidKinds lists every rule kind
+var ValidKinds = map[string]bool{
+	KindSpeedAbove:     true,
+	KindExploded:       true,
+	KindMissionChanged: true,
+	KindSilent:         true,
+	KindConflict:       true,
+}
+
+// Duration is a time.Duration written as a Go duration string (e.g. "5m") in JSON
+type Duration time.Duration
+
+// MarshalJSON writes the duration as a string
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// UnmarshalJSON reads the duration from a string
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return fmt.Errorf("duration must be a string such as \"5m\": %w", err)
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return err
+	}
+	*d = Duration(duration)
+	return nil
+}
+
+// Rule describes a condition on rocket state that raises an alert
+type Rule struct {
+	ID               string   `json:"id" example:"fast-rockets"`
+	Name             string   `json:"name,omitempty" example:"Rocket faster than 10000"`
+	Kind             string   `json:"kind" example:"speed_above"`    // speed_above, exploded, mission_changed, silent or conflict
+	RocketID         string   `json:"rocketId,omitempty" example:""` // Only evaluate this rocket, all rockets if empty
+	Threshold        int      `json:"threshold,omitempty" example:"10000"`
+	Hysteresis       int      `json:"hysteresis,omitempty" example:"500"`
+	ExpectedMissions []string `json:"expectedMissions,omitempty"`
+	SilenceAfter     Duration `json:"silenceAfter,omitempty" swaggertype:"string" example:"5m"`
+}
+
+// Validate checks that the rule is complete for its kind
+func (r *Rule) Validate() error {
+	if r.ID == "" {
+		return fmt.Errorf("rule id is required")
+	}
+	if !ValidKinds[r.Kind] {
+		return fmt.Errorf("unknown rule kind %q, valid kinds are: speed_above, exploded, mission_changed, silent, conflict", r.Kind)
+	}
+	switch r.Kind {
+	case KindSpeedAbove:
+		if r.Threshold <= 0 {
+			return fmt.Errorf("speed_above rule requires a positive threshold")
+		}
+		if r.Hysteresis < 0 || r.Hysteresis > r.Threshold {
+			return fmt.Errorf("hysteresis must be between 0 and the threshold")
+		}
+	case KindSilent:
+		if r.SilenceAfter < 0 {
+			return fmt.Errorf("silenceAfter must not be negative")
+		}
+	}
+	return nil
+}
+
+// appliesTo reports whether the rule evaluates the given rocket
+func (r *Rule) appliesTo(rocketID string) bool {
+	return r.RocketID == "" || r.RocketID == rocketID
+}
+
+// LoadRules reads a JSON array of rules from a file
+func LoadRules(path string) ([]Rule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var rules []Rule
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("invalid rules file %s: %w", path, err)
+	}
+	for i := range rules {
+		if err := rules[i].Validate(); err != nil {
+			return nil, fmt.Errorf("invalid rule %d in %s: %w", i, path, err)
+		}
+	}
+	return rules, nil
+}
@@ -0,0 +1,155 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"lunar-backend-challenge/internal/alerting"
+	"lunar-backend-challenge/internal/errors"
+	"lunar-backend-challenge/internal/middleware"
+)
+
+// HandleGetAlerts returns alerts raised by the alerting rules
+// @Summary List alerts
+// @Description Retrieves firing alerts, oldest first, or recently resolved alerts with state=resolved, most recent first
+// @Tags Alerts
+// @Produce json
+// @Param state query string false "firing or resolved" default(firing)
+// @Success 200 {array} alerting.Alert "Alerts"
+// @Failure 400 {object} errors.BadRequestError "Invalid state"
+// @Router /alerts [get]
+func (h *ApiHandler) HandleGetAlerts(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Query().Get("state") {
+	case "", alerting.StateFiring:
+		middleware.WriteSuccessResponse(w, h.Alerts.Active())
+	case alerting.StateResolved:
+		middleware.WriteSuccessResponse(w, h.Alerts.Resolved())
+	default:
+		middleware.WriteErrorResponse(w, errors.NewAPIError(http.StatusBadRequest, "Invalid state", "Valid states are: firing, resolved"))
+	}
+}
+
+// HandleListRules returns the alerting rules
+// @Summary List alerting rules
+// @Description Retrieves every alerting rule ordered by ID
+// @Tags Admin
+// @Produce json
+// @Success 200 {array} alerting.Rule "Alerting rules"
+// @Router /admin/rules [get]
+func (h *ApiHandler) HandleListRules(w http.ResponseWriter, r *http.Request) {
+	middleware.WriteSuccessResponse(w, h.Alerts.Rules())
+}
+
+// HandleGetRule returns a single alerting rule
+// @Summary Get alerting rule
+// @Tags Admin
+// @Produce json
+// @Param id path string true "Rule ID"
+// @Success 200 {object} alerting.Rule "Alerting rule"
+// @Failure 404 {object} errors.NotFoundError "Rule not found"
+// @Router /admin/rules/{id} [get]
+func (h *ApiHandler) HandleGetRule(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	rule, exists := h.Alerts.Rule(id)
+	if !exists {
+		middleware.WriteErrorResponse(w, ruleNotFound(id))
+		return
+	}
+
+	middleware.WriteSuccessResponse(w, rule)
+}
+
+// HandleCreateRule adds an alerting rule
+// @Summary Create alerting rule
+// @Description Adds a rule evaluated on every rocket state change. Rules added here are not written back to the rules file.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param rule body alerting.Rule true "Rule to add"
+// @Success 200 {object} alerting.Rule "Rule added"
+// @Failure 400 {object} errors.BadRequestError "Invalid rule"
+// @Failure 409 {object} errors.APIError "Rule already exists"
+// @Router /admin/rules [post]
+func (h *ApiHandler) HandleCreateRule(w http.ResponseWriter, r *http.Request) {
+	rule, err := decodeRule(r)
+	if err != nil {
+		middleware.WriteErrorResponse(w, err)
+		return
+	}
+
+	if err := h.Alerts.AddRule(rule); err == alerting.ErrRuleExists {
+		middleware.WriteErrorResponse(w, errors.NewAPIError(http.StatusConflict, "Rule already exists", "A rule with ID "+rule.ID+" already exists, use PUT to replace it"))
+		return
+	} else if err != nil {
+		middleware.WriteErrorResponse(w, errors.NewAPIError(http.StatusBadRequest, "Invalid rule", err.Error()))
+		return
+	}
+
+	middleware.WriteSuccessResponse(w, rule)
+}
+
+// HandlePutRule adds or replaces an alerting rule
+// @Summary Replace alerting rule
+// @Description Adds or replaces the rule with the given ID. Alerts raised by a replaced rule are resolved.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Rule ID"
+// @Param rule body alerting.Rule true "Rule"
+// @Success 200 {object} alerting.Rule "Rule stored"
+// @Failure 400 {object} errors.BadRequestError "Invalid rule"
+// @Router /admin/rules/{id} [put]
+func (h *ApiHandler) HandlePutRule(w http.ResponseWriter, r *http.Request) {
+	rule, err := decodeRule(r)
+	if err != nil {
+		middleware.WriteErrorResponse(w, err)
+		return
+	}
+
+	// The path decides which rule is replaced
+	rule.ID = r.PathValue("id")
+	if _, err := h.Alerts.PutRule(rule, time.Now()); err != nil {
+		middleware.WriteErrorResponse(w, errors.NewAPIError(http.StatusBadRequest, "Invalid rule", err.Error()))
+		return
+	}
+
+	middleware.WriteSuccessResponse(w, rule)
+}
+
+// HandleDeleteRule removes an alerting rule
+// @Summary Delete alerting rule
+// @Description Removes a rule and resolves the alerts it raised
+// @Tags Admin
+// @Produce json
+// @Param id path string true "Rule ID"
+// @Success 200 {object} map[string]string "Rule deleted"
+// @Failure 404 {object} errors.NotFoundError "Rule not found"
+// @Router /admin/rules/{id} [delete]
+func (h *ApiHandler) HandleDeleteRule(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	if !h.Alerts.RemoveRule(id, time.Now()) {
+		middleware.WriteErrorResponse(w, ruleNotFound(id))
+		return
+	}
+
+	middleware.WriteSuccessResponse(w, map[string]string{
+		"status": "deleted",
+		"id":     id,
+	})
+}
+
+// decodeRule reads a rule from the request body
+func decodeRule(r *http.Request) (alerting.Rule, error) {
+	var rule alerting.Rule
+	if err := json.NewDecoder(r.Body).Decode(&rule); err != nil {
+		return rule, errors.NewAPIError(http.StatusBadRequest, "Invalid JSON format", err.Error())
+	}
+	return rule, nil
+}
+
+func ruleNotFound(id string) errors.APIError {
+	return errors.NewAPIError(http.StatusNotFound, "Rule not found", "No alerting rule found with ID: "+id)
+}
@@ -0,0 +1,125 @@
+package api
+
+import (
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"lunar-backend-challenge/internal/deadletter"
+	"lunar-backend-challenge/internal/errors"
+	"lunar-backend-challenge/internal/middleware"
+)
+
+// HandleListDeadLetters returns rejected messages held in the dead-letter store
+// @Summary List dead-lettered messages
+// @Description Retrieves rejected messages with their rejection reason, oldest first
+// @Tags Admin
+// @Produce json
+// @Param channel query string false "Only entries for this rocket channel"
+// @Param reason query string false "Only entries with this reason (invalid_json, validation_failed, processing_failed, conflict, clock_skew)"
+// @Success 200 {array} deadletter.Entry "Dead-lettered messages"
+// @Failure 400 {object} errors.BadRequestError "Invalid reason filter"
+// @Router /admin/dead-letters [get]
+func (h *ApiHandler) HandleListDeadLetters(w http.ResponseWriter, r *http.Request) {
+	filter := deadletter.Filter{
+		Channel: r.URL.Query().Get("channel"),
+		Reason:  r.URL.Query().Get("reason"),
+	}
+
+	if filter.Reason != "" && !slices.Contains(deadletter.ValidReasons, filter.Reason) {
+		middleware.WriteErrorResponse(w, errors.NewAPIError(
+			http.StatusBadRequest,
+			"Invalid reason filter",
+			"Valid reasons are: "+strings.Join(deadletter.ValidReasons, ", "),
+		))
+		return
+	}
+
+	middleware.WriteSuccessResponse(w, h.DeadLetters.List(filter))
+}
+
+// HandleGetDeadLetter returns a single dead-lettered message
+// @Summary Get dead-lettered message
+// @Description Retrieves a rejected message including its raw body
+// @Tags Admin
+// @Produce json
+// @Param id path string true "Dead-letter entry ID"
+// @Success 200 {object} deadletter.Entry "Dead-lettered message"
+// @Failure 404 {object} errors.NotFoundError "Entry not found"
+// @Router /admin/dead-letters/{id} [get]
+func (h *ApiHandler) HandleGetDeadLetter(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	entry, exists := h.DeadLetters.Get(id)
+	if !exists {
+		middleware.WriteErrorResponse(w, deadLetterNotFound(id))
+		return
+	}
+
+	middleware.WriteSuccessResponse(w, entry)
+}
+
+// HandleReplayDeadLetter processes a dead-lettered message again
+// @Summary Replay dead-lettered message
+// @Description Re-processes a rejected message. An optional request body replaces the stored message (edit-and-replay).
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Dead-letter entry ID"
+// @Param message body models.RocketMessage false "Edited message to replay instead of the stored one"
+// @Success 200 {object} MessageResponse "Message replayed"
+// @Failure 400 {object} errors.BadRequestError "Replayed message was rejected again"
+// @Failure 404 {object} errors.NotFoundError "Entry not found"
+// @Router /admin/dead-letters/{id}/replay [post]
+func (h *ApiHandler) HandleReplayDeadLetter(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	entry, exists := h.DeadLetters.Get(id)
+	if !exists {
+		middleware.WriteErrorResponse(w, deadLetterNotFound(id))
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		middleware.WriteErrorResponse(w, errors.NewAPIError(http.StatusBadRequest, "Invalid JSON format", err.Error()))
+		return
+	}
+
+	message, result, err := h.ingestService().Replay(entry, body, time.Now())
+	if err != nil {
+		middleware.WriteErrorResponse(w, err)
+		return
+	}
+
+	middleware.WriteSuccessResponse(w, newMessageResponse(message, result))
+}
+
+// HandleDiscardDeadLetter removes a dead-lettered message without replaying it
+// @Summary Discard dead-lettered message
+// @Description Removes a rejected message from the dead-letter store
+// @Tags Admin
+// @Produce json
+// @Param id path string true "Dead-letter entry ID"
+// @Success 200 {object} map[string]string "Entry discarded"
+// @Failure 404 {object} errors.NotFoundError "Entry not found"
+// @Router /admin/dead-letters/{id} [delete]
+func (h *ApiHandler) HandleDiscardDeadLetter(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	if !h.DeadLetters.Discard(id) {
+		middleware.WriteErrorResponse(w, deadLetterNotFound(id))
+		return
+	}
+
+	middleware.WriteSuccessResponse(w, map[string]string{
+		"status": "discarded",
+		"id":     id,
+	})
+}
+
+func deadLetterNotFound(id string) errors.APIError {
+	return errors.NewAPIError(http.StatusNotFound, "Dead letter not found", "No dead-lettered message found with ID: "+id)
+}
@@ -0,0 +1,64 @@
+package api
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"lunar-backend-challenge/internal/errors"
+	"lunar-backend-challenge/internal/export"
+	"lunar-backend-challenge/internal/middleware"
+	"lunar-backend-challenge/internal/validation"
+)
+
+// HandleGetRocketEvents returns the message history of a rocket
+// @Summary Get message history of a rocket
+// @Description Retrieves the most recent messages processed in sequence for a rocket, with the speed, mission and status each left the rocket in. Pick CSV or NDJSON with the format parameter or the Accept header.
+// @Tags Rockets
+// @Produce json
+// @Produce text/csv
+// @Produce application/x-ndjson
+// @Param id path string true "Rocket ID" example:"193270a9-c9cf-404a-8f83-838e71d9ae67"
+// @Param format query string false "Response format (json, csv, ndjson), overrides the Accept header"
+// @Success 200 {array} storage.MessageEvent "Message history, oldest first"
+// @Failure 400 {object} errors.BadRequestError "Invalid rocket ID or format"
+// @Failure 404 {object} errors.NotFoundError "Rocket not found"
+// @Router /rockets/{id}/events [get]
+func (h *ApiHandler) HandleGetRocketEvents(w http.ResponseWriter, r *http.Request) {
+	// Extract rocket ID from URL path parameter
+	rocketID := r.PathValue("id")
+
+	// Validate rocket ID
+	if err := validation.ValidateRocketID(rocketID); err != nil {
+		middleware.WriteErrorResponse(w, err)
+		return
+	}
+
+	format, err := export.Negotiate(r)
+	if err != nil {
+		middleware.WriteErrorResponse(w, errors.NewAPIError(http.StatusBadRequest, "Invalid format", err.Error()))
+		return
+	}
+
+	events, exists := h.Repository.GetEvents(rocketID)
+	if !exists {
+		middleware.WriteErrorResponse(w, errors.NewAPIError(http.StatusNotFound, "Rocket not found", "No rocket found with ID: "+rocketID))
+		return
+	}
+
+	writeList(w, format, events)
+}
+
+// writeList writes a list response in the negotiated format. CSV and NDJSON are streamed.
+func writeList[T any](w http.ResponseWriter, format export.Format, records []T) {
+	if format == export.FormatJSON {
+		middleware.WriteSuccessResponse(w, records)
+		return
+	}
+	// Large exports may take longer than the server's write timeout
+	http.NewResponseController(w).SetWriteDeadline(time.Time{})
+	// The status is already sent, so a failure can only be logged
+	if err := export.Write(w, format, records); err != nil {
+		log.Printf("Failed to write %s response: %v", format, err)
+	}
+}
@@ -1,20 +1,34 @@
 package api
 
 import (
-	"encoding/json"
+	"io"
 	"log"
 	"net/http"
+	"strings"
+	"time"
 
+	"lunar-backend-challenge/internal/alerting"
+	"lunar-backend-challenge/internal/deadletter"
 	"lunar-backend-challenge/internal/errors"
+	"lunar-backend-challenge/internal/export"
+	"lunar-backend-challenge/internal/filtering"
+	"lunar-backend-challenge/internal/ingest"
+	"lunar-backend-challenge/internal/lifecycle"
+	"lunar-backend-challenge/internal/metrics"
 	"lunar-backend-challenge/internal/middleware"
 	"lunar-backend-challenge/internal/models"
 	"lunar-backend-challenge/internal/sorting"
 	"lunar-backend-challenge/internal/storage"
 	"lunar-backend-challenge/internal/validation"
+	"lunar-backend-challenge/internal/webhooks"
 )
 
 type ApiHandler struct {
-	Repository *storage.RocketRepository
+	Repository  *storage.RocketRepository
+	DeadLetters *deadletter.Store
+	Alerts      *alerting.Engine
+	Webhooks    *webhooks.Dispatcher
+	Metrics     *metrics.Registry
 }
 
 // MessageResponse represents the response for message processing
@@ -23,22 +37,78 @@ type MessageResponse struct {
 	Message       string `json:"message" example:"Message processed successfully"`
 	RocketID      string `json:"rocketId" example:"193270a9-c9cf-404a-8f83-838e71d9ae67"`
 	MessageNumber int    `json:"messageNumber" example:"1"`
+	Outcome       string `json:"outcome" example:"applied"`                  // applied, buffered, duplicate, conflict or ignored
+	Reason        string `json:"reason,omitempty" example:"rocket exploded"` // Why the message was buffered, a duplicate, a conflict or ignored
+}
+
+// outcomeMessages describes each accepted outcome for the response message
+var outcomeMessages = map[storage.Outcome]string{
+	storage.OutcomeApplied:   "Message processed successfully",
+	storage.OutcomeBuffered:  "Message buffered until earlier messages arrive",
+	storage.OutcomeDuplicate: "Duplicate message ignored",
+	storage.OutcomeConflict:  "Conflicting duplicate message ignored",
+	storage.OutcomeIgnored:   "Message accounted for but had no effect",
+}
+
+// newMessageResponse builds the response for an accepted message
+func newMessageResponse(message *models.RocketMessage, result storage.ProcessResult) MessageResponse {
+	return MessageResponse{
+		Status:        "success",
+		Message:       outcomeMessages[result.Outcome],
+		RocketID:      message.GetChannel(),
+		MessageNumber: message.GetMessageNumber(),
+		Outcome:       string(result.Outcome),
+		Reason:        result.Reason,
+	}
 }
 
 // DebugInfo provides debugging information about message processing
 type DebugInfo struct {
-	RocketID              string `json:"rocketId" example:"193270a9-c9cf-404a-8f83-838e71d9ae67"`
-	ProcessedMessageCount int    `json:"processedMessageCount" example:"5"`
-	PendingMessageCount   int    `json:"pendingMessageCount" example:"2"`
-	PendingMessageNumbers []int  `json:"pendingMessageNumbers" example:"1,2,3"`
-	LastProcessedMessage  int    `json:"lastProcessedMessage" example:"6"`
+	RocketID              string             `json:"rocketId" example:"193270a9-c9cf-404a-8f83-838e71d9ae67"`
+	ProcessedMessageCount int                `json:"processedMessageCount" example:"5"`
+	PendingMessageCount   int                `json:"pendingMessageCount" example:"2"`
+	PendingMessageNumbers []int              `json:"pendingMessageNumbers" example:"1,2,3"`
+	LastProcessedMessage  int                `json:"lastProcessedMessage" example:"6"`
+	ConflictCount         int                `json:"conflictCount" example:"0"`
+	Conflicts             []storage.Conflict `json:"conflicts,omitempty"` // Recent messages that reused an accepted message number with a different payload
+	AnomalyCount          int                `json:"anomalyCount" example:"0"`
+	Anomalies             []storage.Anomaly  `json:"anomalies,omitempty"` // Recent messages whose messageTime disagreed with the server clock or their number
+	Lag                   *storage.LagStats  `json:"lag,omitempty"`       // How far behind real time the rocket state is
+}
+
+// LaunchHistory lists the launch generations of a rocket
+type LaunchHistory struct {
+	RocketID    string                    `json:"rocketId" example:"193270a9-c9cf-404a-8f83-838e71d9ae67"`
+	LaunchCount int                       `json:"launchCount" example:"2"`
+	Launches    []models.LaunchGeneration `json:"launches"`
 }
 
 // NewAPIHandler creates a new API handler
 func NewAPIHandler() *ApiHandler {
-	return &ApiHandler{
-		Repository: storage.NewRocketRepository(),
+	return NewAPIHandlerWithRepository(storage.NewRocketRepository())
+}
+
+// NewAPIHandlerWithRepository creates a new API handler serving the given repository
+func NewAPIHandlerWithRepository(repository *storage.RocketRepository) *ApiHandler {
+	handler := &ApiHandler{
+		Repository:  repository,
+		DeadLetters: deadletter.NewStore(deadletter.DefaultCapacity),
+		Alerts:      alerting.NewEngine(),
+		Webhooks:    webhooks.NewDispatcher(webhooks.DefaultOptions()),
+		Metrics:     metrics.NewRegistry(),
 	}
+	repository.Subscribe(handler.Alerts.Observe)
+	repository.Subscribe(handler.Webhooks.Observe)
+	handler.Metrics.Register(repository)
+	handler.Metrics.Register(handler.DeadLetters)
+	handler.Metrics.Register(handler.Alerts)
+	handler.Metrics.Register(handler.Webhooks)
+	return handler
+}
+
+// ingestService returns the ingestion pipeline bound to the handler's repository and dead-letter store
+func (h *ApiHandler) ingestService() *ingest.Service {
+	return ingest.NewService(h.Repository, h.DeadLetters)
 }
 
 // HandleMessage processes incoming rocket messages
@@ -50,52 +120,25 @@ func NewAPIHandler() *ApiHandler {
 // @Param message body models.RocketMessage true "Rocket message to process"
 // @Success 200 {object} MessageResponse "Message processed successfully"
 // @Failure 400 {object} errors.BadRequestError "Invalid request format or validation error"
+// @Failure 409 {object} errors.APIError "Message number already accepted with a different payload (reject conflict policy)"
 // @Failure 422 {object} errors.MessageProcessingError "Message processing failed"
 // @Router /messages [post]
 func (h *ApiHandler) HandleMessage(w http.ResponseWriter, r *http.Request) {
-	var message models.RocketMessage
-
-	// Decode JSON
-	if err := json.NewDecoder(r.Body).Decode(&message); err != nil {
-		log.Printf("Failed to decode JSON: %v", err)
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		log.Printf("Failed to read request body: %v", err)
 		middleware.WriteErrorResponse(w, errors.NewAPIError(http.StatusBadRequest, "Invalid JSON format", err.Error()))
 		return
 	}
 
-	// Validate message
-	if err := validation.ValidateRocketMessage(&message); err != nil {
-		log.Printf("Message validation failed: %v", err)
+	// Decode, validate and process the message; rejected messages end up in the dead-letter store
+	message, result, err := h.ingestService().Ingest(body, time.Now())
+	if err != nil {
 		middleware.WriteErrorResponse(w, err)
 		return
 	}
 
-	// Log incoming message for debugging
-	log.Printf("Received message: Channel=%s, MsgNum=%d, Type=%s",
-		message.GetChannel(), message.GetMessageNumber(), message.GetMessageType())
-
-	// Process the message
-	success := h.Repository.ProcessMessage(&message)
-	if !success {
-		processingErr := errors.NewMessageProcessingError(
-			message.GetChannel(),
-			message.GetMessageNumber(),
-			message.GetMessageType(),
-			"Message processing failed - may be duplicate, out-of-order, or invalid state transition",
-		)
-		log.Printf("Failed to process message: %v", processingErr)
-		middleware.WriteErrorResponse(w, processingErr)
-		return
-	}
-
-	log.Printf("Successfully processed message: Channel=%s, MsgNum=%d, Type=%s",
-		message.GetChannel(), message.GetMessageNumber(), message.GetMessageType())
-
-	middleware.WriteSuccessResponse(w, map[string]interface{}{
-		"status":        "success",
-		"message":       "Message processed successfully",
-		"rocketId":      message.GetChannel(),
-		"messageNumber": message.GetMessageNumber(),
-	})
+	middleware.WriteSuccessResponse(w, newMessageResponse(message, result))
 }
 
 // HandleGetRocket returns a specific rocket by ID
@@ -128,15 +171,61 @@ func (h *ApiHandler) HandleGetRocket(w http.ResponseWriter, r *http.Request) {
 	middleware.WriteSuccessResponse(w, rocket)
 }
 
-// HandleGetRockets returns all rockets with optional sorting
+// HandleGetRocketLaunches returns the launch generations of a specific rocket
+// @Summary Get launch history of a rocket
+// @Description Retrieves every launch of a rocket, including relaunches, with how each flight ended
+// @Tags Rockets
+// @Produce json
+// @Param id path string true "Rocket ID" example:"193270a9-c9cf-404a-8f83-838e71d9ae67"
+// @Success 200 {object} LaunchHistory "Launch history"
+// @Failure 400 {object} errors.BadRequestError "Invalid rocket ID format"
+// @Failure 404 {object} errors.NotFoundError "Rocket not found"
+// @Router /rockets/{id}/launches [get]
+func (h *ApiHandler) HandleGetRocketLaunches(w http.ResponseWriter, r *http.Request) {
+	// Extract rocket ID from URL path parameter
+	rocketID := r.PathValue("id")
+
+	// Validate rocket ID
+	if err := validation.ValidateRocketID(rocketID); err != nil {
+		middleware.WriteErrorResponse(w, err)
+		return
+	}
+
+	// Get rocket from repository
+	rocket, exists := h.Repository.GetRocket(rocketID)
+	if !exists {
+		middleware.WriteErrorResponse(w, errors.NewAPIError(http.StatusNotFound, "Rocket not found", "No rocket found with ID: "+rocketID))
+		return
+	}
+
+	launches := rocket.Launches
+	if launches == nil {
+		launches = []models.LaunchGeneration{}
+	}
+
+	middleware.WriteSuccessResponse(w, LaunchHistory{
+		RocketID:    rocket.ID,
+		LaunchCount: rocket.LaunchCount,
+		Launches:    launches,
+	})
+}
+
+// HandleGetRockets returns all rockets with optional filtering and sorting
 // @Summary List all rockets
-// @Description Retrieves a list of all rockets with their current state, with optional sorting
+// @Description Retrieves a list of all rockets with their current state, with optional filtering and sorting. Pick CSV (with a header row of the summary fields) or NDJSON with the format parameter or the Accept header.
 // @Tags Rockets
 // @Produce json
-// @Param sortBy query string false "Sort field (id, type, speed, mission, exploded, updatedAt)" default(id)
+// @Produce text/csv
+// @Produce application/x-ndjson
+// @Param type query string false "Only rockets of this type (case-insensitive)"
+// @Param mission query string false "Only rockets on this mission (case-insensitive)"
+// @Param status query string false "Only rockets with this lifecycle status"
+// @Param silent query bool false "Only rockets that are (true) or are not (false) silent"
+// @Param sortBy query string false "Sort field (id, type, speed, mission, exploded, status, updatedAt)" default(id)
 // @Param sortOrder query string false "Sort order (asc, desc)" default(asc)
+// @Param format query string false "Response format (json, csv, ndjson), overrides the Accept header"
 // @Success 200 {array} models.RocketSummary "List of rockets"
-// @Failure 400 {object} errors.BadRequestError "Invalid sorting parameters"
+// @Failure 400 {object} errors.BadRequestError "Invalid filter, sorting or format parameters"
 // @Router /rockets [get]
 func (h *ApiHandler) HandleGetRockets(w http.ResponseWriter, r *http.Request) {
 	// Parse query parameters for sorting
@@ -148,7 +237,7 @@ func (h *ApiHandler) HandleGetRockets(w http.ResponseWriter, r *http.Request) {
 		middleware.WriteErrorResponse(w, errors.NewAPIError(
 			http.StatusBadRequest,
 			"Invalid sort field",
-			"Valid sort fields are: id, type, speed, mission, exploded, updatedAt",
+			"Valid sort fields are: id, type, speed, mission, exploded, status, updatedAt",
 		))
 		return
 	}
@@ -163,13 +252,41 @@ func (h *ApiHandler) HandleGetRockets(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	filter, err := rocketFilter(r)
+	if err != nil {
+		middleware.WriteErrorResponse(w, err)
+		return
+	}
+
+	format, err := export.Negotiate(r)
+	if err != nil {
+		middleware.WriteErrorResponse(w, errors.NewAPIError(http.StatusBadRequest, "Invalid format", err.Error()))
+		return
+	}
+
 	// Get rockets from repository
-	rockets := h.Repository.GetAllRockets()
+	rockets := filtering.FilterRockets(h.Repository.GetAllRockets(), filter)
 
 	// Apply sorting
 	sortedRockets := sorting.SortRockets(rockets, sortBy, sortOrder)
 
-	middleware.WriteSuccessResponse(w, sortedRockets)
+	writeList(w, format, sortedRockets)
+}
+
+// rocketFilter reads and validates the rocket filter query parameters
+func rocketFilter(r *http.Request) (filtering.Filter, error) {
+	filter := filtering.FromQuery(r.URL.Query())
+	if !filtering.ValidateStatus(filter.Status) {
+		return filter, errors.NewAPIError(
+			http.StatusBadRequest,
+			"Invalid status filter",
+			"Valid statuses are: "+strings.Join(lifecycle.Statuses, ", "),
+		)
+	}
+	if !filtering.ValidateSilent(filter.Silent) {
+		return filter, errors.NewAPIError(http.StatusBadRequest, "Invalid silent filter", "Valid values are: true, false")
+	}
+	return filter, nil
 }
 
 // HandleDebugRocket returns debug information for a specific rocket
@@ -201,6 +318,9 @@ func (h *ApiHandler) HandleDebugRocket(w http.ResponseWriter, r *http.Request) {
 
 	// Get debug information
 	processedCount, pendingMessages := h.Repository.GetDebugInfo(rocketID)
+	conflicts := h.Repository.GetConflicts(rocketID)
+	anomalies := h.Repository.GetAnomalies(rocketID)
+	lag, hasLag := h.Repository.GetLag(rocketID)
 
 	debugInfo := DebugInfo{
 		RocketID:              rocketID,
@@ -208,6 +328,13 @@ func (h *ApiHandler) HandleDebugRocket(w http.ResponseWriter, r *http.Request) {
 		PendingMessageCount:   len(pendingMessages),
 		PendingMessageNumbers: pendingMessages,
 		LastProcessedMessage:  rocket.LastProcessedMessageNumber,
+		ConflictCount:         len(conflicts),
+		Conflicts:             conflicts,
+		AnomalyCount:          len(anomalies),
+		Anomalies:             anomalies,
+	}
+	if hasLag {
+		debugInfo.Lag = &lag
 	}
 
 	middleware.WriteSuccessResponse(w, debugInfo)
@@ -229,6 +356,11 @@ func (h *ApiHandler) HandleDebugAll(w http.ResponseWriter, r *http.Request) {
 		debugInfos[i] = DebugInfo{
 			RocketID:             rocket.ID,
 			LastProcessedMessage: fullRocket.LastProcessedMessageNumber,
+			ConflictCount:        len(h.Repository.GetConflicts(rocket.ID)),
+			AnomalyCount:         len(h.Repository.GetAnomalies(rocket.ID)),
+		}
+		if lag, exists := h.Repository.GetLag(rocket.ID); exists {
+			debugInfos[i].Lag = &lag
 		}
 	}
 
@@ -0,0 +1,22 @@
+package api
+
+import (
+	"log"
+	"net/http"
+)
+
+// HandleMetrics exposes service metrics in the Prometheus text format
+// @Summary Service metrics
+// @Description Exposes metrics such as dead-letter store depth in the Prometheus text exposition format
+// @Tags Monitoring
+// @Produce plain
+// @Success 200 {string} string "Metrics"
+// @Router /metrics [get]
+func (h *ApiHandler) HandleMetrics(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	w.WriteHeader(http.StatusOK)
+
+	if err := h.Metrics.WritePrometheus(w); err != nil {
+		log.Printf("Failed to write metrics: %v", err)
+	}
+}
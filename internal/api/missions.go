@@ -0,0 +1,46 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+
+	"lunar-backend-challenge/internal/errors"
+	"lunar-backend-challenge/internal/middleware"
+)
+
+// HandleGetMissions returns every mission rockets have been assigned to
+// @Summary List missions
+// @Description Retrieves every mission with its current, total and assignment counts. Mission names are matched case-insensitively.
+// @Tags Missions
+// @Produce json
+// @Success 200 {array} models.MissionSummary "List of missions"
+// @Router /missions [get]
+func (h *ApiHandler) HandleGetMissions(w http.ResponseWriter, r *http.Request) {
+	middleware.WriteSuccessResponse(w, h.Repository.GetMissions())
+}
+
+// HandleGetMission returns the rockets that are and were assigned to a mission
+// @Summary Get mission by name
+// @Description Retrieves current rockets and historical assignment intervals of a mission, from launch or mission change until the next change, explosion or relaunch
+// @Tags Missions
+// @Produce json
+// @Param name path string true "Mission name (case-insensitive)" example:"ARTEMIS"
+// @Success 200 {object} models.Mission "Mission details"
+// @Failure 400 {object} errors.BadRequestError "Missing mission name"
+// @Failure 404 {object} errors.NotFoundError "Mission not found"
+// @Router /missions/{name} [get]
+func (h *ApiHandler) HandleGetMission(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	if strings.TrimSpace(name) == "" {
+		middleware.WriteErrorResponse(w, errors.NewAPIError(http.StatusBadRequest, "Invalid mission name", "Mission name cannot be empty"))
+		return
+	}
+
+	mission, exists := h.Repository.GetMission(name)
+	if !exists {
+		middleware.WriteErrorResponse(w, errors.NewAPIError(http.StatusNotFound, "Mission not found", "No rocket was ever assigned to mission: "+name))
+		return
+	}
+
+	middleware.WriteSuccessResponse(w, mission)
+}
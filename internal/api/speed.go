@@ -0,0 +1,88 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"lunar-backend-challenge/internal/errors"
+	"lunar-backend-challenge/internal/middleware"
+	"lunar-backend-challenge/internal/storage"
+	"lunar-backend-challenge/internal/validation"
+)
+
+// SpeedSeries is the downsampled speed telemetry of a rocket
+type SpeedSeries struct {
+	RocketID string                `json:"rocketId" example:"193270a9-c9cf-404a-8f83-838e71d9ae67"`
+	Step     string                `json:"step,omitempty" example:"1m0s"`
+	Buckets  []storage.SpeedBucket `json:"buckets"`
+}
+
+// HandleGetRocketSpeed returns the speed history of a rocket for charting
+// @Summary Get speed history of a rocket
+// @Description Retrieves speed points recorded on launches and speed changes, downsampled into buckets of `step` width with min, max, avg and last speed
+// @Tags Rockets
+// @Produce json
+// @Param id path string true "Rocket ID" example:"193270a9-c9cf-404a-8f83-838e71d9ae67"
+// @Param from query string false "Only points with a message time at or after this RFC 3339 time"
+// @Param to query string false "Only points with a message time before this RFC 3339 time"
+// @Param step query string false "Bucket width as a Go duration (e.g. 30s, 5m); one bucket per point if omitted"
+// @Success 200 {object} SpeedSeries "Speed history"
+// @Failure 400 {object} errors.BadRequestError "Invalid rocket ID or query parameters"
+// @Failure 404 {object} errors.NotFoundError "Rocket not found"
+// @Router /rockets/{id}/speed [get]
+func (h *ApiHandler) HandleGetRocketSpeed(w http.ResponseWriter, r *http.Request) {
+	// Extract rocket ID from URL path parameter
+	rocketID := r.PathValue("id")
+
+	// Validate rocket ID
+	if err := validation.ValidateRocketID(rocketID); err != nil {
+		middleware.WriteErrorResponse(w, err)
+		return
+	}
+
+	query := r.URL.Query()
+	from, err := parseTimeParam(query.Get("from"))
+	if err != nil {
+		middleware.WriteErrorResponse(w, errors.NewAPIError(http.StatusBadRequest, "Invalid from time", "from must be an RFC 3339 time"))
+		return
+	}
+	to, err := parseTimeParam(query.Get("to"))
+	if err != nil {
+		middleware.WriteErrorResponse(w, errors.NewAPIError(http.StatusBadRequest, "Invalid to time", "to must be an RFC 3339 time"))
+		return
+	}
+	if !from.IsZero() && !to.IsZero() && !from.Before(to) {
+		middleware.WriteErrorResponse(w, errors.NewAPIError(http.StatusBadRequest, "Invalid time range", "from must be before to"))
+		return
+	}
+
+	var step time.Duration
+	if raw := query.Get("step"); raw != "" {
+		step, err = time.ParseDuration(raw)
+		if err != nil || step <= 0 {
+			middleware.WriteErrorResponse(w, errors.NewAPIError(http.StatusBadRequest, "Invalid step", "step must be a positive duration such as 30s or 5m"))
+			return
+		}
+	}
+
+	buckets, exists := h.Repository.GetSpeedBuckets(rocketID, from, to, step)
+	if !exists {
+		middleware.WriteErrorResponse(w, errors.NewAPIError(http.StatusNotFound, "Rocket not found", "No rocket found with ID: "+rocketID))
+		return
+	}
+
+	series := SpeedSeries{RocketID: rocketID, Buckets: buckets}
+	if step > 0 {
+		series.Step = step.String()
+	}
+
+	middleware.WriteSuccessResponse(w, series)
+}
+
+// parseTimeParam parses an optional RFC 3339 query parameter, returning the zero time when empty
+func parseTimeParam(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339Nano, value)
+}
@@ -0,0 +1,29 @@
+package api
+
+import (
+	"net/http"
+
+	"lunar-backend-challenge/internal/middleware"
+)
+
+// HandleGetStats returns fleet statistics
+// @Summary Fleet statistics
+// @Description Retrieves rocket totals, counts and speeds per rocket type and mission, and explosions per reason. Accepts the same filters as the rocket list.
+// @Tags Rockets
+// @Produce json
+// @Param type query string false "Only rockets of this type (case-insensitive)"
+// @Param mission query string false "Only rockets on this mission (case-insensitive)"
+// @Param status query string false "Only rockets with this lifecycle status"
+// @Param silent query bool false "Only rockets that are (true) or are not (false) silent"
+// @Success 200 {object} models.FleetStats "Fleet statistics"
+// @Failure 400 {object} errors.BadRequestError "Invalid filter parameters"
+// @Router /stats [get]
+func (h *ApiHandler) HandleGetStats(w http.ResponseWriter, r *http.Request) {
+	filter, err := rocketFilter(r)
+	if err != nil {
+		middleware.WriteErrorResponse(w, err)
+		return
+	}
+
+	middleware.WriteSuccessResponse(w, h.Repository.GetStats(filter))
+}
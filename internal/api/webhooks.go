@@ -0,0 +1,136 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"lunar-backend-challenge/internal/errors"
+	"lunar-backend-challenge/internal/middleware"
+	"lunar-backend-challenge/internal/webhooks"
+)
+
+// HandleCreateWebhook adds a webhook subscription
+// @Summary Create webhook subscription
+// @Description Subscribes a URL to rocket state changes, optionally filtered by message type, rocket and mission. Deliveries are signed with HMAC-SHA256 in the X-Webhook-Signature header; the secret is generated if none is given and only returned here.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param subscription body webhooks.Subscription true "Subscription (url is required)"
+// @Success 200 {object} webhooks.Subscription "Subscription created"
+// @Failure 400 {object} errors.BadRequestError "Invalid subscription"
+// @Router /admin/webhooks [post]
+func (h *ApiHandler) HandleCreateWebhook(w http.ResponseWriter, r *http.Request) {
+	var subscription webhooks.Subscription
+	if err := json.NewDecoder(r.Body).Decode(&subscription); err != nil {
+		middleware.WriteErrorResponse(w, errors.NewAPIError(http.StatusBadRequest, "Invalid JSON format", err.Error()))
+		return
+	}
+
+	created, err := h.Webhooks.Subscribe(subscription)
+	if err != nil {
+		middleware.WriteErrorResponse(w, errors.NewAPIError(http.StatusBadRequest, "Invalid subscription", err.Error()))
+		return
+	}
+
+	middleware.WriteSuccessResponse(w, created)
+}
+
+// HandleListWebhooks returns the webhook subscriptions
+// @Summary List webhook subscriptions
+// @Description Retrieves every webhook subscription, without secrets
+// @Tags Admin
+// @Produce json
+// @Success 200 {array} webhooks.Subscription "Subscriptions"
+// @Router /admin/webhooks [get]
+func (h *ApiHandler) HandleListWebhooks(w http.ResponseWriter, r *http.Request) {
+	middleware.WriteSuccessResponse(w, h.Webhooks.List())
+}
+
+// HandleGetWebhook returns a single webhook subscription
+// @Summary Get webhook subscription
+// @Tags Admin
+// @Produce json
+// @Param id path string true "Subscription ID"
+// @Success 200 {object} webhooks.Subscription "Subscription"
+// @Failure 404 {object} errors.NotFoundError "Subscription not found"
+// @Router /admin/webhooks/{id} [get]
+func (h *ApiHandler) HandleGetWebhook(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	subscription, exists := h.Webhooks.Get(id)
+	if !exists {
+		middleware.WriteErrorResponse(w, webhookNotFound(id))
+		return
+	}
+
+	middleware.WriteSuccessResponse(w, subscription)
+}
+
+// HandleDeleteWebhook removes a webhook subscription
+// @Summary Delete webhook subscription
+// @Description Removes a subscription; events still queued for it are not delivered
+// @Tags Admin
+// @Produce json
+// @Param id path string true "Subscription ID"
+// @Success 200 {object} map[string]string "Subscription deleted"
+// @Failure 404 {object} errors.NotFoundError "Subscription not found"
+// @Router /admin/webhooks/{id} [delete]
+func (h *ApiHandler) HandleDeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	if !h.Webhooks.Unsubscribe(id) {
+		middleware.WriteErrorResponse(w, webhookNotFound(id))
+		return
+	}
+
+	middleware.WriteSuccessResponse(w, map[string]string{
+		"status": "deleted",
+		"id":     id,
+	})
+}
+
+// HandleGetWebhookDeliveries returns the delivery log of a webhook subscription
+// @Summary Get webhook delivery log
+// @Description Retrieves the most recent delivery attempts of a subscription, most recent first
+// @Tags Admin
+// @Produce json
+// @Param id path string true "Subscription ID"
+// @Success 200 {array} webhooks.Delivery "Delivery attempts"
+// @Failure 404 {object} errors.NotFoundError "Subscription not found"
+// @Router /admin/webhooks/{id}/deliveries [get]
+func (h *ApiHandler) HandleGetWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	deliveries, exists := h.Webhooks.Deliveries(id)
+	if !exists {
+		middleware.WriteErrorResponse(w, webhookNotFound(id))
+		return
+	}
+
+	middleware.WriteSuccessResponse(w, deliveries)
+}
+
+// HandleEnableWebhook re-enables a webhook subscription disabled after repeated failures
+// @Summary Re-enable webhook subscription
+// @Description Re-enables a subscription and resets its failure count. Events that occurred while it was disabled are not delivered.
+// @Tags Admin
+// @Produce json
+// @Param id path string true "Subscription ID"
+// @Success 200 {object} webhooks.Subscription "Subscription"
+// @Failure 404 {object} errors.NotFoundError "Subscription not found"
+// @Router /admin/webhooks/{id}/enable [post]
+func (h *ApiHandler) HandleEnableWebhook(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	subscription, exists := h.Webhooks.Enable(id)
+	if !exists {
+		middleware.WriteErrorResponse(w, webhookNotFound(id))
+		return
+	}
+
+	middleware.WriteSuccessResponse(w, subscription)
+}
+
+func webhookNotFound(id string) errors.APIError {
+	return errors.NewAPIError(http.StatusNotFound, "Webhook not found", "No webhook subscription found with ID: "+id)
+}
@@ -0,0 +1,263 @@
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"lunar-backend-challenge/internal/api"
+	"lunar-backend-challenge/internal/errors"
+	"lunar-backend-challenge/internal/models"
+)
+
+// Client calls the rocket tracking API of a server
+type Client struct {
+	BaseURL string // e.g. http://localhost:8088
+	Token   string // Sent as a bearer token if not empty
+	HTTP    *http.Client
+}
+
+// New creates a client for the server at baseURL
+func New(baseURL, token string) *Client {
+	return &Client{
+		BaseURL: strings.TrimSuffix(baseURL, "/"),
+		Token:   token,
+		HTTP:    &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// ListRockets returns the rockets passing the filter, sorted like GET /rockets?sortBy=&sortOrder=
+func (c *Client) ListRockets(ctx context.Context, query url.Values) ([]models.RocketSummary, error) {
+	var rockets []models.RocketSummary
+	err := c.do(ctx, http.MethodGet, "/rockets?"+query.Encode(), nil, &rockets)
+	return rockets, err
+}
+
+// GetRocket returns a rocket by ID
+func (c *Client) GetRocket(ctx context.Context, id string) (*models.RocketState, error) {
+	var rocket models.RocketState
+	if err := c.do(ctx, http.MethodGet, "/rockets/"+url.PathEscape(id), nil, &rocket); err != nil {
+		return nil, err
+	}
+	return &rocket, nil
+}
+
+// GetDebugInfo returns the message processing details of a rocket
+func (c *Client) GetDebugInfo(ctx context.Context, id string) (*api.DebugInfo, error) {
+	var debugInfo api.DebugInfo
+	if err := c.do(ctx, http.MethodGet, "/debug/rockets/"+url.PathEscape(id), nil, &debugInfo); err != nil {
+		return nil, err
+	}
+	return &debugInfo, nil
+}
+
+// SendMessage posts a message to /messages. msg is encoded as JSON, usually a *models.RocketMessage
+// or a json.RawMessage for message types with fields not declared in models.MessageContent.
+func (c *Client) SendMessage(ctx context.Context, msg any) (*api.MessageResponse, error) {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+	var response api.MessageResponse
+	if err := c.do(ctx, http.MethodPost, "/messages", body, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// do sends a request and decodes the JSON response into result. Error responses are
+// returned as errors.APIError.
+func (c *Client) do(ctx context.Context, method, path string, body []byte, result any) error {
+	req, err := c.newRequest(ctx, method, path, body)
+	if err != nil {
+		return err
+	}
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return responseError(resp)
+	}
+	return json.NewDecoder(resp.Body).Decode(result)
+}
+
+// newRequest creates a request to the server with the credentials
+func (c *Client) newRequest(ctx context.Context, method, path string, body []byte) (*http.Request, error) {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.BaseURL+path, reader)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+	return req, nil
+}
+
+// responseError converts an error response into an errors.APIError
+func responseError(resp *http.Response) error {
+	var envelope struct {
+		Error errors.APIError `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil || envelope.Error.Message == "" {
+		return errors.NewAPIError(resp.StatusCode, http.StatusText(resp.StatusCode), "")
+	}
+	envelope.Error.Code = resp.StatusCode
+	return envelope.Error
+}
+
+// Change is a rocket state change received by Watch
+type Change struct {
+	Kind          string               `json:"kind"`
+	RocketID      string               `json:"rocketId"`
+	MessageType   string               `json:"messageType,omitempty"`
+	MessageNumber int                  `json:"messageNumber,omitempty"`
+	OccurredAt    time.Time            `json:"occurredAt"`
+	After         models.RocketSummary `json:"after"`
+}
+
+// watchQuery subscribes to rocket changes through the GraphQL endpoint
+const watchQuery = `subscription($rocketId: ID, $mission: String) {
+  rocketChanged(rocketId: $rocketId, mission: $mission) {
+    kind rocketId messageType messageNumber occurredAt
+    after { id type speed mission exploded status updatedAt partial silent lastSeen }
+  }
+}`
+
+// Watch streams rocket state changes, optionally of one rocket or mission, to handle until ctx
+// is done or the server ends the stream. It uses the GraphQL subscription over server-sent events.
+func (c *Client) Watch(ctx context.Context, rocketID, mission string, handle func(Change)) error {
+	variables := map[string]any{}
+	if rocketID != "" {
+		variables["rocketId"] = rocketID
+	}
+	if mission != "" {
+		variables["mission"] = mission
+	}
+	body, err := json.Marshal(map[string]any{"query": watchQuery, "variables": variables})
+	if err != nil {
+		return err
+	}
+
+	req, err := c.newRequest(ctx, http.MethodPost, "/graphql", body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+
+	// The stream stays open, so only the context bounds it
+	streaming := *c.HTTP
+	streaming.Timeout = 0
+	resp, err := streaming.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return responseError(resp)
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		data, isData := strings.CutPrefix(scanner.Text(), "data: ")
+		if !isData {
+			continue
+		}
+		var event struct {
+			Data struct {
+				RocketChanged Change `json:"rocketChanged"`
+			} `json:"data"`
+			Errors []struct {
+				Message string `json:"message"`
+			} `json:"errors"`
+		}
+		if err := json.Unmarshal([]byte(data), &event); err != nil {
+			return fmt.Errorf("invalid event %q: %w", data, err)
+		}
+		if len(event.Errors) > 0 {
+			return fmt.Errorf("subscription failed: %s", event.Errors[0].Message)
+		}
+		handle(event.Data.RocketChanged)
+	}
+	if ctx.Err() != nil {
+		return nil
+	}
+	return scanner.Err()
+}
+
+// Poll emulates Watch for servers without the GraphQL endpoint: it lists the rockets every
+// interval and reports those that are new or were updated since the previous listing
+func (c *Client) Poll(ctx context.Context, query url.Values, interval time.Duration, handle func(Change)) error {
+	seen := make(map[string]models.RocketSummary)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		rockets, err := c.ListRockets(ctx, query)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		for _, rocket := range rockets {
+			previous, exists := seen[rocket.ID]
+			if exists && previous == rocket {
+				continue
+			}
+			seen[rocket.ID] = rocket
+			kind := "updated"
+			if !exists {
+				kind = "new"
+			}
+			handle(Change{Kind: kind, RocketID: rocket.ID, OccurredAt: rocket.UpdatedAt, After: rocket})
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Gap is a range of message numbers that have not been received yet
+type Gap struct {
+	From int `json:"from"`
+	To   int `json:"to"`
+}
+
+// Gaps returns the message numbers missing between the last processed message and the
+// buffered ones, which hold back everything after them. pending may be in any order.
+func Gaps(lastProcessed int, pending []int) []Gap {
+	sorted := slices.Clone(pending)
+	slices.Sort(sorted)
+
+	gaps := []Gap{}
+	next := lastProcessed + 1
+	for _, number := range sorted {
+		if number > next {
+			gaps = append(gaps, Gap{From: next, To: number - 1})
+		}
+		if number >= next {
+			next = number + 1
+		}
+	}
+	return gaps
+}
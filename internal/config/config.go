@@ -0,0 +1,183 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"lunar-backend-challenge/internal/graphapi"
+	"lunar-backend-challenge/internal/recording"
+	"lunar-backend-challenge/internal/storage"
+)
+
+// Config holds the server configuration, read from command line flags with environment fallbacks
+type Config struct {
+	Addr                string
+	GRPCAddr            string
+	GraphQLComplexity   int
+	BootstrapPolicy     storage.BootstrapPolicy
+	BootstrapTimeout    time.Duration
+	MaintenanceInterval time.Duration
+	SilenceTimeout      time.Duration
+	SilenceClock        storage.SilenceClock
+	ConflictPolicy      storage.ConflictPolicy
+	MaxClockSkew        time.Duration
+	MaxMessageGap       time.Duration
+	ClockSkewPolicy     storage.ClockSkewPolicy
+	RulesFile           string
+	ImportFiles         []string
+	RecordFile          string
+	RecordMaxSize       int
+	RecordMaxFiles      int
+}
+
+// Load parses the command line arguments (without the program name).
+// Each flag defaults to the environment variable named in its usage text.
+func Load(args []string) (*Config, error) {
+	flags := flag.NewFlagSet("lunar-rocket-api", flag.ContinueOnError)
+
+	addr := flags.String("addr", envString("ADDR", ":8088"), "listen address (ADDR)")
+	grpcAddr := flags.String("grpc-addr", envString("GRPC_ADDR", ":9090"), "gRPC listen address, empty to disable (GRPC_ADDR)")
+	graphQLComplexity := flags.Int("graphql-max-complexity", envInt("GRAPHQL_MAX_COMPLEXITY", graphapi.DefaultMaxComplexity),
+		"highest complexity of a GraphQL query (GRAPHQL_MAX_COMPLEXITY)")
+	bootstrapPolicy := flags.String("bootstrap-policy", envString("BOOTSTRAP_POLICY", string(storage.BootstrapWait)),
+		"what to do with rockets whose launch was never received: wait or timeout (BOOTSTRAP_POLICY)")
+	bootstrapTimeout := flags.Duration("bootstrap-timeout", envDuration("BOOTSTRAP_TIMEOUT", storage.DefaultBootstrapTimeout),
+		"how long to buffer messages before bootstrapping a partial rocket (BOOTSTRAP_TIMEOUT)")
+	maintenanceInterval := flags.Duration("maintenance-interval", envDuration("MAINTENANCE_INTERVAL", time.Second),
+		"how often time based housekeeping runs (MAINTENANCE_INTERVAL)")
+	silenceTimeout := flags.Duration("silence-timeout", envDuration("SILENCE_TIMEOUT", storage.DefaultSilenceTimeout),
+		"how long a rocket in flight may send no messages before it is silent, negative to disable (SILENCE_TIMEOUT)")
+	silenceClock := flags.String("silence-clock", envString("SILENCE_CLOCK", string(storage.SilenceByReceiveTime)),
+		"measure silence by receive time or by messageTime: received or message (SILENCE_CLOCK)")
+	conflictPolicy := flags.String("conflict-policy", envString("CONFLICT_POLICY", string(storage.ConflictFirstWins)),
+		"what to do with a message number received again with a different payload: first-wins, reject or alert (CONFLICT_POLICY)")
+	maxClockSkew := flags.Duration("max-clock-skew", envDuration("MAX_CLOCK_SKEW", storage.DefaultMaxClockSkew),
+		"how far a messageTime may be ahead of server time before it is an anomaly, negative to disable (MAX_CLOCK_SKEW)")
+	maxMessageGap := flags.Duration("max-message-gap", envDuration("MAX_MESSAGE_GAP", storage.DefaultMaxMessageGap),
+		"how far apart the messageTimes of consecutive messages may be before it is an anomaly, negative to disable (MAX_MESSAGE_GAP)")
+	clockSkewPolicy := flags.String("clock-skew-policy", envString("CLOCK_SKEW_POLICY", string(storage.ClockSkewFlag)),
+		"what to do with messages beyond the clock skew tolerance: flag or reject (CLOCK_SKEW_POLICY)")
+	rulesFile := flags.String("rules-file", envString("RULES_FILE", ""),
+		"JSON file with alerting rules loaded at startup (RULES_FILE)")
+	importFiles := flags.String("import", envString("IMPORT_FILES", ""),
+		"comma separated NDJSON message files applied before serving (IMPORT_FILES)")
+	recordFile := flags.String("record-file", envString("RECORD_FILE", ""),
+		"file the raw POST /messages bodies are recorded to for replay, empty to disable (RECORD_FILE)")
+	recordMaxSize := flags.Int("record-max-size", envInt("RECORD_MAX_SIZE", recording.DefaultMaxSize),
+		"size in bytes at which the recording file is rotated (RECORD_MAX_SIZE)")
+	recordMaxFiles := flags.Int("record-max-files", envInt("RECORD_MAX_FILES", recording.DefaultMaxFiles),
+		"number of rotated recording files kept (RECORD_MAX_FILES)")
+
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+
+	policy, err := storage.ParseBootstrapPolicy(*bootstrapPolicy)
+	if err != nil {
+		return nil, err
+	}
+
+	clock, err := storage.ParseSilenceClock(*silenceClock)
+	if err != nil {
+		return nil, err
+	}
+
+	conflicts, err := storage.ParseConflictPolicy(*conflictPolicy)
+	if err != nil {
+		return nil, err
+	}
+
+	skewPolicy, err := storage.ParseClockSkewPolicy(*clockSkewPolicy)
+	if err != nil {
+		return nil, err
+	}
+
+	// Housekeeping runs on a ticker, which needs a positive interval
+	if *maintenanceInterval <= 0 {
+		return nil, fmt.Errorf("invalid maintenance interval %s (must be positive)", *maintenanceInterval)
+	}
+
+	return &Config{
+		Addr:                *addr,
+		GRPCAddr:            *grpcAddr,
+		GraphQLComplexity:   *graphQLComplexity,
+		BootstrapPolicy:     policy,
+		BootstrapTimeout:    *bootstrapTimeout,
+		MaintenanceInterval: *maintenanceInterval,
+		SilenceTimeout:      *silenceTimeout,
+		SilenceClock:        clock,
+		ConflictPolicy:      conflicts,
+		MaxClockSkew:        *maxClockSkew,
+		MaxMessageGap:       *maxMessageGap,
+		ClockSkewPolicy:     skewPolicy,
+		RulesFile:           *rulesFile,
+		ImportFiles:         splitList(*importFiles),
+		RecordFile:          *recordFile,
+		RecordMaxSize:       *recordMaxSize,
+		RecordMaxFiles:      *recordMaxFiles,
+	}, nil
+}
+
+// GraphQL returns the GraphQL endpoint options
+func (c *Config) GraphQL() graphapi.Options {
+	options := graphapi.DefaultOptions()
+	options.MaxComplexity = c.GraphQLComplexity
+	return options
+}
+
+// Storage returns the repository configuration
+func (c *Config) Storage() storage.Config {
+	storageConfig := storage.DefaultConfig()
+	storageConfig.BootstrapPolicy = c.BootstrapPolicy
+	storageConfig.BootstrapTimeout = c.BootstrapTimeout
+	storageConfig.SilenceTimeout = c.SilenceTimeout
+	storageConfig.SilenceClock = c.SilenceClock
+	storageConfig.ConflictPolicy = c.ConflictPolicy
+	storageConfig.MaxClockSkew = c.MaxClockSkew
+	storageConfig.MaxMessageGap = c.MaxMessageGap
+	storageConfig.ClockSkewPolicy = c.ClockSkewPolicy
+	return storageConfig
+}
+
+// splitList splits a comma separated list, dropping empty entries
+func splitList(list string) []string {
+	var entries []string
+	for _, entry := range strings.Split(list, ",") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// envString returns the environment variable or the fallback if it is not set
+func envString(name, fallback string) string {
+	if value, exists := os.LookupEnv(name); exists {
+		return value
+	}
+	return fallback
+}
+
+// envDuration returns the environment variable parsed as a duration or the fallback if it is not set or invalid
+func envDuration(name string, fallback time.Duration) time.Duration {
+	if value, exists := os.LookupEnv(name); exists {
+		if duration, err := time.ParseDuration(value); err == nil {
+			return duration
+		}
+	}
+	return fallback
+}
+
+// envInt returns the environment variable parsed as an integer or the fallback if it is not set or invalid
+func envInt(name string, fallback int) int {
+	if value, exists := os.LookupEnv(name); exists {
+		if number, err := strconv.Atoi(value); err == nil {
+			return number
+		}
+	}
+	return fallback
+}
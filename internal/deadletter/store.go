@@ -0,0 +1,255 @@
+package deadletter
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"lunar-backend-challenge/internal/metrics"
+)
+
+// Rejection reason codes
+const (
+	ReasonInvalidJSON      = "invalid_json"
+	ReasonValidationFailed = "validation_failed"
+	ReasonProcessingFailed = "processing_failed"
+	ReasonConflict         = "conflict"   // Same message number as an accepted message, different payload
+	ReasonClockSkew        = "clock_skew" // messageTime too far ahead of server time
+)
+
+// ValidReasons lists the reason codes entries can be filtered by
+var ValidReasons = []string{
+	ReasonInvalidJSON,
+	ReasonValidationFailed,
+	ReasonProcessingFailed,
+	ReasonConflict,
+	ReasonClockSkew,
+}
+
+// DefaultCapacity is the number of entries kept before the oldest are evicted
+const DefaultCapacity = 10000
+
+// Entry is a rejected message together with why and when it was rejected
+type Entry struct {
+	ID            string    `json:"id" example:"17"`
+	Channel       string    `json:"channel,omitempty" example:"193270a9-c9cf-404a-8f83-838e71d9ae67"`
+	MessageNumber int       `json:"messageNumber,omitempty" example:"3"`
+	MessageType   string    `json:"messageType,omitempty" example:"RocketSpeedIncreased"`
+	Reason        string    `json:"reason" example:"validation_failed"`
+	Details       string    `json:"details" example:"Validation error for field 'by': speed change amount must be positive"`
+	ReceivedAt    time.Time `json:"receivedAt" example:"2024-03-14T19:39:05.86337+01:00"`
+	Body          string    `json:"body" example:"{\"metadata\":{...},\"message\":{...}}"`
+	ReplayCount   int       `json:"replayCount" example:"0"`
+}
+
+// Filter selects entries by channel and/or reason. Empty fields match everything.
+type Filter struct {
+	Channel string
+	Reason  string
+}
+
+// Matches reports whether an entry satisfies the filter
+func (f Filter) Matches(entry *Entry) bool {
+	if f.Channel != "" && entry.Channel != f.Channel {
+		return false
+	}
+	if f.Reason != "" && entry.Reason != f.Reason {
+		return false
+	}
+	return true
+}
+
+// Stats holds counters about the dead-letter store
+type Stats struct {
+	Depth        int
+	Added        map[string]int64 // Entries added per reason
+	Replayed     int64
+	Discarded    int64
+	Evicted      int64
+	ReplayFailed int64
+}
+
+// Store keeps rejected messages in memory so they can be inspected, replayed or discarded
+type Store struct {
+	entries  map[string]*Entry
+	order    []string // Entry IDs in insertion order, used for eviction
+	capacity int
+	nextID   int
+	stats    Stats
+	mutex    sync.RWMutex
+}
+
+// NewStore creates a dead-letter store holding at most capacity entries (DefaultCapacity if <= 0)
+func NewStore(capacity int) *Store {
+	if capacity <= 0 {
+		capacity = DefaultCapacity
+	}
+	return &Store{
+		entries:  make(map[string]*Entry),
+		capacity: capacity,
+		stats:    Stats{Added: make(map[string]int64)},
+	}
+}
+
+// Add stores a rejected message and returns the stored entry with its assigned ID
+func (s *Store) Add(entry Entry) Entry {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.nextID++
+	entry.ID = strconv.Itoa(s.nextID)
+	if entry.ReceivedAt.IsZero() {
+		entry.ReceivedAt = time.Now()
+	}
+
+	s.entries[entry.ID] = &entry
+	s.order = append(s.order, entry.ID)
+	s.stats.Added[entry.Reason]++
+	s.evict()
+
+	return entry
+}
+
+// evict drops the oldest entries once the store is over capacity
+func (s *Store) evict() {
+	for len(s.entries) > s.capacity && len(s.order) > 0 {
+		oldest := s.order[0]
+		s.order = s.order[1:]
+		if _, exists := s.entries[oldest]; exists {
+			delete(s.entries, oldest)
+			s.stats.Evicted++
+		}
+	}
+
+	// Replayed and discarded entries leave stale IDs behind, compact them away now and then
+	if len(s.order) > 2*s.capacity {
+		live := make([]string, 0, len(s.entries))
+		for _, id := range s.order {
+			if _, exists := s.entries[id]; exists {
+				live = append(live, id)
+			}
+		}
+		s.order = live
+	}
+}
+
+// Get returns a copy of the entry with the given ID
+func (s *Store) Get(id string) (Entry, bool) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	entry, exists := s.entries[id]
+	if !exists {
+		return Entry{}, false
+	}
+	return *entry, true
+}
+
+// List returns the entries matching the filter, oldest first
+func (s *Store) List(filter Filter) []Entry {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	entries := make([]Entry, 0, len(s.entries))
+	for _, id := range s.order {
+		entry, exists := s.entries[id]
+		if exists && filter.Matches(entry) {
+			entries = append(entries, *entry)
+		}
+	}
+	return entries
+}
+
+// MarkReplayFailed records a failed replay attempt, replacing the body and rejection reason
+func (s *Store) MarkReplayFailed(id string, updated Entry) (Entry, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	entry, exists := s.entries[id]
+	if !exists {
+		return Entry{}, false
+	}
+
+	entry.Channel = updated.Channel
+	entry.MessageNumber = updated.MessageNumber
+	entry.MessageType = updated.MessageType
+	entry.Reason = updated.Reason
+	entry.Details = updated.Details
+	entry.Body = updated.Body
+	entry.ReplayCount++
+	s.stats.ReplayFailed++
+
+	return *entry, true
+}
+
+// MarkReplayed removes an entry after it has been replayed successfully
+func (s *Store) MarkReplayed(id string) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if _, exists := s.entries[id]; !exists {
+		return false
+	}
+	delete(s.entries, id)
+	s.stats.Replayed++
+	return true
+}
+
+// Discard removes an entry without replaying it
+func (s *Store) Discard(id string) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if _, exists := s.entries[id]; !exists {
+		return false
+	}
+	delete(s.entries, id)
+	s.stats.Discarded++
+	return true
+}
+
+// Len returns the number of entries in the store
+func (s *Store) Len() int {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return len(s.entries)
+}
+
+// Stats returns a snapshot of the store counters
+func (s *Store) Stats() Stats {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	stats := s.stats
+	stats.Depth = len(s.entries)
+	stats.Added = make(map[string]int64, len(s.stats.Added))
+	for reason, count := range s.stats.Added {
+		stats.Added[reason] = count
+	}
+	return stats
+}
+
+// Collect implements metrics.Collector
+func (s *Store) Collect() []metrics.Sample {
+	stats := s.Stats()
+
+	samples := []metrics.Sample{
+		{Name: "rocket_dead_letters_depth", Help: "Number of rejected messages currently held in the dead-letter store", Type: metrics.TypeGauge, Value: float64(stats.Depth)},
+		{Name: "rocket_dead_letters_replayed_total", Help: "Dead-lettered messages successfully replayed", Type: metrics.TypeCounter, Value: float64(stats.Replayed)},
+		{Name: "rocket_dead_letters_replay_failed_total", Help: "Replay attempts that were rejected again", Type: metrics.TypeCounter, Value: float64(stats.ReplayFailed)},
+		{Name: "rocket_dead_letters_discarded_total", Help: "Dead-lettered messages discarded without replay", Type: metrics.TypeCounter, Value: float64(stats.Discarded)},
+		{Name: "rocket_dead_letters_evicted_total", Help: "Dead-lettered messages evicted because the store was full", Type: metrics.TypeCounter, Value: float64(stats.Evicted)},
+	}
+
+	for reason, count := range stats.Added {
+		samples = append(samples, metrics.Sample{
+			Name:   "rocket_dead_letters_added_total",
+			Help:   "Rejected messages added to the dead-letter store",
+			Type:   metrics.TypeCounter,
+			Labels: map[string]string{"reason": reason},
+			Value:  float64(count),
+		})
+	}
+	return samples
+}
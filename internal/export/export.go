@@ -0,0 +1,210 @@
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Format is a response format for list endpoints
+type Format string
+
+// Supported formats
+const (
+	FormatJSON   Format = "json"
+	FormatCSV    Format = "csv"
+	FormatNDJSON Format = "ndjson"
+)
+
+// Media types of the formats
+const (
+	MediaTypeCSV    = "text/csv"
+	MediaTypeNDJSON = "application/x-ndjson"
+)
+
+// flushEvery is the number of records written between flushes, so large exports go out as they are encoded
+const flushEvery = 100
+
+// Negotiate picks the response format from the format query parameter or, without it, the first
+// supported media type of the Accept header. JSON is the default.
+func Negotiate(r *http.Request) (Format, error) {
+	if format := r.URL.Query().Get("format"); format != "" {
+		switch Format(strings.ToLower(format)) {
+		case FormatJSON:
+			return FormatJSON, nil
+		case FormatCSV:
+			return FormatCSV, nil
+		case FormatNDJSON:
+			return FormatNDJSON, nil
+		}
+		return "", fmt.Errorf("unsupported format %q, supported formats are: json, csv, ndjson", format)
+	}
+
+	for _, accepted := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(accepted))
+		if err != nil {
+			continue
+		}
+		switch mediaType {
+		case MediaTypeCSV:
+			return FormatCSV, nil
+		case MediaTypeNDJSON, "application/ndjson":
+			return FormatNDJSON, nil
+		case "application/json", "*/*", "application/*":
+			return FormatJSON, nil
+		}
+	}
+	return FormatJSON, nil
+}
+
+// Columns returns the CSV header of a struct type: the JSON names of its exported fields, in field order
+func Columns(recordType reflect.Type) []string {
+	var columns []string
+	for _, field := range exportedFields(recordType) {
+		columns = append(columns, field.name)
+	}
+	return columns
+}
+
+// Write streams records as CSV, with a header row from Columns, or as NDJSON, one object per
+// line. Output is flushed every few records rather than buffered until the end.
+func Write[T any](w http.ResponseWriter, format Format, records []T) error {
+	switch format {
+	case FormatCSV:
+		return writeCSV(w, records)
+	case FormatNDJSON:
+		return writeNDJSON(w, records)
+	default:
+		return fmt.Errorf("format %q cannot be streamed", format)
+	}
+}
+
+// writeCSV streams records as CSV
+func writeCSV[T any](w http.ResponseWriter, records []T) error {
+	fields := exportedFields(reflect.TypeOf((*T)(nil)).Elem())
+
+	w.Header().Set("Content-Type", MediaTypeCSV+"; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+
+	writer := csv.NewWriter(w)
+	row := make([]string, len(fields))
+	for i, field := range fields {
+		row[i] = field.name
+	}
+	if err := writer.Write(row); err != nil {
+		return err
+	}
+
+	for n, record := range records {
+		value := reflect.ValueOf(record)
+		for i, field := range fields {
+			row[i] = formatValue(value.Field(field.index))
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+		if (n+1)%flushEvery == 0 {
+			writer.Flush()
+			flush(w)
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// writeNDJSON streams records as newline delimited JSON
+func writeNDJSON[T any](w http.ResponseWriter, records []T) error {
+	w.Header().Set("Content-Type", MediaTypeNDJSON)
+	w.WriteHeader(http.StatusOK)
+
+	encoder := json.NewEncoder(w)
+	for n, record := range records {
+		if err := encoder.Encode(record); err != nil {
+			return err
+		}
+		if (n+1)%flushEvery == 0 {
+			flush(w)
+		}
+	}
+	return nil
+}
+
+// flush sends buffered output to the client if the writer supports it
+func flush(w http.ResponseWriter) {
+	if flusher, ok := w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// exportedField is a struct field with its column name
+type exportedField struct {
+	index int
+	name  string
+}
+
+// exportedFields lists the fields of a struct that are marshalled to JSON, named by their json tag
+func exportedFields(recordType reflect.Type) []exportedField {
+	var fields []exportedField
+	for i := 0; i < recordType.NumField(); i++ {
+		field := recordType.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields = append(fields, exportedField{index: i, name: name})
+	}
+	return fields
+}
+
+// formatValue formats a field for CSV. Zero times and nil pointers are empty, and strings that
+// spreadsheets would evaluate as formulas are escaped.
+func formatValue(value reflect.Value) string {
+	if value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			return ""
+		}
+		value = value.Elem()
+	}
+	if t, ok := value.Interface().(time.Time); ok {
+		if t.IsZero() {
+			return ""
+		}
+		return t.Format(time.RFC3339Nano)
+	}
+
+	switch value.Kind() {
+	case reflect.String:
+		return escapeFormula(value.String())
+	case reflect.Bool:
+		return strconv.FormatBool(value.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(value.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(value.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(value.Float(), 'f', -1, 64)
+	default:
+		return fmt.Sprint(value.Interface())
+	}
+}
+
+// escapeFormula prefixes a cell starting with =, +, - or @ with a quote, so spreadsheets
+// show it as text instead of evaluating it
+func escapeFormula(cell string) string {
+	if cell != "" && strings.ContainsRune("=+-@", rune(cell[0])) {
+		return "'" + cell
+	}
+	return cell
+}
@@ -0,0 +1,113 @@
+package feed
+
+import (
+	"sync"
+
+	"lunar-backend-challenge/internal/models"
+	"lunar-backend-challenge/internal/storage"
+)
+
+// DefaultBuffer is the number of state changes buffered per watcher before it is cut off as too slow
+const DefaultBuffer = 256
+
+// Filter selects the state changes a watcher receives. Empty fields match every rocket.
+type Filter struct {
+	RocketID string
+	Mission  string // Compared case-insensitively, see models.MissionKey
+}
+
+// Matches reports whether a state change passes the filter
+func (f Filter) Matches(change storage.StateChange) bool {
+	if f.RocketID != "" && f.RocketID != change.RocketID {
+		return false
+	}
+	if f.Mission == "" {
+		return true
+	}
+	rocket := change.After
+	if rocket == nil {
+		rocket = change.Before
+	}
+	return rocket != nil && models.MissionKey(rocket.Mission) == models.MissionKey(f.Mission)
+}
+
+// Feed hands the state changes of a repository to watchers that come and go, such as
+// streaming API clients. Subscribe Observe to the repository with RocketRepository.Subscribe.
+type Feed struct {
+	watchers map[int]*Watcher
+	nextID   int
+	mutex    sync.Mutex
+}
+
+// Watcher receives the state changes that pass its filter until it is closed
+type Watcher struct {
+	feed     *Feed
+	id       int
+	filter   Filter
+	events   chan storage.StateChange
+	overflow chan struct{} // Closed when the watcher could not keep up
+}
+
+// New creates a feed without watchers
+func New() *Feed {
+	return &Feed{watchers: make(map[int]*Watcher)}
+}
+
+// Watch registers a watcher for the state changes passing filter, buffering up to buffer
+// changes (DefaultBuffer if <= 0). The watcher must be closed when no longer read.
+func (f *Feed) Watch(filter Filter, buffer int) *Watcher {
+	if buffer <= 0 {
+		buffer = DefaultBuffer
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	f.nextID++
+	w := &Watcher{
+		feed:     f,
+		id:       f.nextID,
+		filter:   filter,
+		events:   make(chan storage.StateChange, buffer),
+		overflow: make(chan struct{}),
+	}
+	f.watchers[w.id] = w
+	return w
+}
+
+// Observe hands a state change to the watchers it matches without blocking. A watcher
+// whose buffer is full misses the change and has its Overflow channel closed.
+func (f *Feed) Observe(change storage.StateChange) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	for _, w := range f.watchers {
+		if !w.filter.Matches(change) {
+			continue
+		}
+		select {
+		case <-w.overflow:
+		case w.events <- change:
+		default:
+			close(w.overflow)
+		}
+	}
+}
+
+// Events returns the state changes of the watcher, in the order they were applied
+func (w *Watcher) Events() <-chan storage.StateChange {
+	return w.events
+}
+
+// Overflow is closed when the watcher fell behind and missed state changes
+func (w *Watcher) Overflow() <-chan struct{} {
+	return w.overflow
+}
+
+// Close stops delivering state changes to the watcher
+func (w *Watcher) Close() {
+	w.feed.mutex.Lock()
+	defer w.feed.mutex.Unlock()
+
+	delete(w.feed.watchers, w.id)
+}
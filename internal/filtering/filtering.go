@@ -0,0 +1,67 @@
+package filtering
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+
+	"lunar-backend-challenge/internal/lifecycle"
+	"lunar-backend-challenge/internal/models"
+)
+
+// Filter narrows rockets down to those matching every non-empty field.
+// Type and mission are compared case-insensitively.
+type Filter struct {
+	Type    string
+	Mission string
+	Status  string
+	Silent  string // "true" or "false"
+}
+
+// FromQuery reads the filter from the type, mission, status and silent query parameters
+func FromQuery(query url.Values) Filter {
+	return Filter{
+		Type:    query.Get("type"),
+		Mission: query.Get("mission"),
+		Status:  query.Get("status"),
+		Silent:  query.Get("silent"),
+	}
+}
+
+// ValidateStatus validates if a status filter is a known lifecycle status
+func ValidateStatus(status string) bool {
+	if status == "" {
+		return true // No status filter
+	}
+	return lifecycle.IsValidStatus(status)
+}
+
+// ValidateSilent validates if a silent filter is a boolean
+func ValidateSilent(silent string) bool {
+	return silent == "" || silent == "true" || silent == "false"
+}
+
+// Matches reports whether a rocket with the given type, mission, status and silence passes the filter
+func (f Filter) Matches(rocketType, mission, status string, silent bool) bool {
+	if f.Silent != "" && f.Silent != strconv.FormatBool(silent) {
+		return false
+	}
+	if f.Type != "" && !strings.EqualFold(f.Type, rocketType) {
+		return false
+	}
+	if f.Mission != "" && !strings.EqualFold(f.Mission, mission) {
+		return false
+	}
+	return f.Status == "" || f.Status == status
+}
+
+// FilterRockets returns the rockets that pass the filter, keeping their order
+func FilterRockets(rockets []models.RocketSummary, filter Filter) []models.RocketSummary {
+	filtered := make([]models.RocketSummary, 0, len(rockets))
+	for _, rocket := range rockets {
+		if filter.Matches(rocket.Type, rocket.Mission, rocket.Status, rocket.Silent) {
+			filtered = append(filtered, rocket)
+		}
+	}
+	return filtered
+}
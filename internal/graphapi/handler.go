@@ -0,0 +1,85 @@
+package graphapi
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/gorilla/websocket"
+
+	"lunar-backend-challenge/internal/feed"
+	"lunar-backend-challenge/internal/storage"
+)
+
+// Defaults for Options
+const (
+	// DefaultMaxComplexity is the highest complexity a query may have
+	DefaultMaxComplexity = 1000
+	// DefaultListSize is the number of entries assumed for the complexity of a list without a limit
+	DefaultListSize = 50
+)
+
+// Options configures the GraphQL endpoint
+type Options struct {
+	// MaxComplexity limits the complexity of a query: every selected field counts 1, and the fields
+	// selected in a list count once per expected entry (the limit, or DefaultListSize)
+	MaxComplexity int
+}
+
+// DefaultOptions returns the default GraphQL endpoint options
+func DefaultOptions() Options {
+	return Options{MaxComplexity: DefaultMaxComplexity}
+}
+
+// NewHandler creates the GraphQL endpoint for a repository, serving queries over GET and POST
+// and subscriptions over websockets and server-sent events
+func NewHandler(repository *storage.RocketRepository, options Options) http.Handler {
+	resolver := &Resolver{Repository: repository, Feed: feed.New()}
+	repository.Subscribe(resolver.Feed.Observe)
+
+	config := Config{Resolvers: resolver}
+	config.Complexity.Query.Rockets = func(childComplexity int, _ *RocketFilter, _ *RocketSortField, _ *SortOrder, limit *int) int {
+		if limit != nil {
+			return 1 + *limit*childComplexity
+		}
+		return 1 + childComplexity*DefaultListSize
+	}
+	config.Complexity.Query.Missions = listComplexity
+	config.Complexity.Mission.CurrentRockets = listComplexity
+	config.Complexity.Mission.HistoricalRockets = listComplexity
+
+	server := handler.New(NewExecutableSchema(config))
+	server.AddTransport(transport.Websocket{
+		KeepAlivePingInterval: 10 * time.Second,
+		Upgrader:              websocket.Upgrader{CheckOrigin: func(*http.Request) bool { return true }},
+	})
+	server.AddTransport(transport.SSE{})
+	server.AddTransport(transport.GET{})
+	server.AddTransport(transport.POST{})
+	server.Use(extension.Introspection{})
+	server.Use(extension.FixedComplexityLimit(options.MaxComplexity))
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Subscriptions outlive the server's read and write timeouts
+		if isSubscription(r) {
+			controller := http.NewResponseController(w)
+			controller.SetReadDeadline(time.Time{})
+			controller.SetWriteDeadline(time.Time{})
+		}
+		server.ServeHTTP(w, r)
+	})
+}
+
+// listComplexity is the complexity of a list without a limit
+func listComplexity(childComplexity int) int {
+	return 1 + childComplexity*DefaultListSize
+}
+
+// isSubscription reports whether a request opens a websocket or server-sent events stream
+func isSubscription(r *http.Request) bool {
+	return strings.EqualFold(r.Header.Get("Upgrade"), "websocket") ||
+		strings.Contains(r.Header.Get("Accept"), "text/event-stream")
+}
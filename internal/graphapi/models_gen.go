@@ -0,0 +1,132 @@
+// Code generated by github.com/99designs/gqlgen, DO NOT EDIT.
+
+package graphapi
+
+import (
+	"fmt"
+	"io"
+	"lunar-backend-challenge/internal/storage"
+	"strconv"
+)
+
+type DebugInfo struct {
+	RocketID              string `json:"rocketId"`
+	ProcessedMessageCount int    `json:"processedMessageCount"`
+	PendingMessageNumbers []int  `json:"pendingMessageNumbers"`
+	LastProcessedMessage  int    `json:"lastProcessedMessage"`
+	// Recent messages that reused an accepted message number with a different payload
+	Conflicts []storage.Conflict `json:"conflicts"`
+	// Recent messages whose messageTime disagreed with the server clock or their number
+	Anomalies []storage.Anomaly `json:"anomalies"`
+	// How far behind real time the rocket state is
+	Lag *storage.LagStats `json:"lag,omitempty"`
+}
+
+type Query struct {
+}
+
+type RocketFilter struct {
+	// Rocket type (case-insensitive)
+	Type *string `json:"type,omitempty"`
+	// Mission (case-insensitive)
+	Mission *string `json:"mission,omitempty"`
+	// Lifecycle status
+	Status *string `json:"status,omitempty"`
+	// Whether the rockets are silent
+	Silent *bool `json:"silent,omitempty"`
+}
+
+type Subscription struct {
+}
+
+type RocketSortField string
+
+const (
+	RocketSortFieldID        RocketSortField = "ID"
+	RocketSortFieldType      RocketSortField = "TYPE"
+	RocketSortFieldSpeed     RocketSortField = "SPEED"
+	RocketSortFieldMission   RocketSortField = "MISSION"
+	RocketSortFieldExploded  RocketSortField = "EXPLODED"
+	RocketSortFieldStatus    RocketSortField = "STATUS"
+	RocketSortFieldUpdatedAt RocketSortField = "UPDATED_AT"
+)
+
+var AllRocketSortField = []RocketSortField{
+	RocketSortFieldID,
+	RocketSortFieldType,
+	RocketSortFieldSpeed,
+	RocketSortFieldMission,
+	RocketSortFieldExploded,
+	RocketSortFieldStatus,
+	RocketSortFieldUpdatedAt,
+}
+
+func (e RocketSortField) IsValid() bool {
+	switch e {
+	case RocketSortFieldID, RocketSortFieldType, RocketSortFieldSpeed, RocketSortFieldMission, RocketSortFieldExploded, RocketSortFieldStatus, RocketSortFieldUpdatedAt:
+		return true
+	}
+	return false
+}
+
+func (e RocketSortField) String() string {
+	return string(e)
+}
+
+func (e *RocketSortField) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = RocketSortField(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid RocketSortField", str)
+	}
+	return nil
+}
+
+func (e RocketSortField) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type SortOrder string
+
+const (
+	SortOrderAsc  SortOrder = "ASC"
+	SortOrderDesc SortOrder = "DESC"
+)
+
+var AllSortOrder = []SortOrder{
+	SortOrderAsc,
+	SortOrderDesc,
+}
+
+func (e SortOrder) IsValid() bool {
+	switch e {
+	case SortOrderAsc, SortOrderDesc:
+		return true
+	}
+	return false
+}
+
+func (e SortOrder) String() string {
+	return string(e)
+}
+
+func (e *SortOrder) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = SortOrder(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid SortOrder", str)
+	}
+	return nil
+}
+
+func (e SortOrder) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
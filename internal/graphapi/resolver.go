@@ -0,0 +1,92 @@
+package graphapi
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"lunar-backend-challenge/internal/feed"
+	"lunar-backend-challenge/internal/filtering"
+	"lunar-backend-challenge/internal/lifecycle"
+	"lunar-backend-challenge/internal/models"
+	"lunar-backend-challenge/internal/storage"
+)
+
+// Resolver resolves the GraphQL schema from a repository. The generated code in generated.go
+// and models_gen.go is updated by running gqlgen in this directory after changing schema.graphqls.
+type Resolver struct {
+	Repository *storage.RocketRepository
+	Feed       *feed.Feed
+}
+
+// sortFields maps the sort fields of the schema to those of internal/sorting
+var sortFields = map[RocketSortField]string{
+	RocketSortFieldID:        "id",
+	RocketSortFieldType:      "type",
+	RocketSortFieldSpeed:     "speed",
+	RocketSortFieldMission:   "mission",
+	RocketSortFieldExploded:  "exploded",
+	RocketSortFieldStatus:    "status",
+	RocketSortFieldUpdatedAt: "updatedAt",
+}
+
+// sortField returns the internal/sorting field for a sort argument, the default if it is null
+func sortField(field *RocketSortField) string {
+	if field == nil {
+		return ""
+	}
+	return sortFields[*field]
+}
+
+// sortDirection returns the internal/sorting order for a sort order argument, the default if it is null
+func sortDirection(order *SortOrder) string {
+	if order == nil {
+		return ""
+	}
+	return strings.ToLower(order.String())
+}
+
+// rocketFilter converts and validates a filter argument
+func rocketFilter(input *RocketFilter) (filtering.Filter, error) {
+	var filter filtering.Filter
+	if input == nil {
+		return filter, nil
+	}
+	if input.Type != nil {
+		filter.Type = *input.Type
+	}
+	if input.Mission != nil {
+		filter.Mission = *input.Mission
+	}
+	if input.Status != nil {
+		filter.Status = *input.Status
+	}
+	if input.Silent != nil {
+		filter.Silent = strconv.FormatBool(*input.Silent)
+	}
+
+	if !filtering.ValidateStatus(filter.Status) {
+		return filter, fmt.Errorf("invalid status filter %q, valid statuses are: %s", filter.Status, strings.Join(lifecycle.Statuses, ", "))
+	}
+	return filter, nil
+}
+
+// debugInfo collects the message processing details of a rocket, like GET /debug/rockets/{id}
+func (r *Resolver) debugInfo(rocket *models.RocketState) *DebugInfo {
+	processedCount, pendingMessages := r.Repository.GetDebugInfo(rocket.ID)
+	debugInfo := &DebugInfo{
+		RocketID:              rocket.ID,
+		ProcessedMessageCount: processedCount,
+		PendingMessageNumbers: pendingMessages,
+		LastProcessedMessage:  rocket.LastProcessedMessageNumber,
+		Conflicts:             r.Repository.GetConflicts(rocket.ID),
+		Anomalies:             r.Repository.GetAnomalies(rocket.ID),
+	}
+	if debugInfo.PendingMessageNumbers == nil {
+		debugInfo.PendingMessageNumbers = []int{}
+	}
+	if lag, exists := r.Repository.GetLag(rocket.ID); exists {
+		debugInfo.Lag = &lag
+	}
+	return debugInfo
+}
@@ -0,0 +1,180 @@
+package graphapi
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen
+
+import (
+	"context"
+	"fmt"
+	"lunar-backend-challenge/internal/feed"
+	"lunar-backend-challenge/internal/filtering"
+	"lunar-backend-challenge/internal/models"
+	"lunar-backend-challenge/internal/sorting"
+	"lunar-backend-challenge/internal/storage"
+	"lunar-backend-challenge/internal/validation"
+)
+
+// Policy is the resolver for the policy field.
+func (r *conflictResolver) Policy(ctx context.Context, obj *storage.Conflict) (string, error) {
+	return string(obj.Policy), nil
+}
+
+// Rocket is the resolver for the rocket field.
+func (r *missionRocketResolver) Rocket(ctx context.Context, obj *models.MissionRocket) (*models.RocketState, error) {
+	rocket, exists := r.Repository.GetRocket(obj.RocketID)
+	if !exists {
+		return nil, nil
+	}
+	return rocket, nil
+}
+
+// Rocket is the resolver for the rocket field.
+func (r *queryResolver) Rocket(ctx context.Context, id string) (*models.RocketState, error) {
+	if err := validation.ValidateRocketID(id); err != nil {
+		return nil, err
+	}
+	rocket, exists := r.Repository.GetRocket(id)
+	if !exists {
+		return nil, nil
+	}
+	return rocket, nil
+}
+
+// Rockets is the resolver for the rockets field.
+func (r *queryResolver) Rockets(ctx context.Context, filter *RocketFilter, sortBy *RocketSortField, sortOrder *SortOrder, limit *int) ([]models.RocketSummary, error) {
+	filtered, err := rocketFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+	if limit != nil && *limit < 0 {
+		return nil, fmt.Errorf("invalid limit %d, must not be negative", *limit)
+	}
+
+	rockets := sorting.SortRockets(filtering.FilterRockets(r.Repository.GetAllRockets(), filtered), sortField(sortBy), sortDirection(sortOrder))
+	if limit != nil && *limit < len(rockets) {
+		rockets = rockets[:*limit]
+	}
+	return rockets, nil
+}
+
+// Missions is the resolver for the missions field.
+func (r *queryResolver) Missions(ctx context.Context) ([]models.MissionSummary, error) {
+	return r.Repository.GetMissions(), nil
+}
+
+// Mission is the resolver for the mission field.
+func (r *queryResolver) Mission(ctx context.Context, name string) (*models.Mission, error) {
+	mission, exists := r.Repository.GetMission(name)
+	if !exists {
+		return nil, nil
+	}
+	return mission, nil
+}
+
+// Debug is the resolver for the debug field.
+func (r *queryResolver) Debug(ctx context.Context, rocketID string) (*DebugInfo, error) {
+	if err := validation.ValidateRocketID(rocketID); err != nil {
+		return nil, err
+	}
+	rocket, exists := r.Repository.GetRocket(rocketID)
+	if !exists {
+		return nil, nil
+	}
+	return r.debugInfo(rocket), nil
+}
+
+// Debug is the resolver for the debug field.
+func (r *rocketResolver) Debug(ctx context.Context, obj *models.RocketState) (*DebugInfo, error) {
+	return r.debugInfo(obj), nil
+}
+
+// MessageType is the resolver for the messageType field.
+func (r *rocketChangeResolver) MessageType(ctx context.Context, obj *storage.StateChange) (*string, error) {
+	if obj.Message == nil {
+		return nil, nil
+	}
+	messageType := obj.Message.GetMessageType()
+	return &messageType, nil
+}
+
+// MessageNumber is the resolver for the messageNumber field.
+func (r *rocketChangeResolver) MessageNumber(ctx context.Context, obj *storage.StateChange) (*int, error) {
+	if obj.Message == nil {
+		return nil, nil
+	}
+	messageNumber := obj.Message.GetMessageNumber()
+	return &messageNumber, nil
+}
+
+// Rocket is the resolver for the rocket field.
+func (r *rocketSummaryResolver) Rocket(ctx context.Context, obj *models.RocketSummary) (*models.RocketState, error) {
+	rocket, exists := r.Repository.GetRocket(obj.ID)
+	if !exists {
+		return nil, fmt.Errorf("rocket %s no longer exists", obj.ID)
+	}
+	return rocket, nil
+}
+
+// RocketChanged is the resolver for the rocketChanged field.
+func (r *subscriptionResolver) RocketChanged(ctx context.Context, rocketID *string, mission *string) (<-chan *storage.StateChange, error) {
+	filter := feed.Filter{}
+	if rocketID != nil {
+		filter.RocketID = *rocketID
+	}
+	if mission != nil {
+		filter.Mission = *mission
+	}
+	watcher := r.Feed.Watch(filter, feed.DefaultBuffer)
+
+	// The subscription ends when the client goes away or falls behind
+	changes := make(chan *storage.StateChange)
+	go func() {
+		defer watcher.Close()
+		defer close(changes)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-watcher.Overflow():
+				return
+			case change := <-watcher.Events():
+				select {
+				case changes <- &change:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return changes, nil
+}
+
+// Conflict returns ConflictResolver implementation.
+func (r *Resolver) Conflict() ConflictResolver { return &conflictResolver{r} }
+
+// MissionRocket returns MissionRocketResolver implementation.
+func (r *Resolver) MissionRocket() MissionRocketResolver { return &missionRocketResolver{r} }
+
+// Query returns QueryResolver implementation.
+func (r *Resolver) Query() QueryResolver { return &queryResolver{r} }
+
+// Rocket returns RocketResolver implementation.
+func (r *Resolver) Rocket() RocketResolver { return &rocketResolver{r} }
+
+// RocketChange returns RocketChangeResolver implementation.
+func (r *Resolver) RocketChange() RocketChangeResolver { return &rocketChangeResolver{r} }
+
+// RocketSummary returns RocketSummaryResolver implementation.
+func (r *Resolver) RocketSummary() RocketSummaryResolver { return &rocketSummaryResolver{r} }
+
+// Subscription returns SubscriptionResolver implementation.
+func (r *Resolver) Subscription() SubscriptionResolver { return &subscriptionResolver{r} }
+
+type conflictResolver struct{ *Resolver }
+type missionRocketResolver struct{ *Resolver }
+type queryResolver struct{ *Resolver }
+type rocketResolver struct{ *Resolver }
+type rocketChangeResolver struct{ *Resolver }
+type rocketSummaryResolver struct{ *Resolver }
+type subscriptionResolver struct{ *Resolver }
@@ -0,0 +1,176 @@
+package grpcapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"lunar-backend-challenge/internal/errors"
+	"lunar-backend-challenge/internal/grpcapi/rocketsv1"
+	"lunar-backend-challenge/internal/models"
+	"lunar-backend-challenge/internal/storage"
+)
+
+// sortFields maps the sort fields of the protobuf API to those of internal/sorting
+var sortFields = map[rocketsv1.SortField]string{
+	rocketsv1.SortField_SORT_FIELD_UNSPECIFIED: "",
+	rocketsv1.SortField_SORT_FIELD_ID:          "id",
+	rocketsv1.SortField_SORT_FIELD_TYPE:        "type",
+	rocketsv1.SortField_SORT_FIELD_SPEED:       "speed",
+	rocketsv1.SortField_SORT_FIELD_MISSION:     "mission",
+	rocketsv1.SortField_SORT_FIELD_EXPLODED:    "exploded",
+	rocketsv1.SortField_SORT_FIELD_STATUS:      "status",
+	rocketsv1.SortField_SORT_FIELD_UPDATED_AT:  "updatedAt",
+}
+
+// sortOrders maps the sort orders of the protobuf API to those of internal/sorting
+var sortOrders = map[rocketsv1.SortOrder]string{
+	rocketsv1.SortOrder_SORT_ORDER_UNSPECIFIED: "",
+	rocketsv1.SortOrder_SORT_ORDER_ASC:         "asc",
+	rocketsv1.SortOrder_SORT_ORDER_DESC:        "desc",
+}
+
+// encodeMessage converts a protobuf rocket message into the JSON body accepted by POST /messages,
+// so it goes through the same decoding, validation and dead-lettering
+func encodeMessage(msg *rocketsv1.RocketMessage) ([]byte, error) {
+	metadata, content := msg.GetMetadata(), msg.GetMessage()
+	if metadata == nil {
+		return nil, status.Error(codes.InvalidArgument, "message metadata is required")
+	}
+
+	body := struct {
+		Metadata map[string]any `json:"metadata"`
+		Message  any            `json:"message"`
+	}{
+		Metadata: map[string]any{
+			"channel":       metadata.GetChannel(),
+			"messageNumber": metadata.GetMessageNumber(),
+			"messageTime":   metadata.GetMessageTime().AsTime(),
+			"messageType":   metadata.GetMessageType(),
+		},
+		Message: models.MessageContent{
+			Type:        content.GetType(),
+			LaunchSpeed: int(content.GetLaunchSpeed()),
+			Mission:     content.GetMission(),
+			By:          int(content.GetBy()),
+			Reason:      content.GetReason(),
+			NewMission:  content.GetNewMission(),
+		},
+	}
+	if metadata.GetMessageTime() == nil {
+		delete(body.Metadata, "messageTime")
+	}
+	if payload := content.GetPayloadJson(); payload != "" {
+		if !json.Valid([]byte(payload)) {
+			return nil, status.Error(codes.InvalidArgument, "payload_json is not valid JSON")
+		}
+		body.Message = json.RawMessage(payload)
+	}
+	return json.Marshal(body)
+}
+
+// statusError converts an error of the ingestion pipeline into a gRPC status error
+func statusError(err error) error {
+	switch e := err.(type) {
+	case errors.APIError:
+		return status.Error(statusCode(e.Code), e.Error())
+	case errors.ValidationError:
+		return status.Error(codes.InvalidArgument, e.Error())
+	case errors.MessageProcessingError:
+		return status.Error(codes.FailedPrecondition, e.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
+
+// statusCode maps an HTTP status code to the closest gRPC code
+func statusCode(httpStatus int) codes.Code {
+	switch httpStatus {
+	case http.StatusBadRequest:
+		return codes.InvalidArgument
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusConflict:
+		return codes.AlreadyExists
+	default:
+		return codes.Internal
+	}
+}
+
+// toRocketState converts a rocket state to protobuf
+func toRocketState(rocket *models.RocketState) *rocketsv1.RocketState {
+	if rocket == nil {
+		return nil
+	}
+	state := &rocketsv1.RocketState{
+		Id:               rocket.ID,
+		Type:             rocket.Type,
+		Speed:            int64(rocket.Speed),
+		Mission:          rocket.Mission,
+		Exploded:         rocket.Exploded,
+		Status:           rocket.Status,
+		Reason:           rocket.Reason,
+		CreatedAt:        timestamp(rocket.CreatedAt),
+		UpdatedAt:        timestamp(rocket.UpdatedAt),
+		Partial:          rocket.Partial,
+		BootstrappedFrom: int64(rocket.BootstrappedFrom),
+		LaunchCount:      int64(rocket.LaunchCount),
+		LastSeen:         timestamp(rocket.LastSeen),
+		LastMessageTime:  timestamp(rocket.LastMessageTime),
+		Silent:           rocket.Silent,
+	}
+	if rocket.SilentSince != nil {
+		state.SilentSince = timestamp(*rocket.SilentSince)
+	}
+	return state
+}
+
+// toRocketSummary converts a rocket summary to protobuf
+func toRocketSummary(rocket models.RocketSummary) *rocketsv1.RocketSummary {
+	return &rocketsv1.RocketSummary{
+		Id:        rocket.ID,
+		Type:      rocket.Type,
+		Speed:     int64(rocket.Speed),
+		Mission:   rocket.Mission,
+		Exploded:  rocket.Exploded,
+		Status:    rocket.Status,
+		UpdatedAt: timestamp(rocket.UpdatedAt),
+		Partial:   rocket.Partial,
+		Silent:    rocket.Silent,
+		LastSeen:  timestamp(rocket.LastSeen),
+	}
+}
+
+// toRocketEvent converts a repository state change to protobuf
+func toRocketEvent(change storage.StateChange) *rocketsv1.RocketEvent {
+	event := &rocketsv1.RocketEvent{
+		Kind:       change.Kind,
+		RocketId:   change.RocketID,
+		OccurredAt: timestamp(change.At),
+		Before:     toRocketState(change.Before),
+		After:      toRocketState(change.After),
+	}
+	if change.Message != nil {
+		event.MessageType = change.Message.GetMessageType()
+		event.MessageNumber = int64(change.Message.GetMessageNumber())
+	}
+	return event
+}
+
+// timestamp converts a time to protobuf, leaving zero times unset
+func timestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
+
+// invalidArgument returns an InvalidArgument status error
+func invalidArgument(format string, args ...any) error {
+	return status.Error(codes.InvalidArgument, fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,1580 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        (unknown)
+// source: rockets/v1/rockets.proto
+
+package rocketsv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// SortField mirrors the sort fields of internal/sorting
+type SortField int32
+
+const (
+	SortField_SORT_FIELD_UNSPECIFIED SortField = 0 // id
+	SortField_SORT_FIELD_ID          SortField = 1
+	SortField_SORT_FIELD_TYPE        SortField = 2
+	SortField_SORT_FIELD_SPEED       SortField = 3
+	SortField_SORT_FIELD_MISSION     SortField = 4
+	SortField_SORT_FIELD_EXPLODED    SortField = 5
+	SortField_SORT_FIELD_STATUS      SortField = 6
+	SortField_SORT_FIELD_UPDATED_AT  SortField = 7
+)
+
+// Enum value maps for SortField.
+var (
+	SortField_name = map[int32]string{
+		0: "SORT_FIELD_UNSPECIFIED",
+		1: "SORT_FIELD_ID",
+		2: "SORT_FIELD_TYPE",
+		3: "SORT_FIELD_SPEED",
+		4: "SORT_FIELD_MISSION",
+		5: "SORT_FIELD_EXPLODED",
+		6: "SORT_FIELD_STATUS",
+		7: "SORT_FIELD_UPDATED_AT",
+	}
+	SortField_value = map[string]int32{
+		"SORT_FIELD_UNSPECIFIED": 0,
+		"SORT_FIELD_ID":          1,
+		"SORT_FIELD_TYPE":        2,
+		"SORT_FIELD_SPEED":       3,
+		"SORT_FIELD_MISSION":     4,
+		"SORT_FIELD_EXPLODED":    5,
+		"SORT_FIELD_STATUS":      6,
+		"SORT_FIELD_UPDATED_AT":  7,
+	}
+)
+
+func (x SortField) Enum() *SortField {
+	p := new(SortField)
+	*p = x
+	return p
+}
+
+func (x SortField) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SortField) Descriptor() protoreflect.EnumDescriptor {
+	return file_rockets_v1_rockets_proto_enumTypes[0].Descriptor()
+}
+
+func (SortField) Type() protoreflect.EnumType {
+	return &file_rockets_v1_rockets_proto_enumTypes[0]
+}
+
+func (x SortField) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SortField.Descriptor instead.
+func (SortField) EnumDescriptor() ([]byte, []int) {
+	return file_rockets_v1_rockets_proto_rawDescGZIP(), []int{0}
+}
+
+type SortOrder int32
+
+const (
+	SortOrder_SORT_ORDER_UNSPECIFIED SortOrder = 0 // asc
+	SortOrder_SORT_ORDER_ASC         SortOrder = 1
+	SortOrder_SORT_ORDER_DESC        SortOrder = 2
+)
+
+// Enum value maps for SortOrder.
+var (
+	SortOrder_name = map[int32]string{
+		0: "SORT_ORDER_UNSPECIFIED",
+		1: "SORT_ORDER_ASC",
+		2: "SORT_ORDER_DESC",
+	}
+	SortOrder_value = map[string]int32{
+		"SORT_ORDER_UNSPECIFIED": 0,
+		"SORT_ORDER_ASC":         1,
+		"SORT_ORDER_DESC":        2,
+	}
+)
+
+func (x SortOrder) Enum() *SortOrder {
+	p := new(SortOrder)
+	*p = x
+	return p
+}
+
+func (x SortOrder) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SortOrder) Descriptor() protoreflect.EnumDescriptor {
+	return file_rockets_v1_rockets_proto_enumTypes[1].Descriptor()
+}
+
+func (SortOrder) Type() protoreflect.EnumType {
+	return &file_rockets_v1_rockets_proto_enumTypes[1]
+}
+
+func (x SortOrder) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SortOrder.Descriptor instead.
+func (SortOrder) EnumDescriptor() ([]byte, []int) {
+	return file_rockets_v1_rockets_proto_rawDescGZIP(), []int{1}
+}
+
+// RocketMessage is a message about a rocket's state change, see models.RocketMessage
+type RocketMessage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Metadata      *Metadata              `protobuf:"bytes,1,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	Message       *MessageContent        `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RocketMessage) Reset() {
+	*x = RocketMessage{}
+	mi := &file_rockets_v1_rockets_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RocketMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RocketMessage) ProtoMessage() {}
+
+func (x *RocketMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_rockets_v1_rockets_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RocketMessage.ProtoReflect.Descriptor instead.
+func (*RocketMessage) Descriptor() ([]byte, []int) {
+	return file_rockets_v1_rockets_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *RocketMessage) GetMetadata() *Metadata {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *RocketMessage) GetMessage() *MessageContent {
+	if x != nil {
+		return x.Message
+	}
+	return nil
+}
+
+type Metadata struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Channel       string                 `protobuf:"bytes,1,opt,name=channel,proto3" json:"channel,omitempty"`
+	MessageNumber int64                  `protobuf:"varint,2,opt,name=message_number,json=messageNumber,proto3" json:"message_number,omitempty"`
+	MessageTime   *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=message_time,json=messageTime,proto3" json:"message_time,omitempty"`
+	MessageType   string                 `protobuf:"bytes,4,opt,name=message_type,json=messageType,proto3" json:"message_type,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Metadata) Reset() {
+	*x = Metadata{}
+	mi := &file_rockets_v1_rockets_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Metadata) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Metadata) ProtoMessage() {}
+
+func (x *Metadata) ProtoReflect() protoreflect.Message {
+	mi := &file_rockets_v1_rockets_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Metadata.ProtoReflect.Descriptor instead.
+func (*Metadata) Descriptor() ([]byte, []int) {
+	return file_rockets_v1_rockets_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Metadata) GetChannel() string {
+	if x != nil {
+		return x.Channel
+	}
+	return ""
+}
+
+func (x *Metadata) GetMessageNumber() int64 {
+	if x != nil {
+		return x.MessageNumber
+	}
+	return 0
+}
+
+func (x *Metadata) GetMessageTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.MessageTime
+	}
+	return nil
+}
+
+func (x *Metadata) GetMessageType() string {
+	if x != nil {
+		return x.MessageType
+	}
+	return ""
+}
+
+// MessageContent holds the payload fields of the built-in message types
+type MessageContent struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Type        string                 `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	LaunchSpeed int64                  `protobuf:"varint,2,opt,name=launch_speed,json=launchSpeed,proto3" json:"launch_speed,omitempty"`
+	Mission     string                 `protobuf:"bytes,3,opt,name=mission,proto3" json:"mission,omitempty"`
+	By          int64                  `protobuf:"varint,4,opt,name=by,proto3" json:"by,omitempty"`
+	Reason      string                 `protobuf:"bytes,5,opt,name=reason,proto3" json:"reason,omitempty"`
+	NewMission  string                 `protobuf:"bytes,6,opt,name=new_mission,json=newMission,proto3" json:"new_mission,omitempty"`
+	// JSON payload for message types with fields not declared above; used instead of them when set
+	PayloadJson   string `protobuf:"bytes,7,opt,name=payload_json,json=payloadJson,proto3" json:"payload_json,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MessageContent) Reset() {
+	*x = MessageContent{}
+	mi := &file_rockets_v1_rockets_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MessageContent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MessageContent) ProtoMessage() {}
+
+func (x *MessageContent) ProtoReflect() protoreflect.Message {
+	mi := &file_rockets_v1_rockets_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MessageContent.ProtoReflect.Descriptor instead.
+func (*MessageContent) Descriptor() ([]byte, []int) {
+	return file_rockets_v1_rockets_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *MessageContent) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *MessageContent) GetLaunchSpeed() int64 {
+	if x != nil {
+		return x.LaunchSpeed
+	}
+	return 0
+}
+
+func (x *MessageContent) GetMission() string {
+	if x != nil {
+		return x.Mission
+	}
+	return ""
+}
+
+func (x *MessageContent) GetBy() int64 {
+	if x != nil {
+		return x.By
+	}
+	return 0
+}
+
+func (x *MessageContent) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *MessageContent) GetNewMission() string {
+	if x != nil {
+		return x.NewMission
+	}
+	return ""
+}
+
+func (x *MessageContent) GetPayloadJson() string {
+	if x != nil {
+		return x.PayloadJson
+	}
+	return ""
+}
+
+type IngestMessageRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Message       *RocketMessage         `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IngestMessageRequest) Reset() {
+	*x = IngestMessageRequest{}
+	mi := &file_rockets_v1_rockets_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IngestMessageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IngestMessageRequest) ProtoMessage() {}
+
+func (x *IngestMessageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rockets_v1_rockets_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IngestMessageRequest.ProtoReflect.Descriptor instead.
+func (*IngestMessageRequest) Descriptor() ([]byte, []int) {
+	return file_rockets_v1_rockets_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *IngestMessageRequest) GetMessage() *RocketMessage {
+	if x != nil {
+		return x.Message
+	}
+	return nil
+}
+
+type IngestMessageResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RocketId      string                 `protobuf:"bytes,1,opt,name=rocket_id,json=rocketId,proto3" json:"rocket_id,omitempty"`
+	MessageNumber int64                  `protobuf:"varint,2,opt,name=message_number,json=messageNumber,proto3" json:"message_number,omitempty"`
+	// applied, buffered, duplicate, conflict or ignored
+	Outcome string `protobuf:"bytes,3,opt,name=outcome,proto3" json:"outcome,omitempty"`
+	// Why the message was buffered, a duplicate, a conflict or ignored
+	Reason        string `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IngestMessageResponse) Reset() {
+	*x = IngestMessageResponse{}
+	mi := &file_rockets_v1_rockets_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IngestMessageResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IngestMessageResponse) ProtoMessage() {}
+
+func (x *IngestMessageResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rockets_v1_rockets_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IngestMessageResponse.ProtoReflect.Descriptor instead.
+func (*IngestMessageResponse) Descriptor() ([]byte, []int) {
+	return file_rockets_v1_rockets_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *IngestMessageResponse) GetRocketId() string {
+	if x != nil {
+		return x.RocketId
+	}
+	return ""
+}
+
+func (x *IngestMessageResponse) GetMessageNumber() int64 {
+	if x != nil {
+		return x.MessageNumber
+	}
+	return 0
+}
+
+func (x *IngestMessageResponse) GetOutcome() string {
+	if x != nil {
+		return x.Outcome
+	}
+	return ""
+}
+
+func (x *IngestMessageResponse) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type IngestStreamRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Message       *RocketMessage         `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IngestStreamRequest) Reset() {
+	*x = IngestStreamRequest{}
+	mi := &file_rockets_v1_rockets_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IngestStreamRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IngestStreamRequest) ProtoMessage() {}
+
+func (x *IngestStreamRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rockets_v1_rockets_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IngestStreamRequest.ProtoReflect.Descriptor instead.
+func (*IngestStreamRequest) Descriptor() ([]byte, []int) {
+	return file_rockets_v1_rockets_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *IngestStreamRequest) GetMessage() *RocketMessage {
+	if x != nil {
+		return x.Message
+	}
+	return nil
+}
+
+type IngestStreamResponse struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Received int64                  `protobuf:"varint,1,opt,name=received,proto3" json:"received,omitempty"`
+	Accepted int64                  `protobuf:"varint,2,opt,name=accepted,proto3" json:"accepted,omitempty"`
+	Rejected int64                  `protobuf:"varint,3,opt,name=rejected,proto3" json:"rejected,omitempty"`
+	// Accepted messages per outcome
+	Outcomes      map[string]int64 `protobuf:"bytes,4,rep,name=outcomes,proto3" json:"outcomes,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	Errors        []*IngestError   `protobuf:"bytes,5,rep,name=errors,proto3" json:"errors,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IngestStreamResponse) Reset() {
+	*x = IngestStreamResponse{}
+	mi := &file_rockets_v1_rockets_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IngestStreamResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IngestStreamResponse) ProtoMessage() {}
+
+func (x *IngestStreamResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rockets_v1_rockets_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IngestStreamResponse.ProtoReflect.Descriptor instead.
+func (*IngestStreamResponse) Descriptor() ([]byte, []int) {
+	return file_rockets_v1_rockets_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *IngestStreamResponse) GetReceived() int64 {
+	if x != nil {
+		return x.Received
+	}
+	return 0
+}
+
+func (x *IngestStreamResponse) GetAccepted() int64 {
+	if x != nil {
+		return x.Accepted
+	}
+	return 0
+}
+
+func (x *IngestStreamResponse) GetRejected() int64 {
+	if x != nil {
+		return x.Rejected
+	}
+	return 0
+}
+
+func (x *IngestStreamResponse) GetOutcomes() map[string]int64 {
+	if x != nil {
+		return x.Outcomes
+	}
+	return nil
+}
+
+func (x *IngestStreamResponse) GetErrors() []*IngestError {
+	if x != nil {
+		return x.Errors
+	}
+	return nil
+}
+
+// IngestError describes a message of a stream that was rejected
+type IngestError struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Position of the message in the stream, from 0
+	Index         int64  `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	RocketId      string `protobuf:"bytes,2,opt,name=rocket_id,json=rocketId,proto3" json:"rocket_id,omitempty"`
+	MessageNumber int64  `protobuf:"varint,3,opt,name=message_number,json=messageNumber,proto3" json:"message_number,omitempty"`
+	Error         string `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IngestError) Reset() {
+	*x = IngestError{}
+	mi := &file_rockets_v1_rockets_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IngestError) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IngestError) ProtoMessage() {}
+
+func (x *IngestError) ProtoReflect() protoreflect.Message {
+	mi := &file_rockets_v1_rockets_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IngestError.ProtoReflect.Descriptor instead.
+func (*IngestError) Descriptor() ([]byte, []int) {
+	return file_rockets_v1_rockets_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *IngestError) GetIndex() int64 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *IngestError) GetRocketId() string {
+	if x != nil {
+		return x.RocketId
+	}
+	return ""
+}
+
+func (x *IngestError) GetMessageNumber() int64 {
+	if x != nil {
+		return x.MessageNumber
+	}
+	return 0
+}
+
+func (x *IngestError) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type GetRocketRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRocketRequest) Reset() {
+	*x = GetRocketRequest{}
+	mi := &file_rockets_v1_rockets_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRocketRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRocketRequest) ProtoMessage() {}
+
+func (x *GetRocketRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rockets_v1_rockets_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRocketRequest.ProtoReflect.Descriptor instead.
+func (*GetRocketRequest) Descriptor() ([]byte, []int) {
+	return file_rockets_v1_rockets_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GetRocketRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type GetRocketResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rocket        *RocketState           `protobuf:"bytes,1,opt,name=rocket,proto3" json:"rocket,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRocketResponse) Reset() {
+	*x = GetRocketResponse{}
+	mi := &file_rockets_v1_rockets_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRocketResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRocketResponse) ProtoMessage() {}
+
+func (x *GetRocketResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rockets_v1_rockets_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRocketResponse.ProtoReflect.Descriptor instead.
+func (*GetRocketResponse) Descriptor() ([]byte, []int) {
+	return file_rockets_v1_rockets_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GetRocketResponse) GetRocket() *RocketState {
+	if x != nil {
+		return x.Rocket
+	}
+	return nil
+}
+
+// RocketState is the full state of a rocket, see models.RocketState
+type RocketState struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Id               string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Type             string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	Speed            int64                  `protobuf:"varint,3,opt,name=speed,proto3" json:"speed,omitempty"`
+	Mission          string                 `protobuf:"bytes,4,opt,name=mission,proto3" json:"mission,omitempty"`
+	Exploded         bool                   `protobuf:"varint,5,opt,name=exploded,proto3" json:"exploded,omitempty"`
+	Status           string                 `protobuf:"bytes,6,opt,name=status,proto3" json:"status,omitempty"`
+	Reason           string                 `protobuf:"bytes,7,opt,name=reason,proto3" json:"reason,omitempty"`
+	CreatedAt        *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt        *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	Partial          bool                   `protobuf:"varint,10,opt,name=partial,proto3" json:"partial,omitempty"`
+	BootstrappedFrom int64                  `protobuf:"varint,11,opt,name=bootstrapped_from,json=bootstrappedFrom,proto3" json:"bootstrapped_from,omitempty"`
+	LaunchCount      int64                  `protobuf:"varint,12,opt,name=launch_count,json=launchCount,proto3" json:"launch_count,omitempty"`
+	LastSeen         *timestamppb.Timestamp `protobuf:"bytes,13,opt,name=last_seen,json=lastSeen,proto3" json:"last_seen,omitempty"`
+	LastMessageTime  *timestamppb.Timestamp `protobuf:"bytes,14,opt,name=last_message_time,json=lastMessageTime,proto3" json:"last_message_time,omitempty"`
+	Silent           bool                   `protobuf:"varint,15,opt,name=silent,proto3" json:"silent,omitempty"`
+	SilentSince      *timestamppb.Timestamp `protobuf:"bytes,16,opt,name=silent_since,json=silentSince,proto3" json:"silent_since,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *RocketState) Reset() {
+	*x = RocketState{}
+	mi := &file_rockets_v1_rockets_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RocketState) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RocketState) ProtoMessage() {}
+
+func (x *RocketState) ProtoReflect() protoreflect.Message {
+	mi := &file_rockets_v1_rockets_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RocketState.ProtoReflect.Descriptor instead.
+func (*RocketState) Descriptor() ([]byte, []int) {
+	return file_rockets_v1_rockets_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *RocketState) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *RocketState) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *RocketState) GetSpeed() int64 {
+	if x != nil {
+		return x.Speed
+	}
+	return 0
+}
+
+func (x *RocketState) GetMission() string {
+	if x != nil {
+		return x.Mission
+	}
+	return ""
+}
+
+func (x *RocketState) GetExploded() bool {
+	if x != nil {
+		return x.Exploded
+	}
+	return false
+}
+
+func (x *RocketState) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *RocketState) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *RocketState) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *RocketState) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *RocketState) GetPartial() bool {
+	if x != nil {
+		return x.Partial
+	}
+	return false
+}
+
+func (x *RocketState) GetBootstrappedFrom() int64 {
+	if x != nil {
+		return x.BootstrappedFrom
+	}
+	return 0
+}
+
+func (x *RocketState) GetLaunchCount() int64 {
+	if x != nil {
+		return x.LaunchCount
+	}
+	return 0
+}
+
+func (x *RocketState) GetLastSeen() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastSeen
+	}
+	return nil
+}
+
+func (x *RocketState) GetLastMessageTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastMessageTime
+	}
+	return nil
+}
+
+func (x *RocketState) GetSilent() bool {
+	if x != nil {
+		return x.Silent
+	}
+	return false
+}
+
+func (x *RocketState) GetSilentSince() *timestamppb.Timestamp {
+	if x != nil {
+		return x.SilentSince
+	}
+	return nil
+}
+
+// RocketSummary is the state of a rocket as listed, see models.RocketSummary
+type RocketSummary struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Type          string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	Speed         int64                  `protobuf:"varint,3,opt,name=speed,proto3" json:"speed,omitempty"`
+	Mission       string                 `protobuf:"bytes,4,opt,name=mission,proto3" json:"mission,omitempty"`
+	Exploded      bool                   `protobuf:"varint,5,opt,name=exploded,proto3" json:"exploded,omitempty"`
+	Status        string                 `protobuf:"bytes,6,opt,name=status,proto3" json:"status,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	Partial       bool                   `protobuf:"varint,8,opt,name=partial,proto3" json:"partial,omitempty"`
+	Silent        bool                   `protobuf:"varint,9,opt,name=silent,proto3" json:"silent,omitempty"`
+	LastSeen      *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=last_seen,json=lastSeen,proto3" json:"last_seen,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RocketSummary) Reset() {
+	*x = RocketSummary{}
+	mi := &file_rockets_v1_rockets_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RocketSummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RocketSummary) ProtoMessage() {}
+
+func (x *RocketSummary) ProtoReflect() protoreflect.Message {
+	mi := &file_rockets_v1_rockets_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RocketSummary.ProtoReflect.Descriptor instead.
+func (*RocketSummary) Descriptor() ([]byte, []int) {
+	return file_rockets_v1_rockets_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *RocketSummary) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *RocketSummary) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *RocketSummary) GetSpeed() int64 {
+	if x != nil {
+		return x.Speed
+	}
+	return 0
+}
+
+func (x *RocketSummary) GetMission() string {
+	if x != nil {
+		return x.Mission
+	}
+	return ""
+}
+
+func (x *RocketSummary) GetExploded() bool {
+	if x != nil {
+		return x.Exploded
+	}
+	return false
+}
+
+func (x *RocketSummary) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *RocketSummary) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *RocketSummary) GetPartial() bool {
+	if x != nil {
+		return x.Partial
+	}
+	return false
+}
+
+func (x *RocketSummary) GetSilent() bool {
+	if x != nil {
+		return x.Silent
+	}
+	return false
+}
+
+func (x *RocketSummary) GetLastSeen() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastSeen
+	}
+	return nil
+}
+
+type ListRocketsRequest struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	SortBy    SortField              `protobuf:"varint,1,opt,name=sort_by,json=sortBy,proto3,enum=rockets.v1.SortField" json:"sort_by,omitempty"`
+	SortOrder SortOrder              `protobuf:"varint,2,opt,name=sort_order,json=sortOrder,proto3,enum=rockets.v1.SortOrder" json:"sort_order,omitempty"`
+	// Filters, as the query parameters of GET /rockets; empty matches everything
+	Type    string `protobuf:"bytes,3,opt,name=type,proto3" json:"type,omitempty"`
+	Mission string `protobuf:"bytes,4,opt,name=mission,proto3" json:"mission,omitempty"`
+	Status  string `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"`
+	// "true" or "false"
+	Silent        string `protobuf:"bytes,6,opt,name=silent,proto3" json:"silent,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRocketsRequest) Reset() {
+	*x = ListRocketsRequest{}
+	mi := &file_rockets_v1_rockets_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRocketsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRocketsRequest) ProtoMessage() {}
+
+func (x *ListRocketsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rockets_v1_rockets_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRocketsRequest.ProtoReflect.Descriptor instead.
+func (*ListRocketsRequest) Descriptor() ([]byte, []int) {
+	return file_rockets_v1_rockets_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ListRocketsRequest) GetSortBy() SortField {
+	if x != nil {
+		return x.SortBy
+	}
+	return SortField_SORT_FIELD_UNSPECIFIED
+}
+
+func (x *ListRocketsRequest) GetSortOrder() SortOrder {
+	if x != nil {
+		return x.SortOrder
+	}
+	return SortOrder_SORT_ORDER_UNSPECIFIED
+}
+
+func (x *ListRocketsRequest) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *ListRocketsRequest) GetMission() string {
+	if x != nil {
+		return x.Mission
+	}
+	return ""
+}
+
+func (x *ListRocketsRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ListRocketsRequest) GetSilent() string {
+	if x != nil {
+		return x.Silent
+	}
+	return ""
+}
+
+type ListRocketsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rockets       []*RocketSummary       `protobuf:"bytes,1,rep,name=rockets,proto3" json:"rockets,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRocketsResponse) Reset() {
+	*x = ListRocketsResponse{}
+	mi := &file_rockets_v1_rockets_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRocketsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRocketsResponse) ProtoMessage() {}
+
+func (x *ListRocketsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rockets_v1_rockets_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRocketsResponse.ProtoReflect.Descriptor instead.
+func (*ListRocketsResponse) Descriptor() ([]byte, []int) {
+	return file_rockets_v1_rockets_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ListRocketsResponse) GetRockets() []*RocketSummary {
+	if x != nil {
+		return x.Rockets
+	}
+	return nil
+}
+
+type WatchRocketsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Event         *RocketEvent           `protobuf:"bytes,1,opt,name=event,proto3" json:"event,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchRocketsResponse) Reset() {
+	*x = WatchRocketsResponse{}
+	mi := &file_rockets_v1_rockets_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchRocketsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchRocketsResponse) ProtoMessage() {}
+
+func (x *WatchRocketsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rockets_v1_rockets_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchRocketsResponse.ProtoReflect.Descriptor instead.
+func (*WatchRocketsResponse) Descriptor() ([]byte, []int) {
+	return file_rockets_v1_rockets_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *WatchRocketsResponse) GetEvent() *RocketEvent {
+	if x != nil {
+		return x.Event
+	}
+	return nil
+}
+
+type WatchRocketsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Only this rocket, all rockets if empty
+	RocketId string `protobuf:"bytes,1,opt,name=rocket_id,json=rocketId,proto3" json:"rocket_id,omitempty"`
+	// Only rockets on this mission (case-insensitive), all if empty
+	Mission       string `protobuf:"bytes,2,opt,name=mission,proto3" json:"mission,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchRocketsRequest) Reset() {
+	*x = WatchRocketsRequest{}
+	mi := &file_rockets_v1_rockets_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchRocketsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchRocketsRequest) ProtoMessage() {}
+
+func (x *WatchRocketsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rockets_v1_rockets_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchRocketsRequest.ProtoReflect.Descriptor instead.
+func (*WatchRocketsRequest) Descriptor() ([]byte, []int) {
+	return file_rockets_v1_rockets_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *WatchRocketsRequest) GetRocketId() string {
+	if x != nil {
+		return x.RocketId
+	}
+	return ""
+}
+
+func (x *WatchRocketsRequest) GetMission() string {
+	if x != nil {
+		return x.Mission
+	}
+	return ""
+}
+
+// RocketEvent is a change to a rocket's state
+type RocketEvent struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// applied, silent, recovered or conflict, see storage.StateChange
+	Kind     string `protobuf:"bytes,1,opt,name=kind,proto3" json:"kind,omitempty"`
+	RocketId string `protobuf:"bytes,2,opt,name=rocket_id,json=rocketId,proto3" json:"rocket_id,omitempty"`
+	// Empty when the rocket went silent
+	MessageType   string                 `protobuf:"bytes,3,opt,name=message_type,json=messageType,proto3" json:"message_type,omitempty"`
+	MessageNumber int64                  `protobuf:"varint,4,opt,name=message_number,json=messageNumber,proto3" json:"message_number,omitempty"`
+	OccurredAt    *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=occurred_at,json=occurredAt,proto3" json:"occurred_at,omitempty"`
+	// Absent for a new rocket
+	Before        *RocketState `protobuf:"bytes,6,opt,name=before,proto3" json:"before,omitempty"`
+	After         *RocketState `protobuf:"bytes,7,opt,name=after,proto3" json:"after,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RocketEvent) Reset() {
+	*x = RocketEvent{}
+	mi := &file_rockets_v1_rockets_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RocketEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RocketEvent) ProtoMessage() {}
+
+func (x *RocketEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_rockets_v1_rockets_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RocketEvent.ProtoReflect.Descriptor instead.
+func (*RocketEvent) Descriptor() ([]byte, []int) {
+	return file_rockets_v1_rockets_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *RocketEvent) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *RocketEvent) GetRocketId() string {
+	if x != nil {
+		return x.RocketId
+	}
+	return ""
+}
+
+func (x *RocketEvent) GetMessageType() string {
+	if x != nil {
+		return x.MessageType
+	}
+	return ""
+}
+
+func (x *RocketEvent) GetMessageNumber() int64 {
+	if x != nil {
+		return x.MessageNumber
+	}
+	return 0
+}
+
+func (x *RocketEvent) GetOccurredAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.OccurredAt
+	}
+	return nil
+}
+
+func (x *RocketEvent) GetBefore() *RocketState {
+	if x != nil {
+		return x.Before
+	}
+	return nil
+}
+
+func (x *RocketEvent) GetAfter() *RocketState {
+	if x != nil {
+		return x.After
+	}
+	return nil
+}
+
+var File_rockets_v1_rockets_proto protoreflect.FileDescriptor
+
+const file_rockets_v1_rockets_proto_rawDesc = "" +
+	"\n" +
+	"\x18rockets/v1/rockets.proto\x12\n" +
+	"rockets.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"w\n" +
+	"\rRocketMessage\x120\n" +
+	"\bmetadata\x18\x01 \x01(\v2\x14.rockets.v1.MetadataR\bmetadata\x124\n" +
+	"\amessage\x18\x02 \x01(\v2\x1a.rockets.v1.MessageContentR\amessage\"\xad\x01\n" +
+	"\bMetadata\x12\x18\n" +
+	"\achannel\x18\x01 \x01(\tR\achannel\x12%\n" +
+	"\x0emessage_number\x18\x02 \x01(\x03R\rmessageNumber\x12=\n" +
+	"\fmessage_time\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\vmessageTime\x12!\n" +
+	"\fmessage_type\x18\x04 \x01(\tR\vmessageType\"\xcd\x01\n" +
+	"\x0eMessageContent\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\tR\x04type\x12!\n" +
+	"\flaunch_speed\x18\x02 \x01(\x03R\vlaunchSpeed\x12\x18\n" +
+	"\amission\x18\x03 \x01(\tR\amission\x12\x0e\n" +
+	"\x02by\x18\x04 \x01(\x03R\x02by\x12\x16\n" +
+	"\x06reason\x18\x05 \x01(\tR\x06reason\x12\x1f\n" +
+	"\vnew_mission\x18\x06 \x01(\tR\n" +
+	"newMission\x12!\n" +
+	"\fpayload_json\x18\a \x01(\tR\vpayloadJson\"K\n" +
+	"\x14IngestMessageRequest\x123\n" +
+	"\amessage\x18\x01 \x01(\v2\x19.rockets.v1.RocketMessageR\amessage\"\x8d\x01\n" +
+	"\x15IngestMessageResponse\x12\x1b\n" +
+	"\trocket_id\x18\x01 \x01(\tR\brocketId\x12%\n" +
+	"\x0emessage_number\x18\x02 \x01(\x03R\rmessageNumber\x12\x18\n" +
+	"\aoutcome\x18\x03 \x01(\tR\aoutcome\x12\x16\n" +
+	"\x06reason\x18\x04 \x01(\tR\x06reason\"J\n" +
+	"\x13IngestStreamRequest\x123\n" +
+	"\amessage\x18\x01 \x01(\v2\x19.rockets.v1.RocketMessageR\amessage\"\xa4\x02\n" +
+	"\x14IngestStreamResponse\x12\x1a\n" +
+	"\breceived\x18\x01 \x01(\x03R\breceived\x12\x1a\n" +
+	"\baccepted\x18\x02 \x01(\x03R\baccepted\x12\x1a\n" +
+	"\brejected\x18\x03 \x01(\x03R\brejected\x12J\n" +
+	"\boutcomes\x18\x04 \x03(\v2..rockets.v1.IngestStreamResponse.OutcomesEntryR\boutcomes\x12/\n" +
+	"\x06errors\x18\x05 \x03(\v2\x17.rockets.v1.IngestErrorR\x06errors\x1a;\n" +
+	"\rOutcomesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x03R\x05value:\x028\x01\"}\n" +
+	"\vIngestError\x12\x14\n" +
+	"\x05index\x18\x01 \x01(\x03R\x05index\x12\x1b\n" +
+	"\trocket_id\x18\x02 \x01(\tR\brocketId\x12%\n" +
+	"\x0emessage_number\x18\x03 \x01(\x03R\rmessageNumber\x12\x14\n" +
+	"\x05error\x18\x04 \x01(\tR\x05error\"\"\n" +
+	"\x10GetRocketRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"D\n" +
+	"\x11GetRocketResponse\x12/\n" +
+	"\x06rocket\x18\x01 \x01(\v2\x17.rockets.v1.RocketStateR\x06rocket\"\xe5\x04\n" +
+	"\vRocketState\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\x12\x14\n" +
+	"\x05speed\x18\x03 \x01(\x03R\x05speed\x12\x18\n" +
+	"\amission\x18\x04 \x01(\tR\amission\x12\x1a\n" +
+	"\bexploded\x18\x05 \x01(\bR\bexploded\x12\x16\n" +
+	"\x06status\x18\x06 \x01(\tR\x06status\x12\x16\n" +
+	"\x06reason\x18\a \x01(\tR\x06reason\x129\n" +
+	"\n" +
+	"created_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12\x18\n" +
+	"\apartial\x18\n" +
+	" \x01(\bR\apartial\x12+\n" +
+	"\x11bootstrapped_from\x18\v \x01(\x03R\x10bootstrappedFrom\x12!\n" +
+	"\flaunch_count\x18\f \x01(\x03R\vlaunchCount\x127\n" +
+	"\tlast_seen\x18\r \x01(\v2\x1a.google.protobuf.TimestampR\blastSeen\x12F\n" +
+	"\x11last_message_time\x18\x0e \x01(\v2\x1a.google.protobuf.TimestampR\x0flastMessageTime\x12\x16\n" +
+	"\x06silent\x18\x0f \x01(\bR\x06silent\x12=\n" +
+	"\fsilent_since\x18\x10 \x01(\v2\x1a.google.protobuf.TimestampR\vsilentSince\"\xbd\x02\n" +
+	"\rRocketSummary\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\x12\x14\n" +
+	"\x05speed\x18\x03 \x01(\x03R\x05speed\x12\x18\n" +
+	"\amission\x18\x04 \x01(\tR\amission\x12\x1a\n" +
+	"\bexploded\x18\x05 \x01(\bR\bexploded\x12\x16\n" +
+	"\x06status\x18\x06 \x01(\tR\x06status\x129\n" +
+	"\n" +
+	"updated_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12\x18\n" +
+	"\apartial\x18\b \x01(\bR\apartial\x12\x16\n" +
+	"\x06silent\x18\t \x01(\bR\x06silent\x127\n" +
+	"\tlast_seen\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\blastSeen\"\xd8\x01\n" +
+	"\x12ListRocketsRequest\x12.\n" +
+	"\asort_by\x18\x01 \x01(\x0e2\x15.rockets.v1.SortFieldR\x06sortBy\x124\n" +
+	"\n" +
+	"sort_order\x18\x02 \x01(\x0e2\x15.rockets.v1.SortOrderR\tsortOrder\x12\x12\n" +
+	"\x04type\x18\x03 \x01(\tR\x04type\x12\x18\n" +
+	"\amission\x18\x04 \x01(\tR\amission\x12\x16\n" +
+	"\x06status\x18\x05 \x01(\tR\x06status\x12\x16\n" +
+	"\x06silent\x18\x06 \x01(\tR\x06silent\"J\n" +
+	"\x13ListRocketsResponse\x123\n" +
+	"\arockets\x18\x01 \x03(\v2\x19.rockets.v1.RocketSummaryR\arockets\"E\n" +
+	"\x14WatchRocketsResponse\x12-\n" +
+	"\x05event\x18\x01 \x01(\v2\x17.rockets.v1.RocketEventR\x05event\"L\n" +
+	"\x13WatchRocketsRequest\x12\x1b\n" +
+	"\trocket_id\x18\x01 \x01(\tR\brocketId\x12\x18\n" +
+	"\amission\x18\x02 \x01(\tR\amission\"\xa5\x02\n" +
+	"\vRocketEvent\x12\x12\n" +
+	"\x04kind\x18\x01 \x01(\tR\x04kind\x12\x1b\n" +
+	"\trocket_id\x18\x02 \x01(\tR\brocketId\x12!\n" +
+	"\fmessage_type\x18\x03 \x01(\tR\vmessageType\x12%\n" +
+	"\x0emessage_number\x18\x04 \x01(\x03R\rmessageNumber\x12;\n" +
+	"\voccurred_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"occurredAt\x12/\n" +
+	"\x06before\x18\x06 \x01(\v2\x17.rockets.v1.RocketStateR\x06before\x12-\n" +
+	"\x05after\x18\a \x01(\v2\x17.rockets.v1.RocketStateR\x05after*\xc8\x01\n" +
+	"\tSortField\x12\x1a\n" +
+	"\x16SORT_FIELD_UNSPECIFIED\x10\x00\x12\x11\n" +
+	"\rSORT_FIELD_ID\x10\x01\x12\x13\n" +
+	"\x0fSORT_FIELD_TYPE\x10\x02\x12\x14\n" +
+	"\x10SORT_FIELD_SPEED\x10\x03\x12\x16\n" +
+	"\x12SORT_FIELD_MISSION\x10\x04\x12\x17\n" +
+	"\x13SORT_FIELD_EXPLODED\x10\x05\x12\x15\n" +
+	"\x11SORT_FIELD_STATUS\x10\x06\x12\x19\n" +
+	"\x15SORT_FIELD_UPDATED_AT\x10\a*P\n" +
+	"\tSortOrder\x12\x1a\n" +
+	"\x16SORT_ORDER_UNSPECIFIED\x10\x00\x12\x12\n" +
+	"\x0eSORT_ORDER_ASC\x10\x01\x12\x13\n" +
+	"\x0fSORT_ORDER_DESC\x10\x022\xa9\x03\n" +
+	"\rRocketService\x12T\n" +
+	"\rIngestMessage\x12 .rockets.v1.IngestMessageRequest\x1a!.rockets.v1.IngestMessageResponse\x12S\n" +
+	"\fIngestStream\x12\x1f.rockets.v1.IngestStreamRequest\x1a .rockets.v1.IngestStreamResponse(\x01\x12H\n" +
+	"\tGetRocket\x12\x1c.rockets.v1.GetRocketRequest\x1a\x1d.rockets.v1.GetRocketResponse\x12N\n" +
+	"\vListRockets\x12\x1e.rockets.v1.ListRocketsRequest\x1a\x1f.rockets.v1.ListRocketsResponse\x12S\n" +
+	"\fWatchRockets\x12\x1f.rockets.v1.WatchRocketsRequest\x1a .rockets.v1.WatchRocketsResponse0\x01B>Z<lunar-backend-challenge/internal/grpcapi/rocketsv1;rocketsv1b\x06proto3"
+
+var (
+	file_rockets_v1_rockets_proto_rawDescOnce sync.Once
+	file_rockets_v1_rockets_proto_rawDescData []byte
+)
+
+func file_rockets_v1_rockets_proto_rawDescGZIP() []byte {
+	file_rockets_v1_rockets_proto_rawDescOnce.Do(func() {
+		file_rockets_v1_rockets_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_rockets_v1_rockets_proto_rawDesc), len(file_rockets_v1_rockets_proto_rawDesc)))
+	})
+	return file_rockets_v1_rockets_proto_rawDescData
+}
+
+var file_rockets_v1_rockets_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_rockets_v1_rockets_proto_msgTypes = make([]protoimpl.MessageInfo, 18)
+var file_rockets_v1_rockets_proto_goTypes = []any{
+	(SortField)(0),                // 0: rockets.v1.SortField
+	(SortOrder)(0),                // 1: rockets.v1.SortOrder
+	(*RocketMessage)(nil),         // 2: rockets.v1.RocketMessage
+	(*Metadata)(nil),              // 3: rockets.v1.Metadata
+	(*MessageContent)(nil),        // 4: rockets.v1.MessageContent
+	(*IngestMessageRequest)(nil),  // 5: rockets.v1.IngestMessageRequest
+	(*IngestMessageResponse)(nil), // 6: rockets.v1.IngestMessageResponse
+	(*IngestStreamRequest)(nil),   // 7: rockets.v1.IngestStreamRequest
+	(*IngestStreamResponse)(nil),  // 8: rockets.v1.IngestStreamResponse
+	(*IngestError)(nil),           // 9: rockets.v1.IngestError
+	(*GetRocketRequest)(nil),      // 10: rockets.v1.GetRocketRequest
+	(*GetRocketResponse)(nil),     // 11: rockets.v1.GetRocketResponse
+	(*RocketState)(nil),           // 12: rockets.v1.RocketState
+	(*RocketSummary)(nil),         // 13: rockets.v1.RocketSummary
+	(*ListRocketsRequest)(nil),    // 14: rockets.v1.ListRocketsRequest
+	(*ListRocketsResponse)(nil),   // 15: rockets.v1.ListRocketsResponse
+	(*WatchRocketsResponse)(nil),  // 16: rockets.v1.WatchRocketsResponse
+	(*WatchRocketsRequest)(nil),   // 17: rockets.v1.WatchRocketsRequest
+	(*RocketEvent)(nil),           // 18: rockets.v1.RocketEvent
+	nil,                           // 19: rockets.v1.IngestStreamResponse.OutcomesEntry
+	(*timestamppb.Timestamp)(nil), // 20: google.protobuf.Timestamp
+}
+var file_rockets_v1_rockets_proto_depIdxs = []int32{
+	3,  // 0: rockets.v1.RocketMessage.metadata:type_name -> rockets.v1.Metadata
+	4,  // 1: rockets.v1.RocketMessage.message:type_name -> rockets.v1.MessageContent
+	20, // 2: rockets.v1.Metadata.message_time:type_name -> google.protobuf.Timestamp
+	2,  // 3: rockets.v1.IngestMessageRequest.message:type_name -> rockets.v1.RocketMessage
+	2,  // 4: rockets.v1.IngestStreamRequest.message:type_name -> rockets.v1.RocketMessage
+	19, // 5: rockets.v1.IngestStreamResponse.outcomes:type_name -> rockets.v1.IngestStreamResponse.OutcomesEntry
+	9,  // 6: rockets.v1.IngestStreamResponse.errors:type_name -> rockets.v1.IngestError
+	12, // 7: rockets.v1.GetRocketResponse.rocket:type_name -> rockets.v1.RocketState
+	20, // 8: rockets.v1.RocketState.created_at:type_name -> google.protobuf.Timestamp
+	20, // 9: rockets.v1.RocketState.updated_at:type_name -> google.protobuf.Timestamp
+	20, // 10: rockets.v1.RocketState.last_seen:type_name -> google.protobuf.Timestamp
+	20, // 11: rockets.v1.RocketState.last_message_time:type_name -> google.protobuf.Timestamp
+	20, // 12: rockets.v1.RocketState.silent_since:type_name -> google.protobuf.Timestamp
+	20, // 13: rockets.v1.RocketSummary.updated_at:type_name -> google.protobuf.Timestamp
+	20, // 14: rockets.v1.RocketSummary.last_seen:type_name -> google.protobuf.Timestamp
+	0,  // 15: rockets.v1.ListRocketsRequest.sort_by:type_name -> rockets.v1.SortField
+	1,  // 16: rockets.v1.ListRocketsRequest.sort_order:type_name -> rockets.v1.SortOrder
+	13, // 17: rockets.v1.ListRocketsResponse.rockets:type_name -> rockets.v1.RocketSummary
+	18, // 18: rockets.v1.WatchRocketsResponse.event:type_name -> rockets.v1.RocketEvent
+	20, // 19: rockets.v1.RocketEvent.occurred_at:type_name -> google.protobuf.Timestamp
+	12, // 20: rockets.v1.RocketEvent.before:type_name -> rockets.v1.RocketState
+	12, // 21: rockets.v1.RocketEvent.after:type_name -> rockets.v1.RocketState
+	5,  // 22: rockets.v1.RocketService.IngestMessage:input_type -> rockets.v1.IngestMessageRequest
+	7,  // 23: rockets.v1.RocketService.IngestStream:input_type -> rockets.v1.IngestStreamRequest
+	10, // 24: rockets.v1.RocketService.GetRocket:input_type -> rockets.v1.GetRocketRequest
+	14, // 25: rockets.v1.RocketService.ListRockets:input_type -> rockets.v1.ListRocketsRequest
+	17, // 26: rockets.v1.RocketService.WatchRockets:input_type -> rockets.v1.WatchRocketsRequest
+	6,  // 27: rockets.v1.RocketService.IngestMessage:output_type -> rockets.v1.IngestMessageResponse
+	8,  // 28: rockets.v1.RocketService.IngestStream:output_type -> rockets.v1.IngestStreamResponse
+	11, // 29: rockets.v1.RocketService.GetRocket:output_type -> rockets.v1.GetRocketResponse
+	15, // 30: rockets.v1.RocketService.ListRockets:output_type -> rockets.v1.ListRocketsResponse
+	16, // 31: rockets.v1.RocketService.WatchRockets:output_type -> rockets.v1.WatchRocketsResponse
+	27, // [27:32] is the sub-list for method output_type
+	22, // [22:27] is the sub-list for method input_type
+	22, // [22:22] is the sub-list for extension type_name
+	22, // [22:22] is the sub-list for extension extendee
+	0,  // [0:22] is the sub-list for field type_name
+}
+
+func init() { file_rockets_v1_rockets_proto_init() }
+func file_rockets_v1_rockets_proto_init() {
+	if File_rockets_v1_rockets_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_rockets_v1_rockets_proto_rawDesc), len(file_rockets_v1_rockets_proto_rawDesc)),
+			NumEnums:      2,
+			NumMessages:   18,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_rockets_v1_rockets_proto_goTypes,
+		DependencyIndexes: file_rockets_v1_rockets_proto_depIdxs,
+		EnumInfos:         file_rockets_v1_rockets_proto_enumTypes,
+		MessageInfos:      file_rockets_v1_rockets_proto_msgTypes,
+	}.Build()
+	File_rockets_v1_rockets_proto = out.File
+	file_rockets_v1_rockets_proto_goTypes = nil
+	file_rockets_v1_rockets_proto_depIdxs = nil
+}
@@ -0,0 +1,288 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: rockets/v1/rockets.proto
+
+package rocketsv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	RocketService_IngestMessage_FullMethodName = "/rockets.v1.RocketService/IngestMessage"
+	RocketService_IngestStream_FullMethodName  = "/rockets.v1.RocketService/IngestStream"
+	RocketService_GetRocket_FullMethodName     = "/rockets.v1.RocketService/GetRocket"
+	RocketService_ListRockets_FullMethodName   = "/rockets.v1.RocketService/ListRockets"
+	RocketService_WatchRockets_FullMethodName  = "/rockets.v1.RocketService/WatchRockets"
+)
+
+// RocketServiceClient is the client API for RocketService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// RocketService mirrors the REST API: ingestion of rocket messages and queries of rocket state.
+// It serves the same repository as the HTTP server.
+type RocketServiceClient interface {
+	// IngestMessage processes a single rocket message, like POST /messages
+	IngestMessage(ctx context.Context, in *IngestMessageRequest, opts ...grpc.CallOption) (*IngestMessageResponse, error)
+	// IngestStream processes a stream of rocket messages and reports the outcome of each once the client closes the stream.
+	// Rejected messages do not end the stream.
+	IngestStream(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[IngestStreamRequest, IngestStreamResponse], error)
+	// GetRocket returns a rocket by ID, like GET /rockets/{id}
+	GetRocket(ctx context.Context, in *GetRocketRequest, opts ...grpc.CallOption) (*GetRocketResponse, error)
+	// ListRockets returns the rockets with optional filtering and sorting, like GET /rockets
+	ListRockets(ctx context.Context, in *ListRocketsRequest, opts ...grpc.CallOption) (*ListRocketsResponse, error)
+	// WatchRockets streams rocket state changes as they happen, until the client cancels
+	WatchRockets(ctx context.Context, in *WatchRocketsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WatchRocketsResponse], error)
+}
+
+type rocketServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewRocketServiceClient(cc grpc.ClientConnInterface) RocketServiceClient {
+	return &rocketServiceClient{cc}
+}
+
+func (c *rocketServiceClient) IngestMessage(ctx context.Context, in *IngestMessageRequest, opts ...grpc.CallOption) (*IngestMessageResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(IngestMessageResponse)
+	err := c.cc.Invoke(ctx, RocketService_IngestMessage_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rocketServiceClient) IngestStream(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[IngestStreamRequest, IngestStreamResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &RocketService_ServiceDesc.Streams[0], RocketService_IngestStream_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[IngestStreamRequest, IngestStreamResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type RocketService_IngestStreamClient = grpc.ClientStreamingClient[IngestStreamRequest, IngestStreamResponse]
+
+func (c *rocketServiceClient) GetRocket(ctx context.Context, in *GetRocketRequest, opts ...grpc.CallOption) (*GetRocketResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetRocketResponse)
+	err := c.cc.Invoke(ctx, RocketService_GetRocket_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rocketServiceClient) ListRockets(ctx context.Context, in *ListRocketsRequest, opts ...grpc.CallOption) (*ListRocketsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListRocketsResponse)
+	err := c.cc.Invoke(ctx, RocketService_ListRockets_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rocketServiceClient) WatchRockets(ctx context.Context, in *WatchRocketsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WatchRocketsResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &RocketService_ServiceDesc.Streams[1], RocketService_WatchRockets_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchRocketsRequest, WatchRocketsResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type RocketService_WatchRocketsClient = grpc.ServerStreamingClient[WatchRocketsResponse]
+
+// RocketServiceServer is the server API for RocketService service.
+// All implementations must embed UnimplementedRocketServiceServer
+// for forward compatibility.
+//
+// RocketService mirrors the REST API: ingestion of rocket messages and queries of rocket state.
+// It serves the same repository as the HTTP server.
+type RocketServiceServer interface {
+	// IngestMessage processes a single rocket message, like POST /messages
+	IngestMessage(context.Context, *IngestMessageRequest) (*IngestMessageResponse, error)
+	// IngestStream processes a stream of rocket messages and reports the outcome of each once the client closes the stream.
+	// Rejected messages do not end the stream.
+	IngestStream(grpc.ClientStreamingServer[IngestStreamRequest, IngestStreamResponse]) error
+	// GetRocket returns a rocket by ID, like GET /rockets/{id}
+	GetRocket(context.Context, *GetRocketRequest) (*GetRocketResponse, error)
+	// ListRockets returns the rockets with optional filtering and sorting, like GET /rockets
+	ListRockets(context.Context, *ListRocketsRequest) (*ListRocketsResponse, error)
+	// WatchRockets streams rocket state changes as they happen, until the client cancels
+	WatchRockets(*WatchRocketsRequest, grpc.ServerStreamingServer[WatchRocketsResponse]) error
+	mustEmbedUnimplementedRocketServiceServer()
+}
+
+// UnimplementedRocketServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedRocketServiceServer struct{}
+
+func (UnimplementedRocketServiceServer) IngestMessage(context.Context, *IngestMessageRequest) (*IngestMessageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method IngestMessage not implemented")
+}
+func (UnimplementedRocketServiceServer) IngestStream(grpc.ClientStreamingServer[IngestStreamRequest, IngestStreamResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method IngestStream not implemented")
+}
+func (UnimplementedRocketServiceServer) GetRocket(context.Context, *GetRocketRequest) (*GetRocketResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRocket not implemented")
+}
+func (UnimplementedRocketServiceServer) ListRockets(context.Context, *ListRocketsRequest) (*ListRocketsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListRockets not implemented")
+}
+func (UnimplementedRocketServiceServer) WatchRockets(*WatchRocketsRequest, grpc.ServerStreamingServer[WatchRocketsResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchRockets not implemented")
+}
+func (UnimplementedRocketServiceServer) mustEmbedUnimplementedRocketServiceServer() {}
+func (UnimplementedRocketServiceServer) testEmbeddedByValue()                       {}
+
+// UnsafeRocketServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to RocketServiceServer will
+// result in compilation errors.
+type UnsafeRocketServiceServer interface {
+	mustEmbedUnimplementedRocketServiceServer()
+}
+
+func RegisterRocketServiceServer(s grpc.ServiceRegistrar, srv RocketServiceServer) {
+	// If the following call pancis, it indicates UnimplementedRocketServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&RocketService_ServiceDesc, srv)
+}
+
+func _RocketService_IngestMessage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IngestMessageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RocketServiceServer).IngestMessage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RocketService_IngestMessage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RocketServiceServer).IngestMessage(ctx, req.(*IngestMessageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RocketService_IngestStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(RocketServiceServer).IngestStream(&grpc.GenericServerStream[IngestStreamRequest, IngestStreamResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type RocketService_IngestStreamServer = grpc.ClientStreamingServer[IngestStreamRequest, IngestStreamResponse]
+
+func _RocketService_GetRocket_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRocketRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RocketServiceServer).GetRocket(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RocketService_GetRocket_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RocketServiceServer).GetRocket(ctx, req.(*GetRocketRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RocketService_ListRockets_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRocketsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RocketServiceServer).ListRockets(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RocketService_ListRockets_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RocketServiceServer).ListRockets(ctx, req.(*ListRocketsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RocketService_WatchRockets_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchRocketsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(RocketServiceServer).WatchRockets(m, &grpc.GenericServerStream[WatchRocketsRequest, WatchRocketsResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type RocketService_WatchRocketsServer = grpc.ServerStreamingServer[WatchRocketsResponse]
+
+// RocketService_ServiceDesc is the grpc.ServiceDesc for RocketService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var RocketService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "rockets.v1.RocketService",
+	HandlerType: (*RocketServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "IngestMessage",
+			Handler:    _RocketService_IngestMessage_Handler,
+		},
+		{
+			MethodName: "GetRocket",
+			Handler:    _RocketService_GetRocket_Handler,
+		},
+		{
+			MethodName: "ListRockets",
+			Handler:    _RocketService_ListRockets_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "IngestStream",
+			Handler:       _RocketService_IngestStream_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "WatchRockets",
+			Handler:       _RocketService_WatchRockets_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "rockets/v1/rockets.proto",
+}
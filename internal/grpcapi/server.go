@@ -0,0 +1,162 @@
+package grpcapi
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"lunar-backend-challenge/internal/deadletter"
+	"lunar-backend-challenge/internal/feed"
+	"lunar-backend-challenge/internal/filtering"
+	"lunar-backend-challenge/internal/grpcapi/rocketsv1"
+	"lunar-backend-challenge/internal/ingest"
+	"lunar-backend-challenge/internal/lifecycle"
+	"lunar-backend-challenge/internal/sorting"
+	"lunar-backend-challenge/internal/storage"
+	"lunar-backend-challenge/internal/validation"
+)
+
+// Server implements the RocketService gRPC API on top of a repository
+type Server struct {
+	rocketsv1.UnimplementedRocketServiceServer
+
+	Repository *storage.RocketRepository
+	Ingest     *ingest.Service
+	Feed       *feed.Feed
+}
+
+// NewServer creates a gRPC server for the repository, dead-lettering rejected messages in
+// deadLetters (nil to disable), and subscribes it to the repository's state changes
+func NewServer(repository *storage.RocketRepository, deadLetters *deadletter.Store) *Server {
+	server := &Server{
+		Repository: repository,
+		Ingest:     ingest.NewService(repository, deadLetters),
+		Feed:       feed.New(),
+	}
+	repository.Subscribe(server.Feed.Observe)
+	return server
+}
+
+// IngestMessage processes a single rocket message
+func (s *Server) IngestMessage(ctx context.Context, request *rocketsv1.IngestMessageRequest) (*rocketsv1.IngestMessageResponse, error) {
+	return s.ingest(request.GetMessage())
+}
+
+// IngestStream processes a stream of rocket messages, reporting every outcome when the stream ends
+func (s *Server) IngestStream(stream rocketsv1.RocketService_IngestStreamServer) error {
+	response := &rocketsv1.IngestStreamResponse{Outcomes: make(map[string]int64)}
+	for index := int64(0); ; index++ {
+		request, err := stream.Recv()
+		if err == io.EOF {
+			return stream.SendAndClose(response)
+		}
+		if err != nil {
+			return err
+		}
+
+		response.Received++
+		result, err := s.ingest(request.GetMessage())
+		if err != nil {
+			response.Rejected++
+			response.Errors = append(response.Errors, &rocketsv1.IngestError{
+				Index:         index,
+				RocketId:      request.GetMessage().GetMetadata().GetChannel(),
+				MessageNumber: request.GetMessage().GetMetadata().GetMessageNumber(),
+				Error:         status.Convert(err).Message(),
+			})
+			continue
+		}
+		response.Accepted++
+		response.Outcomes[result.Outcome]++
+	}
+}
+
+// ingest runs a message through the ingestion pipeline shared with POST /messages
+func (s *Server) ingest(msg *rocketsv1.RocketMessage) (*rocketsv1.IngestMessageResponse, error) {
+	if msg == nil {
+		return nil, invalidArgument("message is required")
+	}
+	body, err := encodeMessage(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	message, result, err := s.Ingest.Ingest(body, time.Now())
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return &rocketsv1.IngestMessageResponse{
+		RocketId:      message.GetChannel(),
+		MessageNumber: int64(message.GetMessageNumber()),
+		Outcome:       string(result.Outcome),
+		Reason:        result.Reason,
+	}, nil
+}
+
+// GetRocket returns a rocket by ID
+func (s *Server) GetRocket(ctx context.Context, request *rocketsv1.GetRocketRequest) (*rocketsv1.GetRocketResponse, error) {
+	if err := validation.ValidateRocketID(request.GetId()); err != nil {
+		return nil, statusError(err)
+	}
+
+	rocket, exists := s.Repository.GetRocket(request.GetId())
+	if !exists {
+		return nil, status.Errorf(codes.NotFound, "no rocket found with ID: %s", request.GetId())
+	}
+	return &rocketsv1.GetRocketResponse{Rocket: toRocketState(rocket)}, nil
+}
+
+// ListRockets returns the rockets with optional filtering and sorting
+func (s *Server) ListRockets(ctx context.Context, request *rocketsv1.ListRocketsRequest) (*rocketsv1.ListRocketsResponse, error) {
+	sortBy, valid := sortFields[request.GetSortBy()]
+	if !valid {
+		return nil, invalidArgument("invalid sort field %d", request.GetSortBy())
+	}
+	sortOrder, valid := sortOrders[request.GetSortOrder()]
+	if !valid {
+		return nil, invalidArgument("invalid sort order %d", request.GetSortOrder())
+	}
+
+	filter := filtering.Filter{
+		Type:    request.GetType(),
+		Mission: request.GetMission(),
+		Status:  request.GetStatus(),
+		Silent:  request.GetSilent(),
+	}
+	if !filtering.ValidateStatus(filter.Status) {
+		return nil, invalidArgument("invalid status filter %q, valid statuses are: %v", filter.Status, lifecycle.Statuses)
+	}
+	if !filtering.ValidateSilent(filter.Silent) {
+		return nil, invalidArgument("invalid silent filter %q, valid values are: true, false", filter.Silent)
+	}
+
+	rockets := sorting.SortRockets(filtering.FilterRockets(s.Repository.GetAllRockets(), filter), sortBy, sortOrder)
+	response := &rocketsv1.ListRocketsResponse{Rockets: make([]*rocketsv1.RocketSummary, len(rockets))}
+	for i, rocket := range rockets {
+		response.Rockets[i] = toRocketSummary(rocket)
+	}
+	return response, nil
+}
+
+// WatchRockets streams the state changes that pass the request filters until the client cancels.
+// A client that does not keep up is cut off with ResourceExhausted.
+func (s *Server) WatchRockets(request *rocketsv1.WatchRocketsRequest, stream rocketsv1.RocketService_WatchRocketsServer) error {
+	watcher := s.Feed.Watch(feed.Filter{RocketID: request.GetRocketId(), Mission: request.GetMission()}, feed.DefaultBuffer)
+	defer watcher.Close()
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case <-watcher.Overflow():
+			return status.Error(codes.ResourceExhausted, "watcher fell behind, events were dropped")
+		case change := <-watcher.Events():
+			if err := stream.Send(&rocketsv1.WatchRocketsResponse{Event: toRocketEvent(change)}); err != nil {
+				return err
+			}
+		}
+	}
+}
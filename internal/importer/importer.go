@@ -0,0 +1,127 @@
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"lunar-backend-challenge/internal/storage"
+)
+
+// DefaultProgressEvery is the number of lines between progress reports
+const DefaultProgressEvery = 1000
+
+// maxLineSize is the longest line accepted in a message file
+const maxLineSize = 4 << 20
+
+// Importer feeds NDJSON message files, one JSON message per line, into a sink
+type Importer struct {
+	Sink          Sink
+	Progress      io.Writer // Receives progress reports, nil to disable
+	ProgressEvery int       // Lines between progress reports, DefaultProgressEvery if <= 0
+}
+
+// Failure is a line whose message was dead-lettered, or would have been in a dry run
+type Failure struct {
+	Source  string `json:"source"`
+	Line    int    `json:"line"`
+	Outcome string `json:"outcome"` // rejected, or conflict for an accepted conflicting duplicate
+	Error   string `json:"error"`
+}
+
+// Report sums up an import
+type Report struct {
+	Lines    int            `json:"lines"`    // Non-empty lines applied
+	Outcomes map[string]int `json:"outcomes"` // Messages per outcome
+	Failures []Failure      `json:"failures"` // Dead-lettered lines, in input order
+}
+
+// NewReport creates an empty report
+func NewReport() *Report {
+	return &Report{Outcomes: make(map[string]int), Failures: []Failure{}}
+}
+
+// String summarizes the report on one line, outcomes in alphabetical order
+func (r *Report) String() string {
+	outcomes := make([]string, 0, len(r.Outcomes))
+	for outcome := range r.Outcomes {
+		outcomes = append(outcomes, outcome)
+	}
+	sort.Strings(outcomes)
+
+	var summary strings.Builder
+	fmt.Fprintf(&summary, "%d lines", r.Lines)
+	for _, outcome := range outcomes {
+		fmt.Fprintf(&summary, ", %d %s", r.Outcomes[outcome], outcome)
+	}
+	fmt.Fprintf(&summary, ", %d dead-lettered", len(r.Failures))
+	return summary.String()
+}
+
+// Import applies every line of input to the sink in order, adding the results to report.
+// source names the input in failures and progress reports. Only read and delivery errors are
+// returned; rejected messages are recorded in the report.
+func (i *Importer) Import(source string, input io.Reader, report *Report) error {
+	progressEvery := i.ProgressEvery
+	if progressEvery <= 0 {
+		progressEvery = DefaultProgressEvery
+	}
+
+	scanner := bufio.NewScanner(input)
+	scanner.Buffer(make([]byte, 64*1024), maxLineSize)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		outcome, err := i.Sink.Apply(line)
+		if outcome == "" {
+			return fmt.Errorf("%s line %d: %w", source, lineNumber, err)
+		}
+		report.Lines++
+		report.Outcomes[outcome]++
+		switch {
+		case err != nil:
+			report.Failures = append(report.Failures, Failure{Source: source, Line: lineNumber, Outcome: outcome, Error: err.Error()})
+		case outcome == string(storage.OutcomeConflict):
+			// Conflicting duplicates are dead-lettered even when the conflict policy accepts them
+			report.Failures = append(report.Failures, Failure{Source: source, Line: lineNumber, Outcome: outcome, Error: "conflicting duplicate"})
+		}
+
+		if i.Progress != nil && report.Lines%progressEvery == 0 {
+			fmt.Fprintf(i.Progress, "%s: line %d, %s\n", source, lineNumber, report)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read %s: %w", source, err)
+	}
+	return nil
+}
+
+// ImportFiles imports the files at paths in order, "-" meaning standard input
+func (i *Importer) ImportFiles(paths []string, report *Report) error {
+	for _, path := range paths {
+		if path == "-" {
+			if err := i.Import("stdin", os.Stdin, report); err != nil {
+				return err
+			}
+			continue
+		}
+
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		err = i.Import(path, file, report)
+		file.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
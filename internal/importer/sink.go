@@ -0,0 +1,111 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"lunar-backend-challenge/internal/ingest"
+	"lunar-backend-challenge/internal/registry"
+	"lunar-backend-challenge/internal/storage"
+	"lunar-backend-challenge/internal/validation"
+)
+
+// OutcomeValid is the outcome of a message that passed a dry run
+const OutcomeValid = "valid"
+
+// Sink applies a raw JSON message and returns its outcome, a storage.Outcome or OutcomeValid,
+// with an error if the message was rejected. An empty outcome means the message could not be
+// delivered at all, which stops the import.
+type Sink interface {
+	Apply(body []byte) (string, error)
+}
+
+// RepositorySink applies messages directly through the ingestion pipeline of a repository,
+// so rejected messages end up in its dead-letter store like those posted to /messages
+type RepositorySink struct {
+	Ingest *ingest.Service
+}
+
+// Apply ingests a message, see ingest.Service.Ingest
+func (s RepositorySink) Apply(body []byte) (string, error) {
+	_, result, err := s.Ingest.Ingest(body, time.Now())
+	if err != nil {
+		return string(storage.OutcomeRejected), err
+	}
+	return string(result.Outcome), nil
+}
+
+// HTTPSink posts messages to the /messages endpoint of a running server
+type HTTPSink struct {
+	URL    string // Base URL of the server, e.g. http://localhost:8088
+	Client *http.Client
+}
+
+// NewHTTPSink creates a sink posting to the server at baseURL
+func NewHTTPSink(baseURL string) *HTTPSink {
+	return &HTTPSink{
+		URL:    strings.TrimSuffix(baseURL, "/"),
+		Client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Apply posts a message and returns the outcome reported by the server
+func (s *HTTPSink) Apply(body []byte) (string, error) {
+	resp, err := s.Client.Post(s.URL+"/messages", "application/json", bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("failed to post message: %w", err)
+	}
+	defer resp.Body.Close()
+
+	payload, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var failure struct {
+			Error struct {
+				Message string `json:"message"`
+				Details string `json:"details"`
+			} `json:"error"`
+		}
+		if json.Unmarshal(payload, &failure) != nil || failure.Error.Message == "" {
+			return string(storage.OutcomeRejected), fmt.Errorf("server answered %s", resp.Status)
+		}
+		return string(storage.OutcomeRejected), fmt.Errorf("%s: %s", failure.Error.Message, failure.Error.Details)
+	}
+
+	var response struct {
+		Outcome string `json:"outcome"`
+	}
+	if err := json.Unmarshal(payload, &response); err != nil {
+		return "", fmt.Errorf("failed to decode response: %w", err)
+	}
+	return response.Outcome, nil
+}
+
+// DryRunSink only decodes and validates messages, without applying them anywhere
+type DryRunSink struct {
+	Registry *registry.Registry // Message types to check against, registry.Default if nil
+}
+
+// Apply checks that a message would pass decoding and validation
+func (s DryRunSink) Apply(body []byte) (string, error) {
+	types := s.Registry
+	if types == nil {
+		types = registry.Default
+	}
+	message, err := types.DecodeMessage(body)
+	if err != nil {
+		return string(storage.OutcomeRejected), fmt.Errorf("invalid JSON: %w", err)
+	}
+	if err := validation.ValidateRocketMessageWith(types, message); err != nil {
+		return string(storage.OutcomeRejected), err
+	}
+	return OutcomeValid, nil
+}
@@ -0,0 +1,132 @@
+package ingest
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"lunar-backend-challenge/internal/deadletter"
+	"lunar-backend-challenge/internal/errors"
+	"lunar-backend-challenge/internal/models"
+	"lunar-backend-challenge/internal/registry"
+	"lunar-backend-challenge/internal/storage"
+	"lunar-backend-challenge/internal/validation"
+)
+
+// Service runs raw rocket messages through decoding, validation and the repository.
+// Messages rejected along the way are kept in the dead-letter store instead of being lost.
+type Service struct {
+	Repository  *storage.RocketRepository
+	DeadLetters *deadletter.Store
+	Registry    *registry.Registry // Message types messages are decoded and validated with
+}
+
+// NewService creates an ingestion service decoding and validating messages with the message types
+// of the repository; deadLetters may be nil to disable dead-lettering
+func NewService(repository *storage.RocketRepository, deadLetters *deadletter.Store) *Service {
+	return &Service{
+		Repository:  repository,
+		DeadLetters: deadLetters,
+		Registry:    repository.Registry(),
+	}
+}
+
+// Ingest decodes, validates and processes a raw JSON message. The returned error is an
+// errors.APIError, errors.ValidationError or errors.MessageProcessingError.
+// Conflicting duplicates are dead-lettered even when the conflict policy accepts them.
+func (s *Service) Ingest(body []byte, receivedAt time.Time) (*models.RocketMessage, storage.ProcessResult, error) {
+	message, result, reason, err := s.process(body, receivedAt)
+	if err != nil && s.DeadLetters != nil {
+		s.DeadLetters.Add(newEntry(message, reason, err, body, receivedAt))
+	}
+	if result.Outcome == storage.OutcomeConflict {
+		return message, result, nil
+	}
+	return message, result, err
+}
+
+// Replay processes a dead-lettered message again, using body instead of the stored
+// message when it is not empty. The entry is removed on success and updated on failure.
+func (s *Service) Replay(entry deadletter.Entry, body []byte, receivedAt time.Time) (*models.RocketMessage, storage.ProcessResult, error) {
+	if len(body) == 0 {
+		body = []byte(entry.Body)
+	}
+
+	message, result, reason, err := s.process(body, receivedAt)
+	if s.DeadLetters == nil {
+		return message, result, err
+	}
+
+	if err != nil {
+		s.DeadLetters.MarkReplayFailed(entry.ID, newEntry(message, reason, err, body, receivedAt))
+		return message, result, err
+	}
+
+	s.DeadLetters.MarkReplayed(entry.ID)
+	return message, result, nil
+}
+
+// process runs a message through the pipeline, returning the dead-letter reason on failure
+func (s *Service) process(body []byte, receivedAt time.Time) (*models.RocketMessage, storage.ProcessResult, string, error) {
+	// Decode JSON, using the payload decoder registered for the message type
+	message, err := s.Registry.DecodeMessage(body)
+	if err != nil {
+		log.Printf("Failed to decode JSON: %v", err)
+		return nil, storage.ProcessResult{}, deadletter.ReasonInvalidJSON, errors.NewAPIError(http.StatusBadRequest, "Invalid JSON format", err.Error())
+	}
+
+	// Validate message
+	if err := validation.ValidateRocketMessageWith(s.Registry, message); err != nil {
+		log.Printf("Message validation failed: %v", err)
+		return message, storage.ProcessResult{}, deadletter.ReasonValidationFailed, err
+	}
+
+	// Log incoming message for debugging
+	log.Printf("Received message: Channel=%s, MsgNum=%d, Type=%s",
+		message.GetChannel(), message.GetMessageNumber(), message.GetMessageType())
+
+	// Process the message
+	message.ReceivedAt = receivedAt
+	result := s.Repository.Process(message)
+	if result.Conflict != nil {
+		conflictErr := errors.NewAPIError(http.StatusConflict, "Conflicting duplicate message", result.Reason)
+		log.Printf("Conflicting message: Channel=%s, MsgNum=%d, Type=%s, Outcome=%s",
+			message.GetChannel(), message.GetMessageNumber(), message.GetMessageType(), result.Outcome)
+		return message, result, deadletter.ReasonConflict, conflictErr
+	}
+	if result.Outcome == storage.OutcomeRejected {
+		reason := deadletter.ReasonProcessingFailed
+		if result.Anomaly != nil {
+			reason = deadletter.ReasonClockSkew
+		}
+		processingErr := errors.NewMessageProcessingError(
+			message.GetChannel(),
+			message.GetMessageNumber(),
+			message.GetMessageType(),
+			"Message processing failed - "+result.Reason,
+		)
+		log.Printf("Failed to process message: %v", processingErr)
+		return message, result, reason, processingErr
+	}
+
+	log.Printf("Successfully processed message: Channel=%s, MsgNum=%d, Type=%s, Outcome=%s",
+		message.GetChannel(), message.GetMessageNumber(), message.GetMessageType(), result.Outcome)
+
+	return message, result, "", nil
+}
+
+// newEntry builds a dead-letter entry for a rejected message
+func newEntry(message *models.RocketMessage, reason string, err error, body []byte, receivedAt time.Time) deadletter.Entry {
+	entry := deadletter.Entry{
+		Reason:     reason,
+		Details:    err.Error(),
+		ReceivedAt: receivedAt,
+		Body:       string(body),
+	}
+	if message != nil {
+		entry.Channel = message.GetChannel()
+		entry.MessageNumber = message.GetMessageNumber()
+		entry.MessageType = message.GetMessageType()
+	}
+	return entry
+}
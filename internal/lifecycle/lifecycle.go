@@ -0,0 +1,115 @@
+package lifecycle
+
+import (
+	"fmt"
+
+	"lunar-backend-challenge/internal/models"
+)
+
+// Event is the effect a message has on the lifecycle of a rocket
+type Event string
+
+const (
+	EventLaunch  Event = "launch"  // The rocket is launched or relaunched
+	EventUpdate  Event = "update"  // The flight is updated, e.g. a speed or mission change
+	EventExplode Event = "explode" // The rocket exploded
+	EventLand    Event = "land"    // The rocket landed
+	EventLose    Event = "lose"    // Contact with the rocket was lost for good
+)
+
+// Statuses lists every rocket status in lifecycle order
+var Statuses = []string{
+	models.RocketStatusPendingLaunch,
+	models.RocketStatusActive,
+	models.RocketStatusRelaunched,
+	models.RocketStatusExploded,
+	models.RocketStatusLanded,
+	models.RocketStatusLost,
+}
+
+// Events lists every lifecycle event
+var Events = []Event{EventLaunch, EventUpdate, EventExplode, EventLand, EventLose}
+
+// transitions maps a status and an event to the resulting status.
+// Missing entries are illegal transitions.
+var transitions = map[string]map[Event]string{
+	models.RocketStatusPendingLaunch: {
+		EventLaunch: models.RocketStatusActive,
+	},
+	models.RocketStatusActive: {
+		EventLaunch:  models.RocketStatusRelaunched,
+		EventUpdate:  models.RocketStatusActive,
+		EventExplode: models.RocketStatusExploded,
+		EventLand:    models.RocketStatusLanded,
+		EventLose:    models.RocketStatusLost,
+	},
+	models.RocketStatusRelaunched: {
+		EventLaunch:  models.RocketStatusRelaunched,
+		EventUpdate:  models.RocketStatusRelaunched,
+		EventExplode: models.RocketStatusExploded,
+		EventLand:    models.RocketStatusLanded,
+		EventLose:    models.RocketStatusLost,
+	},
+	models.RocketStatusExploded: {
+		EventLaunch: models.RocketStatusRelaunched,
+	},
+	models.RocketStatusLanded: {
+		EventLaunch: models.RocketStatusRelaunched,
+	},
+	models.RocketStatusLost: {
+		EventLaunch: models.RocketStatusRelaunched,
+	},
+}
+
+// rejections explains why events other than those in the transition table are illegal in a status
+var rejections = map[string]string{
+	models.RocketStatusPendingLaunch: "rocket has not been launched yet",
+	models.RocketStatusExploded:      "rocket has exploded; only a relaunch is allowed",
+	models.RocketStatusLanded:        "rocket has landed; only a relaunch is allowed",
+	models.RocketStatusLost:          "rocket is lost; only a relaunch is allowed",
+}
+
+// TransitionError reports an illegal lifecycle transition
+type TransitionError struct {
+	From   string
+	Event  Event
+	Reason string
+}
+
+func (e TransitionError) Error() string {
+	return fmt.Sprintf("illegal transition %s from status %s: %s", e.Event, e.From, e.Reason)
+}
+
+// Transition returns the status a rocket moves to when event happens in status from
+func Transition(from string, event Event) (string, error) {
+	to, allowed := transitions[from][event]
+	if allowed {
+		return to, nil
+	}
+
+	reason, known := rejections[from]
+	switch {
+	case !IsValidStatus(from):
+		reason = "unknown status"
+	case !known:
+		reason = "event is not allowed in this status"
+	}
+	return "", TransitionError{From: from, Event: event, Reason: reason}
+}
+
+// CanTransition reports whether event is allowed in status from
+func CanTransition(from string, event Event) bool {
+	_, allowed := transitions[from][event]
+	return allowed
+}
+
+// IsValidStatus reports whether status is a known rocket status
+func IsValidStatus(status string) bool {
+	_, exists := transitions[status]
+	return exists
+}
+
+// InFlight reports whether a rocket with the given status is expected to keep sending messages
+func InFlight(status string) bool {
+	return status == models.RocketStatusActive || status == models.RocketStatusRelaunched
+}
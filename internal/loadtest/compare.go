@@ -0,0 +1,35 @@
+package loadtest
+
+// Delta compares a metric of a run with a baseline run
+type Delta struct {
+	Metric   string  `json:"metric"`
+	Baseline float64 `json:"baseline"`
+	Current  float64 `json:"current"`
+	Change   float64 `json:"change"` // Relative change, 0.1 for 10% more than the baseline
+	Better   bool    `json:"better"` // Whether the change is an improvement
+}
+
+// Compare compares the throughput, latencies and allocations of a run with a baseline. Metrics
+// missing from either run are left out.
+func Compare(baseline, current Report) []Delta {
+	var deltas []Delta
+	add := func(metric string, before, after float64, higherIsBetter bool) {
+		if before == 0 || after == 0 {
+			return
+		}
+		change := (after - before) / before
+		deltas = append(deltas, Delta{Metric: metric, Baseline: before, Current: after, Change: change, Better: (change > 0) == higherIsBetter})
+	}
+
+	add("ingest throughput/s", baseline.Ingest.Throughput, current.Ingest.Throughput, true)
+	add("ingest p50 ms", baseline.Ingest.P50, current.Ingest.P50, false)
+	add("ingest p99 ms", baseline.Ingest.P99, current.Ingest.P99, false)
+	add("list throughput/s", baseline.List.Throughput, current.List.Throughput, true)
+	add("list p50 ms", baseline.List.P50, current.List.P50, false)
+	add("list p99 ms", baseline.List.P99, current.List.P99, false)
+	if baseline.Allocations != nil && current.Allocations != nil {
+		add("allocs/request", baseline.Allocations.AllocsPerRequest, current.Allocations.AllocsPerRequest, false)
+		add("bytes/request", baseline.Allocations.BytesPerRequest, current.Allocations.BytesPerRequest, false)
+	}
+	return deltas
+}
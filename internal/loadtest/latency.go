@@ -0,0 +1,76 @@
+package loadtest
+
+import (
+	"math"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Latencies collects the latencies of requests, safe for concurrent use
+type Latencies struct {
+	mutex     sync.Mutex
+	durations []time.Duration
+	errors    int
+}
+
+// Observe records a request that took duration and failed with err, if not nil
+func (l *Latencies) Observe(duration time.Duration, err error) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.durations = append(l.durations, duration)
+	if err != nil {
+		l.errors++
+	}
+}
+
+// Summary sums up the latencies of a kind of request, in milliseconds
+type Summary struct {
+	Requests   int     `json:"requests"`
+	Errors     int     `json:"errors"`
+	Throughput float64 `json:"throughput"` // Requests per second over the run
+	Mean       float64 `json:"meanMs"`
+	P50        float64 `json:"p50Ms"`
+	P90        float64 `json:"p90Ms"`
+	P95        float64 `json:"p95Ms"`
+	P99        float64 `json:"p99Ms"`
+	Max        float64 `json:"maxMs"`
+}
+
+// Summary sums up the latencies observed during a run that took elapsed
+func (l *Latencies) Summary(elapsed time.Duration) Summary {
+	l.mutex.Lock()
+	sorted := slices.Clone(l.durations)
+	summary := Summary{Requests: len(sorted), Errors: l.errors}
+	l.mutex.Unlock()
+
+	if len(sorted) == 0 {
+		return summary
+	}
+	slices.Sort(sorted)
+
+	var total time.Duration
+	for _, duration := range sorted {
+		total += duration
+	}
+	if elapsed > 0 {
+		summary.Throughput = float64(len(sorted)) / elapsed.Seconds()
+	}
+	summary.Mean = milliseconds(total / time.Duration(len(sorted)))
+	summary.P50 = milliseconds(percentile(sorted, 0.50))
+	summary.P90 = milliseconds(percentile(sorted, 0.90))
+	summary.P95 = milliseconds(percentile(sorted, 0.95))
+	summary.P99 = milliseconds(percentile(sorted, 0.99))
+	summary.Max = milliseconds(sorted[len(sorted)-1])
+	return summary
+}
+
+// percentile returns the nearest-rank percentile p (0 < p <= 1) of sorted, which must not be empty
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(math.Ceil(p * float64(len(sorted))))
+	return sorted[max(rank, 1)-1]
+}
+
+func milliseconds(duration time.Duration) float64 {
+	return float64(duration) / float64(time.Millisecond)
+}
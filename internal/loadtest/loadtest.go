@@ -0,0 +1,193 @@
+package loadtest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"runtime"
+	"sync"
+	"time"
+
+	"lunar-backend-challenge/internal/client"
+	"lunar-backend-challenge/internal/importer"
+	"lunar-backend-challenge/internal/models"
+	"lunar-backend-challenge/internal/simulator"
+)
+
+// Config controls a load test
+type Config struct {
+	// Simulation generates the messages posted to /messages; a MessageDelay of 0 posts as fast as
+	// the Concurrency senders can
+	Simulation simulator.Config
+
+	// Preload is the number of rockets launched before the measured run, so the listing is
+	// measured against a fleet of that size
+	Preload int
+
+	// ListConcurrency clients request GET /rockets with ListQuery, back to back, while the
+	// messages are posted; 0 for none
+	ListConcurrency int
+	ListQuery       url.Values
+
+	// MeasureAllocations reports the allocations of this process during the run. They include the
+	// load generator itself, so they are only meaningful with the server running in this process.
+	MeasureAllocations bool
+}
+
+// Validate checks that a configuration can run
+func (c Config) Validate() error {
+	switch {
+	case c.Simulation.Messages < 1:
+		return fmt.Errorf("at least one message is required")
+	case c.Preload < 0:
+		return fmt.Errorf("the number of preloaded rockets cannot be negative")
+	case c.ListConcurrency < 0:
+		return fmt.Errorf("the list concurrency cannot be negative")
+	}
+	return c.Simulation.Validate()
+}
+
+// Report sums up a load test
+type Report struct {
+	Elapsed     float64          `json:"elapsedSeconds"` // Duration of the measured run
+	Simulation  simulator.Report `json:"simulation"`
+	Ingest      Summary          `json:"ingest"` // POST /messages
+	List        Summary          `json:"list"`   // GET /rockets
+	Allocations *Allocations     `json:"allocations,omitempty"`
+}
+
+// Allocations are the heap allocations of the process during a run
+type Allocations struct {
+	Allocs           uint64  `json:"allocs"`
+	Bytes            uint64  `json:"bytes"`
+	AllocsPerRequest float64 `json:"allocsPerRequest"`
+	BytesPerRequest  float64 `json:"bytesPerRequest"`
+}
+
+// timedSink measures the deliveries to a sink
+type timedSink struct {
+	sink      importer.Sink
+	latencies *Latencies
+}
+
+func (s timedSink) Apply(body []byte) (string, error) {
+	start := time.Now()
+	outcome, err := s.sink.Apply(body)
+	s.latencies.Observe(time.Since(start), err)
+	return outcome, err
+}
+
+// Run preloads the server at baseURL, then posts the simulated messages while listing the rockets,
+// and reports the latencies. It returns the delivery error if the server could not be reached.
+func Run(ctx context.Context, baseURL string, config Config) (Report, error) {
+	sink := importer.NewHTTPSink(baseURL)
+	if err := preload(ctx, sink, config.Preload, config.Simulation.Concurrency); err != nil {
+		return Report{}, err
+	}
+
+	var before runtime.MemStats
+	if config.MeasureAllocations {
+		runtime.GC()
+		runtime.ReadMemStats(&before)
+	}
+
+	// List the rockets until all messages were posted
+	listCtx, stopListing := context.WithCancel(ctx)
+	defer stopListing()
+	rockets := client.New(baseURL, "")
+	ingest, list := &Latencies{}, &Latencies{}
+	var listers sync.WaitGroup
+	for range config.ListConcurrency {
+		listers.Add(1)
+		go func() {
+			defer listers.Done()
+			for listCtx.Err() == nil {
+				start := time.Now()
+				_, err := rockets.ListRockets(listCtx, config.ListQuery)
+				if listCtx.Err() != nil {
+					return // Cancelled with the run, not a failed request
+				}
+				list.Observe(time.Since(start), err)
+			}
+		}()
+	}
+
+	start := time.Now()
+	simulation, err := simulator.New(config.Simulation).Run(ctx, timedSink{sink: sink, latencies: ingest})
+	stopListing()
+	listers.Wait()
+	elapsed := time.Since(start)
+
+	report := Report{
+		Elapsed:    elapsed.Seconds(),
+		Simulation: simulation,
+		Ingest:     ingest.Summary(elapsed),
+		List:       list.Summary(elapsed),
+	}
+	if config.MeasureAllocations {
+		var after runtime.MemStats
+		runtime.ReadMemStats(&after)
+		allocations := &Allocations{Allocs: after.Mallocs - before.Mallocs, Bytes: after.TotalAlloc - before.TotalAlloc}
+		if requests := report.Ingest.Requests + report.List.Requests; requests > 0 {
+			allocations.AllocsPerRequest = float64(allocations.Allocs) / float64(requests)
+			allocations.BytesPerRequest = float64(allocations.Bytes) / float64(requests)
+		}
+		report.Allocations = allocations
+	}
+	return report, err
+}
+
+// preload launches count rockets through sink, using concurrency senders
+func preload(ctx context.Context, sink importer.Sink, count, concurrency int) error {
+	ids := make(chan int)
+	failures := make(chan error, max(concurrency, 1))
+	var senders sync.WaitGroup
+	for range max(concurrency, 1) {
+		senders.Add(1)
+		go func() {
+			defer senders.Done()
+			for i := range ids {
+				msg := &models.RocketMessage{}
+				msg.Metadata.Channel = fmt.Sprintf("preload-rocket-%06d", i)
+				msg.Metadata.MessageNumber = 1
+				msg.Metadata.MessageTime = time.Now()
+				msg.Metadata.MessageType = models.MessageTypeRocketLaunched
+				msg.Message = models.MessageContent{Type: "Falcon-9", LaunchSpeed: 500 + i%5000, Mission: fmt.Sprintf("PRELOAD-%d", i%50)}
+				body, err := json.Marshal(msg)
+				if err == nil {
+					_, err = sink.Apply(body)
+				}
+				if err != nil {
+					select {
+					case failures <- fmt.Errorf("failed to preload rocket %d: %w", i, err):
+					default:
+					}
+					return
+				}
+			}
+		}()
+	}
+
+	var err error
+send:
+	for i := range count {
+		select {
+		case ids <- i:
+		case err = <-failures:
+			break send
+		case <-ctx.Done():
+			err = ctx.Err()
+			break send
+		}
+	}
+	close(ids)
+	senders.Wait()
+	if err == nil {
+		select {
+		case err = <-failures:
+		default:
+		}
+	}
+	return err
+}
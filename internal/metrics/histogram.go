@@ -0,0 +1,82 @@
+package metrics
+
+import (
+	"math"
+	"sort"
+)
+
+// DefaultLatencyBuckets are histogram upper bounds in seconds, from milliseconds to minutes
+var DefaultLatencyBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 300}
+
+// Histogram counts observations in buckets by upper bound. It is not safe for concurrent use;
+// the owner is expected to guard it along with the rest of its state.
+type Histogram struct {
+	bounds []float64
+	counts []uint64 // Observations per bucket, not cumulative; the last bucket is +Inf
+	sum    float64
+	count  uint64
+}
+
+// NewHistogram creates a histogram with the given bucket upper bounds
+func NewHistogram(bounds []float64) *Histogram {
+	sorted := append([]float64(nil), bounds...)
+	sort.Float64s(sorted)
+	return &Histogram{
+		bounds: sorted,
+		counts: make([]uint64, len(sorted)+1),
+	}
+}
+
+// Observe adds a value to the histogram
+func (h *Histogram) Observe(value float64) {
+	h.counts[sort.SearchFloat64s(h.bounds, value)]++
+	h.sum += value
+	h.count++
+}
+
+// Count returns the number of observations
+func (h *Histogram) Count() uint64 {
+	return h.count
+}
+
+// Sum returns the sum of the observations
+func (h *Histogram) Sum() float64 {
+	return h.sum
+}
+
+// Samples returns the cumulative buckets, sum and count of the histogram as samples of the given metric
+func (h *Histogram) Samples(name, help string, labels map[string]string) []Sample {
+	samples := make([]Sample, 0, len(h.counts)+2)
+
+	var cumulative uint64
+	for i, count := range h.counts {
+		cumulative += count
+		bound := math.Inf(1)
+		if i < len(h.bounds) {
+			bound = h.bounds[i]
+		}
+		samples = append(samples, Sample{
+			Name:   name,
+			Suffix: "_bucket",
+			Help:   help,
+			Type:   TypeHistogram,
+			Labels: withLabel(labels, "le", formatValue(bound)),
+			Value:  float64(cumulative),
+		})
+	}
+
+	return append(samples,
+		Sample{Name: name, Suffix: "_sum", Help: help, Type: TypeHistogram, Labels: labels, Value: h.sum},
+		Sample{Name: name, Suffix: "_count", Help: help, Type: TypeHistogram, Labels: labels, Value: float64(h.count)},
+	)
+}
+
+// withLabel returns a copy of labels with one more label
+func withLabel(labels map[string]string, name, value string) map[string]string {
+	copied := make(map[string]string, len(labels)+1)
+	for key, existing := range labels {
+		copied[key] = existing
+	}
+	copied[name] = value
+	return copied
+}
@@ -0,0 +1,128 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Metric types as used in the Prometheus text format
+const (
+	TypeCounter   = "counter"
+	TypeGauge     = "gauge"
+	TypeHistogram = "histogram"
+)
+
+// Sample is a single metric value with its labels
+type Sample struct {
+	Name   string
+	Suffix string // Appended to Name for the series of a histogram, e.g. "_bucket"
+	Help   string
+	Type   string
+	Labels map[string]string
+	Value  float64
+}
+
+// Collector produces samples whenever metrics are scraped
+type Collector interface {
+	Collect() []Sample
+}
+
+// CollectorFunc adapts a function to the Collector interface
+type CollectorFunc func() []Sample
+
+// Collect calls f
+func (f CollectorFunc) Collect() []Sample {
+	return f()
+}
+
+// Registry holds the collectors exposed on the metrics endpoint
+type Registry struct {
+	collectors []Collector
+	mutex      sync.RWMutex
+}
+
+// NewRegistry creates an empty metrics registry
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Register adds a collector to the registry
+func (r *Registry) Register(collector Collector) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.collectors = append(r.collectors, collector)
+}
+
+// Gather collects the samples of all registered collectors
+func (r *Registry) Gather() []Sample {
+	r.mutex.RLock()
+	collectors := make([]Collector, len(r.collectors))
+	copy(collectors, r.collectors)
+	r.mutex.RUnlock()
+
+	var samples []Sample
+	for _, collector := range collectors {
+		samples = append(samples, collector.Collect()...)
+	}
+	return samples
+}
+
+// WritePrometheus writes all samples in the Prometheus text exposition format
+func (r *Registry) WritePrometheus(w io.Writer) error {
+	samples := r.Gather()
+
+	// Group samples of the same metric so HELP and TYPE are written once
+	sort.SliceStable(samples, func(i, j int) bool {
+		return samples[i].Name < samples[j].Name
+	})
+
+	lastName := ""
+	for _, sample := range samples {
+		if sample.Name != lastName {
+			if sample.Help != "" {
+				if _, err := fmt.Fprintf(w, "# HELP %s %s\n", sample.Name, sample.Help); err != nil {
+					return err
+				}
+			}
+			if sample.Type != "" {
+				if _, err := fmt.Fprintf(w, "# TYPE %s %s\n", sample.Name, sample.Type); err != nil {
+					return err
+				}
+			}
+			lastName = sample.Name
+		}
+
+		if _, err := fmt.Fprintf(w, "%s%s%s %s\n", sample.Name, sample.Suffix, formatLabels(sample.Labels), formatValue(sample.Value)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// formatLabels renders labels as {a="1",b="2"} with sorted keys
+func formatLabels(labels map[string]string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, len(keys))
+	for i, key := range keys {
+		parts[i] = key + "=" + strconv.Quote(labels[key])
+	}
+	return "{" + strings.Join(parts, ",") + "}"
+}
+
+func formatValue(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"lunar-backend-challenge/internal/errors"
+	"lunar-backend-challenge/internal/recording"
+)
+
+// Recording writes the raw body of every POST /messages request, with the time it was received,
+// to the recorder before passing the request on, so that a test run can be replayed exactly
+func Recording(recorder *recording.Recorder) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodPost || r.URL.Path != "/messages" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			receivedAt := time.Now()
+			body, err := io.ReadAll(r.Body)
+			r.Body.Close()
+			if err != nil {
+				log.Printf("Failed to read request body: %v", err)
+				WriteErrorResponse(w, errors.NewAPIError(http.StatusBadRequest, "Invalid JSON format", err.Error()))
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			// A recording failure must not stop ingestion
+			if err := recorder.Record(receivedAt, body); err != nil {
+				log.Printf("Failed to record request: %v", err)
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
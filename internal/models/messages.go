@@ -1,6 +1,9 @@
 package models
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 // RocketMessage represents a message about a rocket's state change
 // @Description A message containing information about a rocket's state change
@@ -14,6 +17,11 @@ type RocketMessage struct {
 
 	// Message content with all possible fields
 	Message MessageContent `json:"message"`
+
+	// Server side timestamps: when the message was received, and when it was applied,
+	// which is later if it was buffered until earlier messages arrived
+	ReceivedAt time.Time `json:"-" swaggerignore:"true"`
+	AppliedAt  time.Time `json:"-" swaggerignore:"true"`
 }
 
 // MessageContent contains the payload of a rocket message
@@ -32,9 +40,12 @@ type MessageContent struct {
 
 	// RocketMissionChanged fields
 	NewMission string `json:"newMission,omitempty" example:"SHUTTLE_MIR"`
+
+	// Raw payload as received, for message types with fields not declared above
+	Raw json.RawMessage `json:"-" swaggerignore:"true"`
 }
 
-// Message type constants
+// Message type constants for the built-in message types (see internal/registry)
 const (
 	MessageTypeRocketLaunched       = "RocketLaunched"
 	MessageTypeRocketSpeedIncreased = "RocketSpeedIncreased"
@@ -43,10 +54,14 @@ const (
 	MessageTypeRocketMissionChanged = "RocketMissionChanged"
 )
 
-// Rocket status constants
+// Rocket status constants, see internal/lifecycle for the allowed transitions
 const (
-	RocketStatusActive   = "active"
-	RocketStatusExploded = "exploded"
+	RocketStatusPendingLaunch = "pending-launch"
+	RocketStatusActive        = "active"
+	RocketStatusExploded      = "exploded"
+	RocketStatusRelaunched    = "relaunched"
+	RocketStatusLanded        = "landed"
+	RocketStatusLost          = "lost"
 )
 
 func (m *RocketMessage) GetChannel() string {
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// MissionKey normalizes a mission name, so names differing only in case are the same mission.
+// This matches how sorting compares missions.
+func MissionKey(name string) string {
+	return strings.ToLower(name)
+}
+
+// MissionSummary, for listing purpose
+type MissionSummary struct {
+	Name               string `json:"name" example:"ARTEMIS"`         // Most recently used spelling of the mission name
+	CurrentRocketCount int    `json:"currentRocketCount" example:"2"` // Rockets assigned to the mission right now
+	RocketCount        int    `json:"rocketCount" example:"5"`        // Rockets ever assigned to the mission
+	AssignmentCount    int    `json:"assignmentCount" example:"6"`    // Assignment intervals, current and historical
+}
+
+// Mission lists the rockets that are and were assigned to a mission
+type Mission struct {
+	MissionSummary
+	CurrentRockets    []MissionRocket `json:"currentRockets"`
+	HistoricalRockets []MissionRocket `json:"historicalRockets"` // One entry per ended assignment, oldest first
+}
+
+// MissionRocket is a rocket's assignment to a mission
+type MissionRocket struct {
+	RocketID  string     `json:"rocketId" example:"193270a9-c9cf-404a-8f83-838e71d9ae67"`
+	Type      string     `json:"type" example:"Falcon-9"`
+	From      time.Time  `json:"from" example:"2024-03-14T19:39:05.86337+01:00"`
+	To        *time.Time `json:"to,omitempty" example:"2024-03-14T19:45:12.12345+01:00"`
+	EndReason string     `json:"endReason,omitempty" example:"mission-changed"` // mission-changed, exploded or relaunched
+}
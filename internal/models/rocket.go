@@ -4,17 +4,156 @@ import "time"
 
 // RocketState represents the state of a rocket
 type RocketState struct {
-	ID                         string    `json:"id" example:"193270a9-c9cf-404a-8f83-838e71d9ae67"`   // Rocket channel ID (unique identifier)
-	Type                       string    `json:"type" example:"Falcon-9"`                             // Rocket type (e.g. "Falcon-9")
-	Speed                      int       `json:"speed" example:"3500"`                                // Current speed
-	Mission                    string    `json:"mission" example:"ARTEMIS"`                           // Current mission
-	Exploded                   bool      `json:"exploded" example:"false"`                            // Status: "exploded"
-	Reason                     string    `json:"reason,omitempty" example:""`                         // Reason for explosion (only if exploded)
-	CreatedAt                  time.Time `json:"createdAt" example:"2024-03-14T19:39:05.86337+01:00"` // Time of first launch
-	UpdatedAt                  time.Time `json:"updatedAt" example:"2024-03-14T19:45:12.12345+01:00"` // Time of last update
-	LastProcessedMessageNumber int       `json:"-"`                                                   // Track message ordering (not exposed in JSON)
+	ID                         string              `json:"id" example:"193270a9-c9cf-404a-8f83-838e71d9ae67"`               // Rocket channel ID (unique identifier)
+	Type                       string              `json:"type" example:"Falcon-9"`                                         // Rocket type (e.g. "Falcon-9")
+	Speed                      int                 `json:"speed" example:"3500"`                                            // Current speed
+	Mission                    string              `json:"mission" example:"ARTEMIS"`                                       // Current mission
+	Exploded                   bool                `json:"exploded" example:"false"`                                        // Status: "exploded"
+	Status                     string              `json:"status" example:"active"`                                         // Lifecycle status, see internal/lifecycle
+	Reason                     string              `json:"reason,omitempty" example:""`                                     // Reason for explosion (only if exploded)
+	CreatedAt                  time.Time           `json:"createdAt" example:"2024-03-14T19:39:05.86337+01:00"`             // Time of first launch
+	UpdatedAt                  time.Time           `json:"updatedAt" example:"2024-03-14T19:45:12.12345+01:00"`             // Time of last update
+	LastProcessedMessageNumber int                 `json:"-"`                                                               // Track message ordering (not exposed in JSON)
+	Partial                    bool                `json:"partial,omitempty" example:"false"`                               // Bootstrapped without its early history; fields may be "unknown"
+	BootstrappedFrom           int                 `json:"bootstrappedFrom,omitempty" example:"0"`                          // Lowest message number the partial rocket was bootstrapped from
+	LaunchCount                int                 `json:"launchCount" example:"1"`                                         // Number of launches, including relaunches
+	Launches                   []LaunchGeneration  `json:"-"`                                                               // Per-launch history, see GET /rockets/{id}/launches
+	Assignments                []MissionAssignment `json:"-"`                                                               // Mission history, see GET /missions
+	LastSeen                   time.Time           `json:"lastSeen" example:"2024-03-14T19:45:13.01234+01:00"`              // When the last message of the rocket was received
+	LastMessageTime            time.Time           `json:"lastMessageTime" example:"2024-03-14T19:45:12.12345+01:00"`       // Latest messageTime received from the rocket
+	Silent                     bool                `json:"silent" example:"false"`                                          // No message received for the silence timeout while in flight
+	SilentSince                *time.Time          `json:"silentSince,omitempty" example:"2024-03-14T19:50:13.01234+01:00"` // When the rocket was marked silent
 }
 
+// Launch end reasons
+const (
+	LaunchEndExploded   = "exploded"
+	LaunchEndSuperseded = "superseded"
+)
+
+// LaunchGeneration describes one flight of a rocket, from its launch until it exploded or was relaunched
+type LaunchGeneration struct {
+	Generation      int        `json:"generation" example:"1"`
+	LaunchedAt      time.Time  `json:"launchedAt" example:"2024-03-14T19:39:05.86337+01:00"`
+	InitialSpeed    int        `json:"initialSpeed" example:"500"`
+	Mission         string     `json:"mission" example:"ARTEMIS"`
+	PeakSpeed       int        `json:"peakSpeed" example:"3500"`
+	EndReason       string     `json:"endReason,omitempty" example:"exploded"`                      // "exploded" or "superseded", empty while in flight
+	ExplosionReason string     `json:"explosionReason,omitempty" example:"PRESSURE_VESSEL_FAILURE"` // Only if exploded
+	EndedAt         *time.Time `json:"endedAt,omitempty" example:"2024-03-14T19:45:12.12345+01:00"`
+}
+
+// Mission assignment end reasons
+const (
+	AssignmentEndMissionChanged = "mission-changed"
+	AssignmentEndExploded       = "exploded"
+	AssignmentEndRelaunched     = "relaunched"
+)
+
+// MissionAssignment is an interval during which a rocket was on a mission, from its launch or
+// a mission change until the next change, explosion or relaunch
+type MissionAssignment struct {
+	Mission   string     `json:"mission" example:"ARTEMIS"`
+	From      time.Time  `json:"from" example:"2024-03-14T19:39:05.86337+01:00"`
+	To        *time.Time `json:"to,omitempty" example:"2024-03-14T19:45:12.12345+01:00"`
+	EndReason string     `json:"endReason,omitempty" example:"mission-changed"` // Empty while still assigned
+}
+
+// Clone returns a deep copy of the rocket state
+func (r *RocketState) Clone() *RocketState {
+	clone := *r
+	if r.Launches != nil {
+		clone.Launches = make([]LaunchGeneration, len(r.Launches))
+		copy(clone.Launches, r.Launches)
+	}
+	if r.Assignments != nil {
+		clone.Assignments = make([]MissionAssignment, len(r.Assignments))
+		copy(clone.Assignments, r.Assignments)
+	}
+	if r.SilentSince != nil {
+		silentSince := *r.SilentSince
+		clone.SilentSince = &silentSince
+	}
+	return &clone
+}
+
+// CurrentLaunch returns the launch generation still in flight, or nil if there is none
+func (r *RocketState) CurrentLaunch() *LaunchGeneration {
+	if len(r.Launches) == 0 {
+		return nil
+	}
+	current := &r.Launches[len(r.Launches)-1]
+	if current.EndReason != "" {
+		return nil
+	}
+	return current
+}
+
+// StartLaunch begins a new launch generation, superseding the one in flight
+func (r *RocketState) StartLaunch(at time.Time, speed int, mission string) {
+	r.EndLaunch(LaunchEndSuperseded, "", at)
+
+	r.LaunchCount++
+	r.Launches = append(r.Launches, LaunchGeneration{
+		Generation:   r.LaunchCount,
+		LaunchedAt:   at,
+		InitialSpeed: speed,
+		Mission:      mission,
+		PeakSpeed:    speed,
+	})
+}
+
+// RecordSpeed tracks the peak speed of the launch generation in flight
+func (r *RocketState) RecordSpeed(speed int) {
+	if current := r.CurrentLaunch(); current != nil && speed > current.PeakSpeed {
+		current.PeakSpeed = speed
+	}
+}
+
+// EndLaunch ends the launch generation in flight, if any
+func (r *RocketState) EndLaunch(endReason, explosionReason string, at time.Time) {
+	current := r.CurrentLaunch()
+	if current == nil {
+		return
+	}
+	endedAt := at
+	current.EndReason = endReason
+	current.ExplosionReason = explosionReason
+	current.EndedAt = &endedAt
+}
+
+// CurrentAssignment returns the mission assignment still open, or nil if there is none
+func (r *RocketState) CurrentAssignment() *MissionAssignment {
+	if len(r.Assignments) == 0 {
+		return nil
+	}
+	current := &r.Assignments[len(r.Assignments)-1]
+	if current.EndReason != "" {
+		return nil
+	}
+	return current
+}
+
+// AssignMission starts a mission assignment, ending the open one with the given reason
+func (r *RocketState) AssignMission(mission string, endReason string, at time.Time) {
+	r.EndAssignment(endReason, at)
+	r.Assignments = append(r.Assignments, MissionAssignment{Mission: mission, From: at})
+}
+
+// EndAssignment ends the open mission assignment, if any
+func (r *RocketState) EndAssignment(endReason string, at time.Time) {
+	current := r.CurrentAssignment()
+	if current == nil {
+		return
+	}
+	endedAt := at
+	current.EndReason = endReason
+	current.To = &endedAt
+}
+
+// UnknownValue is used for fields of a partially bootstrapped rocket that no received message has set yet
+const UnknownValue = "unknown"
+
 // RocketSummary, for listing purpose
 type RocketSummary struct {
 	ID        string    `json:"id" example:"193270a9-c9cf-404a-8f83-838e71d9ae67"`
@@ -22,5 +161,9 @@ type RocketSummary struct {
 	Speed     int       `json:"speed" example:"3500"`
 	Mission   string    `json:"mission" example:"ARTEMIS"`
 	Exploded  bool      `json:"exploded" example:"false"`
+	Status    string    `json:"status" example:"active"`
 	UpdatedAt time.Time `json:"updatedAt" example:"2024-03-14T19:45:12.12345+01:00"`
+	Partial   bool      `json:"partial,omitempty" example:"false"`
+	Silent    bool      `json:"silent" example:"false"`
+	LastSeen  time.Time `json:"lastSeen" example:"2024-03-14T19:45:13.01234+01:00"`
 }
@@ -0,0 +1,19 @@
+package models
+
+// FleetStats summarizes the fleet, or the part of it matching a filter
+type FleetStats struct {
+	TotalRockets       int                   `json:"totalRockets" example:"12"`
+	Active             int                   `json:"active" example:"9"` // Rockets in flight, i.e. active or relaunched
+	Exploded           int                   `json:"exploded" example:"3"`
+	Silent             int                   `json:"silent" example:"1"` // Rockets in flight that stopped sending messages
+	ByType             map[string]GroupStats `json:"byType"`
+	ByMission          map[string]GroupStats `json:"byMission"`
+	ExplosionsByReason map[string]int        `json:"explosionsByReason"` // Currently exploded rockets per explosion reason
+}
+
+// GroupStats summarizes the rockets sharing a type or mission
+type GroupStats struct {
+	Count        int     `json:"count" example:"4"`
+	AverageSpeed float64 `json:"averageSpeed" example:"2750.5"`
+	MaxSpeed     int     `json:"maxSpeed" example:"5000"`
+}
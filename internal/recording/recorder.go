@@ -0,0 +1,218 @@
+package recording
+
+import (
+	"bufio"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// Defaults of the recording file rotation
+const (
+	DefaultMaxSize  = 64 << 20
+	DefaultMaxFiles = 10
+)
+
+// rotatedTimeFormat names rotated files so that they sort in recording order
+const rotatedTimeFormat = "20060102T150405.000000000"
+
+// Record is a request body as received, one JSON object per line in a recording
+type Record struct {
+	Sequence   int64     `json:"sequence"`           // Position of the request in the recording, continued across restarts
+	ReceivedAt time.Time `json:"receivedAt"`         // When the server received the request
+	Body       string    `json:"body"`               // Raw body, base64 encoded if Encoding is base64
+	Encoding   string    `json:"encoding,omitempty"` // base64 for bodies that are not valid UTF-8
+}
+
+// NewRecord creates a record keeping body byte for byte
+func NewRecord(sequence int64, receivedAt time.Time, body []byte) Record {
+	if !utf8.Valid(body) {
+		return Record{Sequence: sequence, ReceivedAt: receivedAt, Body: base64.StdEncoding.EncodeToString(body), Encoding: "base64"}
+	}
+	return Record{Sequence: sequence, ReceivedAt: receivedAt, Body: string(body)}
+}
+
+// RawBody returns the body as it was received
+func (r Record) RawBody() ([]byte, error) {
+	if r.Encoding == "base64" {
+		return base64.StdEncoding.DecodeString(r.Body)
+	}
+	return []byte(r.Body), nil
+}
+
+// Recorder appends records to a file, rotating it when it grows beyond MaxSize. Rotated files get
+// the rotation time inserted before their extension, e.g. messages-20240314T193905.123456789.ndjson,
+// and only the newest MaxFiles of them are kept.
+type Recorder struct {
+	path     string
+	maxSize  int64
+	maxFiles int
+
+	mutex    sync.Mutex
+	file     *os.File
+	size     int64
+	sequence int64
+}
+
+// NewRecorder opens the recording at path, appending to it if it exists and continuing its
+// sequence. maxSize and maxFiles default to DefaultMaxSize and DefaultMaxFiles if <= 0.
+func NewRecorder(path string, maxSize int64, maxFiles int) (*Recorder, error) {
+	if maxSize <= 0 {
+		maxSize = DefaultMaxSize
+	}
+	if maxFiles <= 0 {
+		maxFiles = DefaultMaxFiles
+	}
+	sequence, err := lastSequence(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read recording: %w", err)
+	}
+	r := &Recorder{path: path, maxSize: maxSize, maxFiles: maxFiles, sequence: sequence}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Record appends a request body received at receivedAt
+func (r *Recorder) Record(receivedAt time.Time, body []byte) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.sequence++
+	line, err := json.Marshal(NewRecord(r.sequence, receivedAt, body))
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	if r.size > 0 && r.size+int64(len(line)) > r.maxSize {
+		if err := r.rotate(receivedAt); err != nil {
+			return fmt.Errorf("failed to rotate recording: %w", err)
+		}
+	}
+	n, err := r.file.Write(line)
+	r.size += int64(n)
+	return err
+}
+
+// Close closes the recording file
+func (r *Recorder) Close() error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.file.Close()
+}
+
+// open opens the recording file for appending
+func (r *Recorder) open() error {
+	file, err := os.OpenFile(r.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	r.file, r.size = file, info.Size()
+	return nil
+}
+
+// lastSequence returns the highest sequence in the recording at path or, if it has no records yet,
+// in its newest rotated file. Lines that cannot be decoded, e.g. one cut short by a crash, are skipped.
+func lastSequence(path string) (int64, error) {
+	paths := []string{path}
+	rotated, err := RotatedFiles(path)
+	if err != nil {
+		return 0, err
+	}
+	if len(rotated) > 0 {
+		paths = append(paths, rotated[len(rotated)-1])
+	}
+
+	for _, name := range paths {
+		file, err := os.Open(name)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return 0, err
+		}
+		var sequence int64
+		scanner := bufio.NewScanner(file)
+		scanner.Buffer(make([]byte, 64*1024), maxLineSize)
+		for scanner.Scan() {
+			var record struct {
+				Sequence int64 `json:"sequence"`
+			}
+			if json.Unmarshal(scanner.Bytes(), &record) == nil {
+				sequence = max(sequence, record.Sequence)
+			}
+		}
+		file.Close()
+		if err := scanner.Err(); err != nil {
+			return 0, fmt.Errorf("%s: %w", name, err)
+		}
+		if sequence > 0 {
+			return sequence, nil
+		}
+	}
+	return 0, nil
+}
+
+// rotate moves the full recording aside, starts a new one and removes the oldest rotated files
+func (r *Recorder) rotate(now time.Time) error {
+	if err := r.file.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(r.path, RotatedPath(r.path, now)); err != nil {
+		return err
+	}
+	if err := r.open(); err != nil {
+		return err
+	}
+
+	rotated, err := RotatedFiles(r.path)
+	if err != nil {
+		return err
+	}
+	for len(rotated) > r.maxFiles {
+		if err := os.Remove(rotated[0]); err != nil {
+			return err
+		}
+		rotated = rotated[1:]
+	}
+	return nil
+}
+
+// RotatedPath returns the name a recording at path is rotated to at time t
+func RotatedPath(path string, t time.Time) string {
+	extension := filepath.Ext(path)
+	return strings.TrimSuffix(path, extension) + "-" + t.UTC().Format(rotatedTimeFormat) + extension
+}
+
+// RotatedFiles returns the rotated files of the recording at path, oldest first
+func RotatedFiles(path string) ([]string, error) {
+	extension := filepath.Ext(path)
+	matches, err := filepath.Glob(strings.TrimSuffix(path, extension) + "-*" + extension)
+	if err != nil {
+		return nil, err
+	}
+	var rotated []string
+	for _, match := range matches {
+		stamp := strings.TrimSuffix(strings.TrimPrefix(match, strings.TrimSuffix(path, extension)+"-"), extension)
+		if _, err := time.Parse(rotatedTimeFormat, stamp); err == nil {
+			rotated = append(rotated, match)
+		}
+	}
+	sort.Strings(rotated)
+	return rotated, nil
+}
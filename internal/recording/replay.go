@@ -0,0 +1,147 @@
+package recording
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"lunar-backend-challenge/internal/importer"
+)
+
+// maxLineSize is the longest record accepted in a recording
+const maxLineSize = 4 << 20
+
+// ReadRecords decodes the records of a recording in order, skipping empty lines
+func ReadRecords(input io.Reader) ([]Record, error) {
+	var records []Record
+	scanner := bufio.NewScanner(input)
+	scanner.Buffer(make([]byte, 64*1024), maxLineSize)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var record Record
+		if err := json.Unmarshal(line, &record); err != nil {
+			return nil, fmt.Errorf("line %d: invalid record: %w", lineNumber, err)
+		}
+		records = append(records, record)
+	}
+	return records, scanner.Err()
+}
+
+// ReadFiles reads the records of recordings, in the order of paths
+func ReadFiles(paths []string) ([]Record, error) {
+	var records []Record
+	for _, path := range paths {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		fileRecords, err := ReadRecords(file)
+		file.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		records = append(records, fileRecords...)
+	}
+	return records, nil
+}
+
+// Replayer re-sends recorded requests to a sink
+type Replayer struct {
+	Sink importer.Sink
+
+	// Speed scales the pacing of the recording: 1 replays at the original pace, 2 twice as fast,
+	// and 0 as fast as possible
+	Speed float64
+
+	// PreserveOrder delivers each record only after the previous one was applied, so the sink sees
+	// the original arrival order exactly even if a delivery takes longer than the gap to the next.
+	// Otherwise deliveries are sent on schedule by up to Concurrency concurrent senders.
+	PreserveOrder bool
+	Concurrency   int // Concurrent senders without PreserveOrder, 1 if <= 0
+}
+
+// Replay sends records to the sink, paced by their receive times, and adds the results to report
+// with the record sequence as line number. It stops early if ctx is done or a record cannot be
+// delivered, returning the delivery error.
+func (r *Replayer) Replay(ctx context.Context, source string, records []Record, report *importer.Report) error {
+	concurrency := r.Concurrency
+	if r.PreserveOrder || concurrency <= 0 {
+		concurrency = 1
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var mutex sync.Mutex
+	var failure error
+	apply := func(record Record) {
+		body, err := record.RawBody()
+		outcome := ""
+		if err == nil {
+			outcome, err = r.Sink.Apply(body)
+		}
+
+		mutex.Lock()
+		defer mutex.Unlock()
+		if outcome == "" {
+			if failure == nil {
+				failure = fmt.Errorf("%s record %d: %w", source, record.Sequence, err)
+			}
+			cancel()
+			return
+		}
+		report.Lines++
+		report.Outcomes[outcome]++
+		if err != nil {
+			report.Failures = append(report.Failures, importer.Failure{Source: source, Line: int(record.Sequence), Outcome: outcome, Error: err.Error()})
+		}
+	}
+
+	// Senders take records in order as they become due
+	due := make(chan Record)
+	var senders sync.WaitGroup
+	for range concurrency {
+		senders.Add(1)
+		go func() {
+			defer senders.Done()
+			for record := range due {
+				apply(record)
+			}
+		}()
+	}
+
+	start := time.Now()
+schedule:
+	for _, record := range records {
+		if r.Speed > 0 {
+			offset := time.Duration(float64(record.ReceivedAt.Sub(records[0].ReceivedAt)) / r.Speed)
+			if wait := time.Until(start.Add(offset)); wait > 0 {
+				select {
+				case <-time.After(wait):
+				case <-ctx.Done():
+					break schedule
+				}
+			}
+		}
+		select {
+		case due <- record:
+		case <-ctx.Done():
+			break schedule
+		}
+	}
+	close(due)
+	senders.Wait()
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	return failure
+}
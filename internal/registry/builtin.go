@@ -0,0 +1,153 @@
+package registry
+
+import (
+	"math"
+
+	"lunar-backend-challenge/internal/errors"
+	"lunar-backend-challenge/internal/lifecycle"
+	"lunar-backend-challenge/internal/models"
+)
+
+// builtinTypes returns the message types sent by the rockets test program
+func builtinTypes() []MessageType {
+	return []MessageType{
+		{
+			Name:      models.MessageTypeRocketLaunched,
+			Validate:  validateLaunched,
+			Apply:     applyLaunched,
+			Canonical: declaredFields,
+			Event:     lifecycle.EventLaunch,
+		},
+		{
+			Name:      models.MessageTypeRocketSpeedIncreased,
+			Validate:  validateSpeedChange,
+			Apply:     applySpeedIncreased,
+			Canonical: declaredFields,
+			Event:     lifecycle.EventUpdate,
+		},
+		{
+			Name:      models.MessageTypeRocketSpeedDecreased,
+			Validate:  validateSpeedChange,
+			Apply:     applySpeedDecreased,
+			Canonical: declaredFields,
+			Event:     lifecycle.EventUpdate,
+		},
+		{
+			Name:      models.MessageTypeRocketExploded,
+			Validate:  validateExploded,
+			Apply:     applyExploded,
+			Canonical: declaredFields,
+			Event:     lifecycle.EventExplode,
+		},
+		{
+			Name:      models.MessageTypeRocketMissionChanged,
+			Validate:  validateMissionChanged,
+			Apply:     applyMissionChanged,
+			Canonical: declaredFields,
+			Event:     lifecycle.EventUpdate,
+		},
+	}
+}
+
+func validateLaunched(content *models.MessageContent) error {
+	if content.Type == "" {
+		return errors.NewValidationError("type", "rocket type is required for launch message")
+	}
+	if content.Mission == "" {
+		return errors.NewValidationError("mission", "mission is required for launch message")
+	}
+	if content.LaunchSpeed < 0 {
+		return errors.NewValidationError("launchSpeed", "launch speed cannot be negative")
+	}
+	return nil
+}
+
+func validateSpeedChange(content *models.MessageContent) error {
+	if content.By <= 0 {
+		return errors.NewValidationError("by", "speed change amount must be positive")
+	}
+	return nil
+}
+
+func validateExploded(content *models.MessageContent) error {
+	if content.Reason == "" {
+		return errors.NewValidationError("reason", "explosion reason is required")
+	}
+	return nil
+}
+
+func validateMissionChanged(content *models.MessageContent) error {
+	if content.NewMission == "" {
+		return errors.NewValidationError("newMission", "new mission is required")
+	}
+	return nil
+}
+
+func applyLaunched(rocket *models.RocketState, msg *models.RocketMessage) bool {
+	// Validate required fields
+	if msg.Message.Type == "" || msg.Message.Mission == "" {
+		return false
+	}
+
+	// Reset rocket state for new launch (can relaunch exploded rockets)
+	rocket.Type = msg.Message.Type
+	rocket.Mission = msg.Message.Mission
+	rocket.Speed = msg.Message.LaunchSpeed
+	rocket.Exploded = false
+	rocket.Reason = ""
+
+	// Set created time only for first launch
+	if rocket.CreatedAt.IsZero() {
+		rocket.CreatedAt = msg.GetMessageTime()
+	}
+
+	// Every launch starts a new generation and mission assignment, superseding the previous ones if they did not end
+	rocket.StartLaunch(msg.GetMessageTime(), rocket.Speed, rocket.Mission)
+	rocket.AssignMission(rocket.Mission, models.AssignmentEndRelaunched, msg.GetMessageTime())
+	return true
+}
+
+func applySpeedIncreased(rocket *models.RocketState, msg *models.RocketMessage) bool {
+	if msg.Message.By <= 0 {
+		return false
+	}
+	// Saturate rather than wrap around to a negative speed
+	if rocket.Speed > math.MaxInt-msg.Message.By {
+		rocket.Speed = math.MaxInt
+	} else {
+		rocket.Speed += msg.Message.By
+	}
+	rocket.RecordSpeed(rocket.Speed)
+	return true
+}
+
+func applySpeedDecreased(rocket *models.RocketState, msg *models.RocketMessage) bool {
+	if msg.Message.By <= 0 {
+		return false
+	}
+	rocket.Speed -= msg.Message.By
+	if rocket.Speed < 0 {
+		rocket.Speed = 0
+	}
+	return true
+}
+
+func applyExploded(rocket *models.RocketState, msg *models.RocketMessage) bool {
+	if msg.Message.Reason == "" {
+		return false
+	}
+	rocket.Exploded = true
+	rocket.Reason = msg.Message.Reason
+	rocket.EndLaunch(models.LaunchEndExploded, msg.Message.Reason, msg.GetMessageTime())
+	rocket.EndAssignment(models.AssignmentEndExploded, msg.GetMessageTime())
+	return true
+}
+
+func applyMissionChanged(rocket *models.RocketState, msg *models.RocketMessage) bool {
+	if msg.Message.NewMission == "" {
+		return false
+	}
+	rocket.Mission = msg.Message.NewMission
+	rocket.AssignMission(rocket.Mission, models.AssignmentEndMissionChanged, msg.GetMessageTime())
+	return true
+}
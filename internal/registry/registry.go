@@ -0,0 +1,261 @@
+package registry
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+
+	"lunar-backend-challenge/internal/lifecycle"
+	"lunar-backend-challenge/internal/models"
+)
+
+// MessageType describes how a single rocket message type is decoded, validated and applied.
+// Registering a MessageType is all that is needed to support a new kind of rocket event.
+type MessageType struct {
+	// Name is the value of metadata.messageType for this type (e.g. "RocketLaunched")
+	Name string
+
+	// Decode parses the raw "message" payload. Defaults to DecodeContent when nil.
+	Decode func(raw json.RawMessage) (models.MessageContent, error)
+
+	// Validate checks the type specific payload fields. Optional.
+	Validate func(content *models.MessageContent) error
+
+	// Apply reduces the message onto the rocket state. It returns false if the
+	// message cannot be applied, in which case the state must be left untouched.
+	Apply func(rocket *models.RocketState, msg *models.RocketMessage) bool
+
+	// Canonical returns the payload values that identify a message of this type, so retransmissions
+	// are recognized whatever their encoding. Defaults to canonicalPayload when nil.
+	Canonical func(content *models.MessageContent) any
+
+	// Event is the lifecycle event the message causes. Defaults to lifecycle.EventUpdate when empty.
+	// Messages whose event is illegal in the rocket's current status are not applied.
+	Event lifecycle.Event
+}
+
+// Registry holds the set of known message types
+type Registry struct {
+	types map[string]MessageType
+	mutex sync.RWMutex
+}
+
+// Default is the registry used by validation and storage. It contains the built-in message types.
+var Default = NewBuiltinRegistry()
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{
+		types: make(map[string]MessageType),
+	}
+}
+
+// NewBuiltinRegistry creates a registry with the built-in message types, e.g. to add message
+// types without changing Default
+func NewBuiltinRegistry() *Registry {
+	r := NewRegistry()
+	for _, messageType := range builtinTypes() {
+		r.MustRegister(messageType)
+	}
+	return r
+}
+
+// Register adds a message type to the registry
+func (r *Registry) Register(messageType MessageType) error {
+	if messageType.Name == "" {
+		return fmt.Errorf("message type name is required")
+	}
+	if messageType.Apply == nil {
+		return fmt.Errorf("message type %s has no Apply function", messageType.Name)
+	}
+	if messageType.Decode == nil {
+		messageType.Decode = DecodeContent
+	}
+	if messageType.Canonical == nil {
+		messageType.Canonical = canonicalPayload
+	}
+	if messageType.Event == "" {
+		messageType.Event = lifecycle.EventUpdate
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, exists := r.types[messageType.Name]; exists {
+		return fmt.Errorf("message type %s is already registered", messageType.Name)
+	}
+	r.types[messageType.Name] = messageType
+	return nil
+}
+
+// MustRegister is like Register but panics on error, intended for use during init
+func (r *Registry) MustRegister(messageType MessageType) {
+	if err := r.Register(messageType); err != nil {
+		panic(err)
+	}
+}
+
+// Lookup returns the message type registered under name
+func (r *Registry) Lookup(name string) (MessageType, bool) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	messageType, exists := r.types[name]
+	return messageType, exists
+}
+
+// Names returns the names of all registered message types in sorted order
+func (r *Registry) Names() []string {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	names := make([]string, 0, len(r.types))
+	for name := range r.types {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// DecodeMessage decodes a raw JSON rocket message, using the registered decoder for its payload.
+// Payloads of unknown message types are left empty so validation can report the type.
+func (r *Registry) DecodeMessage(data []byte) (*models.RocketMessage, error) {
+	var envelope struct {
+		Metadata json.RawMessage `json:"metadata"`
+		Message  json.RawMessage `json:"message"`
+	}
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return nil, err
+	}
+
+	msg := &models.RocketMessage{}
+	if len(envelope.Metadata) > 0 {
+		if err := json.Unmarshal(envelope.Metadata, &msg.Metadata); err != nil {
+			return nil, err
+		}
+	}
+
+	messageType, exists := r.Lookup(msg.GetMessageType())
+	if !exists || len(envelope.Message) == 0 {
+		return msg, nil
+	}
+
+	content, err := messageType.Decode(envelope.Message)
+	if err != nil {
+		return nil, err
+	}
+	msg.Message = content
+	return msg, nil
+}
+
+// ContentHash identifies the content of a message: its type and the canonical payload of the type.
+// Retransmissions of a message have the same hash, however their payload was encoded, e.g. with
+// another field order, with explicit zero values or over gRPC instead of HTTP.
+func (r *Registry) ContentHash(msg *models.RocketMessage) string {
+	canonical := canonicalPayload
+	if messageType, exists := r.Lookup(msg.GetMessageType()); exists {
+		canonical = messageType.Canonical
+	}
+	payload, _ := json.Marshal(canonical(&msg.Message))
+
+	hash := sha256.New()
+	hash.Write([]byte(msg.GetMessageType()))
+	hash.Write([]byte{0})
+	hash.Write(payload)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// canonicalPayload is the default canonical payload: the raw payload, or the declared fields without
+// it, with zero values dropped. Object keys are sorted when the result is encoded.
+func canonicalPayload(content *models.MessageContent) any {
+	payload := []byte(content.Raw)
+	if len(payload) == 0 {
+		return declaredFields(content)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(payload))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return string(payload)
+	}
+	return withoutZeroValues(value)
+}
+
+// declaredFields is the canonical payload of the built-in types: the fields MessageContent declares,
+// so fields they do not read are ignored
+func declaredFields(content *models.MessageContent) any {
+	return *content // Raw is not encoded
+}
+
+// withoutZeroValues drops the zero valued members of decoded JSON objects, as absent fields decode to zero values
+func withoutZeroValues(value any) any {
+	switch value := value.(type) {
+	case map[string]any:
+		for key, member := range value {
+			member = withoutZeroValues(member)
+			if isZeroValue(member) {
+				delete(value, key)
+			} else {
+				value[key] = member
+			}
+		}
+		return value
+	case []any:
+		for i, element := range value {
+			value[i] = withoutZeroValues(element)
+		}
+		return value
+	default:
+		return value
+	}
+}
+
+// isZeroValue reports whether a decoded JSON value is null, false, 0, "" or an empty object or array
+func isZeroValue(value any) bool {
+	switch value := value.(type) {
+	case nil:
+		return true
+	case bool:
+		return !value
+	case json.Number:
+		number, err := value.Float64()
+		return err == nil && number == 0
+	case string:
+		return value == ""
+	case map[string]any:
+		return len(value) == 0
+	case []any:
+		return len(value) == 0
+	}
+	return false
+}
+
+// DecodeContent is the default payload decoder. It keeps the raw payload so
+// reducers of new message types can read fields MessageContent does not declare.
+func DecodeContent(raw json.RawMessage) (models.MessageContent, error) {
+	var content models.MessageContent
+	if err := json.Unmarshal(raw, &content); err != nil {
+		return content, err
+	}
+	content.Raw = append(json.RawMessage(nil), raw...)
+	return content, nil
+}
+
+// Register adds a message type to the default registry
+func Register(messageType MessageType) error {
+	return Default.Register(messageType)
+}
+
+// Lookup returns a message type from the default registry
+func Lookup(name string) (MessageType, bool) {
+	return Default.Lookup(name)
+}
+
+// DecodeMessage decodes a raw JSON rocket message using the default registry
+func DecodeMessage(data []byte) (*models.RocketMessage, error) {
+	return Default.DecodeMessage(data)
+}
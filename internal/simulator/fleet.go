@@ -0,0 +1,118 @@
+package simulator
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"sort"
+	"time"
+
+	"lunar-backend-challenge/internal/models"
+)
+
+// Values the launch test program picks rocket and message content from
+var (
+	rocketTypes      = []string{"Falcon-9", "Falcon Heavy", "Starship", "Saturn-V", "Atlas-V", "Ariane-5", "Soyuz"}
+	missions         = []string{"ARTEMIS", "APOLLO", "GEMINI", "MERCURY", "SHUTTLE_MIR", "DRAGON", "ORION", "VOYAGER"}
+	explosionReasons = []string{"PRESSURE_VESSEL_FAILURE", "ENGINE_FAILURE", "FUEL_LEAK", "GUIDANCE_SYSTEM_ERROR", "STRUCTURAL_FAILURE"}
+)
+
+// rocket is a simulated rocket with the state its messages lead to
+type rocket struct {
+	state      models.RocketSummary
+	nextNumber int
+}
+
+// fleet generates the messages of a fixed number of rockets in flight. A rocket that explodes is
+// replaced by a newly launched one.
+type fleet struct {
+	random        *rand.Rand
+	explosionRate float64
+	flying        []*rocket
+	rockets       map[string]*rocket // Every rocket ever launched, by ID
+}
+
+// newFleet creates a fleet of size rockets waiting for their launch
+func newFleet(random *rand.Rand, size int, explosionRate float64) *fleet {
+	f := &fleet{random: random, explosionRate: explosionRate, rockets: make(map[string]*rocket)}
+	for range size {
+		f.flying = append(f.flying, f.newRocket())
+	}
+	return f
+}
+
+// newRocket creates a rocket whose next message is its launch
+func (f *fleet) newRocket() *rocket {
+	r := &rocket{nextNumber: 1}
+	r.state.ID = fmt.Sprintf("%08x-%04x-4%03x-%04x-%012x",
+		f.random.Uint32(), f.random.Uint32()&0xffff, f.random.Uint32()&0xfff,
+		f.random.Uint32()&0x3fff|0x8000, f.random.Uint64()&0xffffffffffff)
+	f.rockets[r.state.ID] = r
+	return r
+}
+
+// next returns the next message of a random rocket, sent at now
+func (f *fleet) next(now time.Time) *models.RocketMessage {
+	slot := f.random.IntN(len(f.flying))
+	r := f.flying[slot]
+
+	msg := &models.RocketMessage{}
+	msg.Metadata.Channel = r.state.ID
+	msg.Metadata.MessageNumber = r.nextNumber
+	msg.Metadata.MessageTime = now
+	r.nextNumber++
+	r.state.UpdatedAt = now
+
+	roll := f.random.Float64()
+	switch {
+	case msg.Metadata.MessageNumber == 1:
+		msg.Metadata.MessageType = models.MessageTypeRocketLaunched
+		msg.Message.Type = pick(f.random, rocketTypes)
+		msg.Message.LaunchSpeed = 100 * (5 + f.random.IntN(26))
+		msg.Message.Mission = pick(f.random, missions)
+		r.state.Type = msg.Message.Type
+		r.state.Speed = msg.Message.LaunchSpeed
+		r.state.Mission = msg.Message.Mission
+		r.state.Status = models.RocketStatusActive
+
+	case roll < f.explosionRate:
+		msg.Metadata.MessageType = models.MessageTypeRocketExploded
+		msg.Message.Reason = pick(f.random, explosionReasons)
+		r.state.Exploded = true
+		r.state.Status = models.RocketStatusExploded
+		f.flying[slot] = f.newRocket()
+
+	case roll < f.explosionRate+0.1:
+		msg.Metadata.MessageType = models.MessageTypeRocketMissionChanged
+		msg.Message.NewMission = pick(f.random, missions)
+		r.state.Mission = msg.Message.NewMission
+
+	case roll < f.explosionRate+0.4 && r.state.Speed > 0:
+		// Rockets never slow down below zero
+		msg.Metadata.MessageType = models.MessageTypeRocketSpeedDecreased
+		msg.Message.By = 1 + f.random.IntN(min(r.state.Speed, 2500))
+		r.state.Speed -= msg.Message.By
+
+	default:
+		msg.Metadata.MessageType = models.MessageTypeRocketSpeedIncreased
+		msg.Message.By = 100 * (1 + f.random.IntN(30))
+		r.state.Speed += msg.Message.By
+	}
+	return msg
+}
+
+// summaries returns the state of every launched rocket, sorted by ID
+func (f *fleet) summaries() []models.RocketSummary {
+	summaries := make([]models.RocketSummary, 0, len(f.rockets))
+	for _, r := range f.rockets {
+		if r.nextNumber > 1 {
+			summaries = append(summaries, r.state)
+		}
+	}
+	sort.Slice(summaries, func(i, j int) bool { return summaries[i].ID < summaries[j].ID })
+	return summaries
+}
+
+// pick returns a random element of values
+func pick(random *rand.Rand, values []string) string {
+	return values[random.IntN(len(values))]
+}
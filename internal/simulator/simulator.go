@@ -0,0 +1,261 @@
+package simulator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"lunar-backend-challenge/internal/importer"
+	"lunar-backend-challenge/internal/models"
+)
+
+// Defaults of the launch test program
+const (
+	DefaultMessageDelay  = 500 * time.Millisecond
+	DefaultConcurrency   = 1
+	DefaultRockets       = 5
+	DefaultExplosionRate = 0.02
+	DefaultReorderWindow = 5
+)
+
+// Config controls the fleet and how its messages are delivered
+type Config struct {
+	Rockets       int           // Rockets in flight at any time
+	Messages      int           // Messages to generate, not counting duplicates; 0 to run until cancelled
+	MessageDelay  time.Duration // Delay between the messages of each sender
+	Concurrency   int           // Concurrent senders
+	ExplosionRate float64       // Chance that a message of a rocket in flight is its explosion
+
+	// ReorderRate is the chance that a message is held back behind up to ReorderWindow later
+	// messages, and DuplicateRate the chance that a message is delivered a second time, up to
+	// ReorderWindow messages later
+	ReorderRate   float64
+	DuplicateRate float64
+	ReorderWindow int
+
+	// Seed makes the generated messages and their delivery order repeatable; 0 picks a random seed
+	Seed uint64
+
+	// Now returns the message time, overridable in tests
+	Now func() time.Time
+}
+
+// DefaultConfig returns the configuration matching the launch test program's defaults
+func DefaultConfig() Config {
+	return Config{
+		Rockets:       DefaultRockets,
+		MessageDelay:  DefaultMessageDelay,
+		Concurrency:   DefaultConcurrency,
+		ExplosionRate: DefaultExplosionRate,
+		ReorderWindow: DefaultReorderWindow,
+		Now:           time.Now,
+	}
+}
+
+// Validate checks that a configuration can run
+func (c Config) Validate() error {
+	switch {
+	case c.Rockets < 1:
+		return fmt.Errorf("at least one rocket is required")
+	case c.Messages < 0:
+		return fmt.Errorf("the number of messages cannot be negative")
+	case c.MessageDelay < 0:
+		return fmt.Errorf("the message delay cannot be negative")
+	case c.Concurrency < 1:
+		return fmt.Errorf("the concurrency level must be at least 1")
+	case c.ReorderWindow < 1 && (c.ReorderRate > 0 || c.DuplicateRate > 0):
+		return fmt.Errorf("the reorder window must be at least 1")
+	}
+	for name, rate := range map[string]float64{"explosion": c.ExplosionRate, "reorder": c.ReorderRate, "duplicate": c.DuplicateRate} {
+		if rate < 0 || rate > 1 {
+			return fmt.Errorf("the %s rate must be between 0 and 1", name)
+		}
+	}
+	return nil
+}
+
+// Report sums up a simulation
+type Report struct {
+	Generated  int            `json:"generated"`  // Messages generated
+	Duplicated int            `json:"duplicated"` // Messages delivered twice
+	Reordered  int            `json:"reordered"`  // Messages held back behind later ones
+	Sent       int            `json:"sent"`       // Deliveries, including duplicates
+	Outcomes   map[string]int `json:"outcomes"`   // Deliveries per outcome reported by the sink
+	Errors     int            `json:"errors"`     // Deliveries the sink rejected
+}
+
+// Simulator generates the messages of a rocket fleet and delivers them to a sink, like the launch
+// test program posting to /messages
+type Simulator struct {
+	config Config
+	random *rand.Rand
+	fleet  *fleet
+
+	mutex   sync.Mutex
+	report  Report
+	failure error // Why delivery stopped, if the sink could not be reached
+}
+
+// New creates a simulator; the configuration must be valid
+func New(config Config) *Simulator {
+	if config.Seed == 0 {
+		config.Seed = rand.Uint64()
+	}
+	if config.Now == nil {
+		config.Now = time.Now
+	}
+	random := rand.New(rand.NewPCG(config.Seed, config.Seed))
+	return &Simulator{
+		config: config,
+		random: random,
+		fleet:  newFleet(random, config.Rockets, config.ExplosionRate),
+		report: Report{Outcomes: make(map[string]int)},
+	}
+}
+
+// Run generates messages and delivers them to sink until all Messages were delivered or ctx is
+// done. It returns the report and, if the sink could not be reached, the delivery error.
+func (s *Simulator) Run(ctx context.Context, sink importer.Sink) (Report, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	deliveries := make(chan []byte)
+	generated := make(chan struct{})
+	go func() {
+		defer close(generated)
+		defer close(deliveries)
+		s.generate(ctx, deliveries)
+	}()
+
+	var senders sync.WaitGroup
+	for range s.config.Concurrency {
+		senders.Add(1)
+		go func() {
+			defer senders.Done()
+			s.send(ctx, cancel, sink, deliveries)
+		}()
+	}
+	senders.Wait()
+	cancel()
+	<-generated
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	report := s.report
+	report.Outcomes = make(map[string]int, len(s.report.Outcomes))
+	for outcome, count := range s.report.Outcomes {
+		report.Outcomes[outcome] = count
+	}
+	return report, s.failure
+}
+
+// Rockets returns the state every launched rocket should be in once all generated messages
+// were applied, sorted by ID. Only call it after Run returned.
+func (s *Simulator) Rockets() []models.RocketSummary {
+	return s.fleet.summaries()
+}
+
+// heldMessage is a delivery postponed behind later messages
+type heldMessage struct {
+	body      []byte
+	remaining int // Messages to deliver before this one
+}
+
+// generate produces the message stream in delivery order, with reordering and duplicates. Messages
+// are generated as senders become ready, so their messageTime is close to their delivery.
+func (s *Simulator) generate(ctx context.Context, deliveries chan<- []byte) {
+	var held []heldMessage
+	deliver := func(body []byte) bool {
+		select {
+		case deliveries <- body:
+			s.mutex.Lock()
+			s.report.Sent++
+			s.mutex.Unlock()
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	for generated := 0; s.config.Messages == 0 || generated < s.config.Messages; generated++ {
+		message, err := json.Marshal(s.fleet.next(s.config.Now()))
+		if err != nil {
+			panic(err) // Generated messages always encode
+		}
+		body := message
+
+		s.mutex.Lock()
+		s.report.Generated++
+		if s.random.Float64() < s.config.ReorderRate {
+			held = append(held, heldMessage{body: body, remaining: 1 + s.random.IntN(s.config.ReorderWindow)})
+			s.report.Reordered++
+			body = nil
+		}
+		if s.random.Float64() < s.config.DuplicateRate {
+			held = append(held, heldMessage{body: message, remaining: s.random.IntN(s.config.ReorderWindow + 1)})
+			s.report.Duplicated++
+		}
+		s.mutex.Unlock()
+
+		if body != nil && !deliver(body) {
+			return
+		}
+
+		// Deliver the held messages whose turn has come, in the order they were held
+		waiting := held[:0]
+		var due [][]byte
+		for _, h := range held {
+			if h.remaining--; h.remaining < 0 {
+				due = append(due, h.body)
+			} else {
+				waiting = append(waiting, h)
+			}
+		}
+		held = waiting
+		for _, body := range due {
+			if !deliver(body) {
+				return
+			}
+		}
+	}
+
+	for _, h := range held {
+		if !deliver(h.body) {
+			return
+		}
+	}
+}
+
+// send delivers messages to the sink, waiting MessageDelay after each. A delivery failure stops
+// the simulation.
+func (s *Simulator) send(ctx context.Context, cancel context.CancelFunc, sink importer.Sink, deliveries <-chan []byte) {
+	for body := range deliveries {
+		outcome, err := sink.Apply(body)
+
+		s.mutex.Lock()
+		if outcome == "" {
+			if s.failure == nil {
+				s.failure = fmt.Errorf("failed to deliver message: %w", err)
+			}
+			s.mutex.Unlock()
+			cancel()
+			return
+		}
+		s.report.Outcomes[outcome]++
+		if err != nil {
+			s.report.Errors++
+		}
+		s.mutex.Unlock()
+
+		if s.config.MessageDelay > 0 {
+			select {
+			case <-time.After(s.config.MessageDelay):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
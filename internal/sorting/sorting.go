@@ -14,6 +14,7 @@ var ValidSortOptions = map[string]bool{
 	"speed":     true,
 	"mission":   true,
 	"exploded":  true,
+	"status":    true,
 	"updatedAt": true,
 }
 
@@ -74,6 +75,13 @@ func SortRockets(rockets []models.RocketSummary, sortBy, sortOrder string) []mod
 				// If both have same exploded status, sort by ID as secondary
 				result = strings.ToLower(sortedRockets[i].ID) < strings.ToLower(sortedRockets[j].ID)
 			}
+		case "status":
+			// Sort by status name, with ID as secondary
+			if sortedRockets[i].Status != sortedRockets[j].Status {
+				result = sortedRockets[i].Status < sortedRockets[j].Status
+			} else {
+				result = strings.ToLower(sortedRockets[i].ID) < strings.ToLower(sortedRockets[j].ID)
+			}
 		case "updatedAt":
 			result = sortedRockets[i].UpdatedAt.Before(sortedRockets[j].UpdatedAt)
 		default:
@@ -0,0 +1,144 @@
+package storage
+
+import (
+	"fmt"
+	"time"
+
+	"lunar-backend-challenge/internal/metrics"
+	"lunar-backend-challenge/internal/models"
+)
+
+// Anomaly kinds
+const (
+	// AnomalyFutureTime means the messageTime is ahead of the server clock by more than MaxClockSkew
+	AnomalyFutureTime = "future_time"
+	// AnomalyTimeReversed means the messageTime is earlier than that of the previous message number
+	AnomalyTimeReversed = "time_reversed"
+	// AnomalyTimeGap means the messageTimes of consecutive message numbers are more than MaxMessageGap apart
+	AnomalyTimeGap = "time_gap"
+	// AnomalyReplayRejected means a message could not be applied when reconciling a partially
+	// bootstrapped rocket, so the rocket was kept partial
+	AnomalyReplayRejected = "replay_rejected"
+)
+
+// DefaultAnomalyHistory is the number of anomalies kept per rocket for debugging
+const DefaultAnomalyHistory = 100
+
+// Anomaly is a message whose messageTime disagrees with the server clock or its message number,
+// or whose replay failed
+type Anomaly struct {
+	Kind          string    `json:"kind" example:"time_reversed"` // future_time, time_reversed, time_gap or replay_rejected
+	MessageNumber int       `json:"messageNumber" example:"4"`
+	MessageTime   time.Time `json:"messageTime" example:"2024-03-14T19:39:01.86337+01:00"`
+	Detail        string    `json:"detail" example:"messageTime is 4s before that of message 3"`
+	Rejected      bool      `json:"rejected" example:"false"` // Whether the message was rejected, see ClockSkewReject and replay_rejected
+	DetectedAt    time.Time `json:"detectedAt" example:"2024-03-14T19:39:05.86337+01:00"`
+}
+
+// checkMessageTime records the timing anomalies of a message the first time its number is seen:
+// a messageTime too far ahead of the server clock, and a messageTime out of order with or too far
+// from those of the neighbouring message numbers. It returns the anomaly the message is rejected
+// for under ClockSkewReject, if any.
+func (r *RocketRepository) checkMessageTime(msg *models.RocketMessage) *Anomaly {
+	rocketID, msgNumber, msgTime := msg.GetChannel(), msg.GetMessageNumber(), msg.GetMessageTime()
+	if _, seen := r.messageTimes[rocketID][msgNumber]; seen {
+		return nil
+	}
+
+	if skew := msgTime.Sub(r.config.Now()); r.config.MaxClockSkew >= 0 && skew > r.config.MaxClockSkew {
+		rejected := r.config.ClockSkewPolicy == ClockSkewReject
+		anomaly := r.recordAnomaly(msg, AnomalyFutureTime, fmt.Sprintf("messageTime is %s ahead of server time", skew), rejected)
+		if rejected {
+			return anomaly
+		}
+	}
+
+	if r.messageTimes[rocketID] == nil {
+		r.messageTimes[rocketID] = make(map[int]time.Time)
+	}
+	times := r.messageTimes[rocketID]
+	if previous, known := times[msgNumber-1]; known {
+		r.checkInterval(msg, msgNumber-1, previous, msgNumber, msgTime)
+	}
+	if next, known := times[msgNumber+1]; known {
+		r.checkInterval(msg, msgNumber, msgTime, msgNumber+1, next)
+	}
+	times[msgNumber] = msgTime
+	return nil
+}
+
+// pruneMessageTimes forgets the messageTimes the interval checks no longer need since the sequence
+// advanced from previous: those more than one below the last processed message, which no message
+// still to arrive neighbours. Retransmissions of older messages are recognized as duplicates before
+// their time is checked. Backfilled history of a partial rocket is kept until it is replayed.
+func (r *RocketRepository) pruneMessageTimes(rocket *models.RocketState, previous int) {
+	times := r.messageTimes[rocket.ID]
+	for msgNum := max(previous-1, 1); msgNum < rocket.LastProcessedMessageNumber-1; msgNum++ {
+		if _, backfilled := r.backfill[rocket.ID][msgNum]; !backfilled {
+			delete(times, msgNum)
+		}
+	}
+}
+
+// checkInterval records an anomaly for msg if the messageTimes of two consecutive message numbers
+// are out of order or too far apart
+func (r *RocketRepository) checkInterval(msg *models.RocketMessage, earlierNumber int, earlier time.Time, laterNumber int, later time.Time) {
+	interval := later.Sub(earlier)
+	switch {
+	case interval < 0:
+		r.recordAnomaly(msg, AnomalyTimeReversed, fmt.Sprintf("messageTime of message %d is %s before that of message %d", laterNumber, -interval, earlierNumber), false)
+	case r.config.MaxMessageGap >= 0 && interval > r.config.MaxMessageGap:
+		r.recordAnomaly(msg, AnomalyTimeGap, fmt.Sprintf("messageTimes of messages %d and %d are %s apart", earlierNumber, laterNumber, interval), false)
+	}
+}
+
+// recordAnomaly keeps an anomaly of a message in the rocket's recent anomalies and counts it
+func (r *RocketRepository) recordAnomaly(msg *models.RocketMessage, kind, detail string, rejected bool) *Anomaly {
+	anomaly := Anomaly{
+		Kind:          kind,
+		MessageNumber: msg.GetMessageNumber(),
+		MessageTime:   msg.GetMessageTime(),
+		Detail:        detail,
+		Rejected:      rejected,
+		DetectedAt:    r.config.Now(),
+	}
+
+	rocketID := msg.GetChannel()
+	r.anomalies[rocketID] = append(r.anomalies[rocketID], anomaly)
+	if len(r.anomalies[rocketID]) > DefaultAnomalyHistory {
+		r.anomalies[rocketID] = r.anomalies[rocketID][len(r.anomalies[rocketID])-DefaultAnomalyHistory:]
+	}
+	r.anomalyCounts[kind]++
+	if rejected && kind == AnomalyFutureTime {
+		r.skewRejected++
+	}
+	return &anomaly
+}
+
+// GetAnomalies returns the most recent timing anomalies of a rocket, oldest first
+func (r *RocketRepository) GetAnomalies(rocketID string) []Anomaly {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	return append([]Anomaly(nil), r.anomalies[rocketID]...)
+}
+
+// Collect returns timing anomaly and lag metrics, see metrics.Collector
+func (r *RocketRepository) Collect() []metrics.Sample {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	samples := []metrics.Sample{
+		{Name: "rocket_messages_skew_rejected_total", Help: "Messages rejected because their messageTime was too far ahead of server time", Type: metrics.TypeCounter, Value: float64(r.skewRejected)},
+	}
+	for _, kind := range []string{AnomalyFutureTime, AnomalyTimeReversed, AnomalyTimeGap, AnomalyReplayRejected} {
+		samples = append(samples, metrics.Sample{
+			Name:   "rocket_message_anomalies_total",
+			Help:   "Messages whose messageTime disagreed with the server clock or their message number, or whose replay failed",
+			Type:   metrics.TypeCounter,
+			Labels: map[string]string{"kind": kind},
+			Value:  float64(r.anomalyCounts[kind]),
+		})
+	}
+	return append(samples, r.lagSamples()...)
+}
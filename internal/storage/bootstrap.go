@@ -0,0 +1,199 @@
+package storage
+
+import (
+	"context"
+	"maps"
+	"sort"
+	"time"
+
+	"lunar-backend-challenge/internal/models"
+)
+
+// BootstrapPending materializes rockets whose launch was never received, if the bootstrap policy
+// allows it and their messages have been buffered for at least the bootstrap timeout.
+// It returns the IDs of the rockets that were materialized.
+func (r *RocketRepository) BootstrapPending(now time.Time) []string {
+	if r.config.BootstrapPolicy != BootstrapAfterTimeout {
+		return nil
+	}
+
+	r.mutex.Lock()
+	var bootstrapped []string
+	for rocketID, bufferedAt := range r.firstBufferedAt {
+		if now.Sub(bufferedAt) < r.config.BootstrapTimeout {
+			continue
+		}
+		if r.materialize(rocketID) {
+			bootstrapped = append(bootstrapped, rocketID)
+		}
+	}
+
+	r.queueChanges()
+	r.mutex.Unlock()
+	r.deliverChanges()
+
+	sort.Strings(bootstrapped)
+	return bootstrapped
+}
+
+// materialize creates a partial rocket from the lowest buffered message number and
+// applies the buffered messages that are in sequence from there
+func (r *RocketRepository) materialize(rocketID string) bool {
+	delete(r.firstBufferedAt, rocketID)
+
+	pendingForRocket := r.pendingMessages[rocketID]
+	if _, exists := r.rockets[rocketID]; exists || len(pendingForRocket) == 0 {
+		return false
+	}
+
+	lowest := 0
+	var lastMessageTime time.Time
+	for msgNum, msg := range pendingForRocket {
+		if lowest == 0 || msgNum < lowest {
+			lowest = msgNum
+		}
+		if msg.GetMessageTime().After(lastMessageTime) {
+			lastMessageTime = msg.GetMessageTime()
+		}
+	}
+
+	rocket := &models.RocketState{
+		ID:                         rocketID,
+		Type:                       models.UnknownValue,
+		Mission:                    models.UnknownValue,
+		Status:                     models.RocketStatusActive,
+		CreatedAt:                  pendingForRocket[lowest].GetMessageTime(),
+		LastProcessedMessageNumber: lowest - 1,
+		Partial:                    true,
+		BootstrappedFrom:           lowest,
+		LastSeen:                   r.config.Now(),
+		LastMessageTime:            lastMessageTime,
+	}
+	r.rockets[rocketID] = rocket
+
+	r.processPendingMessages(rocketID)
+	r.refreshStats(rocketID)
+	return true
+}
+
+// bufferBackfill keeps a message from before the bootstrap point of a partial rocket and
+// reconciles the rocket once its complete history is available
+func (r *RocketRepository) bufferBackfill(rocket *models.RocketState, msg *models.RocketMessage) ProcessResult {
+	if r.backfill[rocket.ID] == nil {
+		r.backfill[rocket.ID] = make(map[int]*models.RocketMessage)
+	}
+	if result, repeated := r.checkBuffered(r.backfill[rocket.ID], msg); repeated {
+		return result
+	}
+	r.backfill[rocket.ID][msg.GetMessageNumber()] = msg
+
+	result, rejected := r.reconcile(rocket)
+	switch {
+	case result.Outcome == OutcomeApplied:
+		return ProcessResult{Outcome: OutcomeApplied, Reason: "rocket reconciled with its complete history"}
+	case result.Outcome == OutcomeRejected && rejected == msg.GetMessageNumber():
+		return result
+	}
+	return ProcessResult{Outcome: OutcomeBuffered, Reason: "waiting for the remaining history of a partially bootstrapped rocket"}
+}
+
+// reconcile rebuilds a partial rocket by replaying its full history once every message before the
+// bootstrap point has arrived. It returns OutcomeBuffered while history is missing and OutcomeApplied
+// once the rocket is reconciled. If a message of the history cannot be applied, the partial rocket is
+// kept as it was, so its sequence never moves backwards, and OutcomeRejected is returned with the
+// number of that message. A rejected backfill message is dropped so it can be sent again.
+func (r *RocketRepository) reconcile(partial *models.RocketState) (ProcessResult, int) {
+	rocketID := partial.ID
+	backfill := r.backfill[rocketID]
+
+	history := make([]*models.RocketMessage, 0, partial.BootstrappedFrom-1+len(r.partialHistory[rocketID]))
+	for msgNum := 1; msgNum < partial.BootstrappedFrom; msgNum++ {
+		msg, exists := backfill[msgNum]
+		if !exists {
+			return ProcessResult{Outcome: OutcomeBuffered}, 0
+		}
+		history = append(history, msg)
+	}
+	history = append(history, r.partialHistory[rocketID]...)
+
+	// Replay everything onto a fresh state, as if the messages had arrived in order, keeping what
+	// the replay replaces in case it has to be rolled back
+	saved := struct {
+		processed    map[int]string
+		speedSeries  *speedSeries
+		events       []MessageEvent
+		lag          *rocketLag
+		messageTimes map[int]time.Time
+	}{r.processedMessages[rocketID], r.speedSeries[rocketID], r.events[rocketID], r.lag[rocketID], maps.Clone(r.messageTimes[rocketID])}
+
+	rocket := &models.RocketState{ID: rocketID, Status: models.RocketStatusPendingLaunch}
+	r.rockets[rocketID] = rocket
+	r.processedMessages[rocketID] = make(map[int]string)
+	delete(r.speedSeries, rocketID)
+	delete(r.events, rocketID)
+	delete(r.lag, rocketID)
+
+	r.replaying = true
+	var rejected *models.RocketMessage
+	var result ProcessResult
+	for _, msg := range history {
+		if result = r.applyInSequence(rocket, msg); result.Outcome == OutcomeRejected {
+			rejected = msg
+			break
+		}
+	}
+	r.replaying = false
+
+	if rejected != nil {
+		r.rockets[rocketID] = partial
+		r.processedMessages[rocketID] = saved.processed
+		r.speedSeries[rocketID] = saved.speedSeries
+		r.events[rocketID] = saved.events
+		r.lag[rocketID] = saved.lag
+		r.messageTimes[rocketID] = saved.messageTimes
+		if rejected.GetMessageNumber() < partial.BootstrappedFrom {
+			delete(backfill, rejected.GetMessageNumber())
+			delete(saved.messageTimes, rejected.GetMessageNumber())
+		}
+
+		reason := "message could not be applied when reconciling: " + result.Reason
+		r.recordAnomaly(rejected, AnomalyReplayRejected, reason, true)
+		return ProcessResult{Outcome: OutcomeRejected, Reason: reason}, rejected.GetMessageNumber()
+	}
+
+	delete(r.backfill, rocketID)
+	delete(r.partialHistory, rocketID)
+	r.pruneMessageTimes(rocket, 0)
+	if rocket.CreatedAt.IsZero() {
+		rocket.CreatedAt = partial.CreatedAt
+	}
+	rocket.LastSeen = partial.LastSeen
+	rocket.LastMessageTime = partial.LastMessageTime
+	rocket.Silent = partial.Silent
+	rocket.SilentSince = partial.SilentSince
+
+	// Report the reconciled state as a single change from the partial one
+	if len(history) > 0 {
+		r.recordChange(ChangeApplied, partial, rocket, history[len(history)-1])
+	}
+
+	r.processPendingMessages(rocketID)
+	return ProcessResult{Outcome: OutcomeApplied}, 0
+}
+
+// RunMaintenance periodically performs time based housekeeping such as bootstrapping
+// rockets whose launch was never received and detecting silent rockets. It blocks until ctx is cancelled.
+func (r *RocketRepository) RunMaintenance(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.BootstrapPending(r.config.Now())
+			r.DetectSilence(r.config.Now())
+		}
+	}
+}
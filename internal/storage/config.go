@@ -0,0 +1,195 @@
+package storage
+
+import (
+	"fmt"
+	"time"
+
+	"lunar-backend-challenge/internal/registry"
+)
+
+// BootstrapPolicy decides what happens to a rocket whose launch message was never received
+type BootstrapPolicy string
+
+const (
+	// BootstrapWait keeps buffering the rocket's messages until its launch arrives
+	BootstrapWait BootstrapPolicy = "wait"
+	// BootstrapAfterTimeout materializes a partial rocket from the lowest buffered
+	// message once messages have been buffered for longer than BootstrapTimeout
+	BootstrapAfterTimeout BootstrapPolicy = "timeout"
+)
+
+// DefaultBootstrapTimeout is how long messages are buffered before a partial rocket is materialized
+const DefaultBootstrapTimeout = 30 * time.Second
+
+// SilenceClock decides which time a rocket's silence is measured against
+type SilenceClock string
+
+const (
+	// SilenceByReceiveTime measures silence since the last message was received
+	SilenceByReceiveTime SilenceClock = "received"
+	// SilenceByMessageTime measures silence since the latest messageTime sent by the rocket
+	SilenceByMessageTime SilenceClock = "message"
+)
+
+// DefaultSilenceTimeout is how long a rocket in flight may go without messages before it is silent
+const DefaultSilenceTimeout = 5 * time.Minute
+
+// ConflictPolicy decides what happens to a message whose number was already accepted with a different payload
+type ConflictPolicy string
+
+const (
+	// ConflictFirstWins keeps the first message and accepts the conflicting one without applying it
+	ConflictFirstWins ConflictPolicy = "first-wins"
+	// ConflictReject keeps the first message and rejects the conflicting one
+	ConflictReject ConflictPolicy = "reject"
+	// ConflictAlert behaves like ConflictFirstWins and also reports the conflict to the observers
+	ConflictAlert ConflictPolicy = "alert"
+)
+
+// ClockSkewPolicy decides what happens to a message whose messageTime is too far ahead of the server clock
+type ClockSkewPolicy string
+
+const (
+	// ClockSkewFlag records the anomaly and processes the message as usual
+	ClockSkewFlag ClockSkewPolicy = "flag"
+	// ClockSkewReject records the anomaly and rejects the message
+	ClockSkewReject ClockSkewPolicy = "reject"
+)
+
+// Default timing anomaly thresholds, see Config
+const (
+	DefaultMaxClockSkew  = time.Minute
+	DefaultMaxMessageGap = time.Hour
+)
+
+// Config holds the tunable behavior of a RocketRepository
+type Config struct {
+	BootstrapPolicy  BootstrapPolicy
+	BootstrapTimeout time.Duration
+
+	// SpeedRetention is the number of speed points kept per rocket
+	SpeedRetention int
+	// EventRetention is the number of message events kept per rocket
+	EventRetention int
+
+	// SilenceTimeout is how long a rocket in flight may go without messages, by SilenceClock,
+	// before DetectSilence marks it silent. A negative timeout disables silence detection.
+	SilenceTimeout time.Duration
+	SilenceClock   SilenceClock
+
+	// ConflictPolicy applies to messages repeating an accepted message number with a different payload
+	ConflictPolicy ConflictPolicy
+
+	// MaxClockSkew is how far a messageTime may be ahead of the server clock before it is an anomaly,
+	// and MaxMessageGap how far apart the messageTimes of consecutive messages may be.
+	// Negative values disable the check. ClockSkewPolicy applies to messages beyond MaxClockSkew.
+	MaxClockSkew    time.Duration
+	MaxMessageGap   time.Duration
+	ClockSkewPolicy ClockSkewPolicy
+
+	// Registry holds the message types the repository can apply, registry.Default if nil
+	Registry *registry.Registry
+
+	// Now returns the current time, overridable in tests
+	Now func() time.Time
+}
+
+// DefaultConfig returns the configuration used by NewRocketRepository
+func DefaultConfig() Config {
+	return Config{
+		BootstrapPolicy:  BootstrapWait,
+		BootstrapTimeout: DefaultBootstrapTimeout,
+		SpeedRetention:   DefaultSpeedRetention,
+		EventRetention:   DefaultEventRetention,
+		SilenceTimeout:   DefaultSilenceTimeout,
+		SilenceClock:     SilenceByReceiveTime,
+		ConflictPolicy:   ConflictFirstWins,
+		MaxClockSkew:     DefaultMaxClockSkew,
+		MaxMessageGap:    DefaultMaxMessageGap,
+		ClockSkewPolicy:  ClockSkewFlag,
+		Registry:         registry.Default,
+		Now:              time.Now,
+	}
+}
+
+// ParseBootstrapPolicy converts a policy name into a BootstrapPolicy
+func ParseBootstrapPolicy(name string) (BootstrapPolicy, error) {
+	switch BootstrapPolicy(name) {
+	case BootstrapWait, BootstrapAfterTimeout:
+		return BootstrapPolicy(name), nil
+	default:
+		return "", fmt.Errorf("invalid bootstrap policy %q (valid policies are: wait, timeout)", name)
+	}
+}
+
+// ParseSilenceClock converts a clock name into a SilenceClock
+func ParseSilenceClock(name string) (SilenceClock, error) {
+	switch SilenceClock(name) {
+	case SilenceByReceiveTime, SilenceByMessageTime:
+		return SilenceClock(name), nil
+	default:
+		return "", fmt.Errorf("invalid silence clock %q (valid clocks are: received, message)", name)
+	}
+}
+
+// ParseConflictPolicy converts a policy name into a ConflictPolicy
+func ParseConflictPolicy(name string) (ConflictPolicy, error) {
+	switch ConflictPolicy(name) {
+	case ConflictFirstWins, ConflictReject, ConflictAlert:
+		return ConflictPolicy(name), nil
+	default:
+		return "", fmt.Errorf("invalid conflict policy %q (valid policies are: first-wins, reject, alert)", name)
+	}
+}
+
+// ParseClockSkewPolicy converts a policy name into a ClockSkewPolicy
+func ParseClockSkewPolicy(name string) (ClockSkewPolicy, error) {
+	switch ClockSkewPolicy(name) {
+	case ClockSkewFlag, ClockSkewReject:
+		return ClockSkewPolicy(name), nil
+	default:
+		return "", fmt.Errorf("invalid clock skew policy %q (valid policies are: flag, reject)", name)
+	}
+}
+
+// withDefaults fills in zero values with their defaults
+func (c Config) withDefaults() Config {
+	defaults := DefaultConfig()
+	if c.BootstrapPolicy == "" {
+		c.BootstrapPolicy = defaults.BootstrapPolicy
+	}
+	if c.BootstrapTimeout <= 0 {
+		c.BootstrapTimeout = defaults.BootstrapTimeout
+	}
+	if c.SpeedRetention <= 0 {
+		c.SpeedRetention = defaults.SpeedRetention
+	}
+	if c.EventRetention <= 0 {
+		c.EventRetention = defaults.EventRetention
+	}
+	if c.SilenceTimeout == 0 {
+		c.SilenceTimeout = defaults.SilenceTimeout
+	}
+	if c.SilenceClock == "" {
+		c.SilenceClock = defaults.SilenceClock
+	}
+	if c.ConflictPolicy == "" {
+		c.ConflictPolicy = defaults.ConflictPolicy
+	}
+	if c.MaxClockSkew == 0 {
+		c.MaxClockSkew = defaults.MaxClockSkew
+	}
+	if c.MaxMessageGap == 0 {
+		c.MaxMessageGap = defaults.MaxMessageGap
+	}
+	if c.ClockSkewPolicy == "" {
+		c.ClockSkewPolicy = defaults.ClockSkewPolicy
+	}
+	if c.Registry == nil {
+		c.Registry = defaults.Registry
+	}
+	if c.Now == nil {
+		c.Now = defaults.Now
+	}
+	return c
+}
@@ -0,0 +1,87 @@
+package storage
+
+import (
+	"fmt"
+	"time"
+
+	"lunar-backend-challenge/internal/models"
+)
+
+// DefaultConflictHistory is the number of conflicts kept per rocket for debugging
+const DefaultConflictHistory = 100
+
+// Conflict is a message that repeated the number of an accepted message with a different payload
+type Conflict struct {
+	MessageNumber   int            `json:"messageNumber" example:"3"`
+	MessageType     string         `json:"messageType" example:"RocketSpeedIncreased"` // Type of the conflicting message
+	AcceptedHash    string         `json:"acceptedHash" example:"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"`
+	ConflictingHash string         `json:"conflictingHash" example:"60303ae22b998861bce3b28f33eec1be758a213c86c93c076dbe9f558c11c752"`
+	Policy          ConflictPolicy `json:"policy" example:"first-wins"` // Policy that was applied
+	DetectedAt      time.Time      `json:"detectedAt" example:"2024-03-14T19:39:05.86337+01:00"`
+}
+
+// checkProcessed classifies a message whose number was already processed as an exact
+// duplicate or a conflict. It returns false if the number was not processed yet.
+func (r *RocketRepository) checkProcessed(msg *models.RocketMessage) (ProcessResult, bool) {
+	acceptedHash, processed := r.processedMessages[msg.GetChannel()][msg.GetMessageNumber()]
+	if !processed {
+		return ProcessResult{}, false
+	}
+	return r.checkRepeat(msg, acceptedHash, "processed"), true
+}
+
+// checkBuffered classifies a message that is about to be buffered while a message with the same
+// number already is. It returns false if the number is not buffered yet. A message that can be
+// applied right away is not checked, as it supersedes a buffered copy that could not.
+func (r *RocketRepository) checkBuffered(buffered map[int]*models.RocketMessage, msg *models.RocketMessage) (ProcessResult, bool) {
+	accepted, exists := buffered[msg.GetMessageNumber()]
+	if !exists {
+		return ProcessResult{}, false
+	}
+	return r.checkRepeat(msg, r.registry.ContentHash(accepted), "buffered"), true
+}
+
+// checkRepeat classifies a message repeating an accepted message number as an exact duplicate or a conflict
+func (r *RocketRepository) checkRepeat(msg *models.RocketMessage, acceptedHash, state string) ProcessResult {
+	conflictingHash := r.registry.ContentHash(msg)
+	if conflictingHash == acceptedHash {
+		return ProcessResult{Outcome: OutcomeDuplicate, Reason: "message already " + state}
+	}
+	return r.conflict(msg, acceptedHash, conflictingHash, state)
+}
+
+// conflict records a conflicting message and applies the conflict policy to it
+func (r *RocketRepository) conflict(msg *models.RocketMessage, acceptedHash, conflictingHash, state string) ProcessResult {
+	rocketID := msg.GetChannel()
+	conflict := Conflict{
+		MessageNumber:   msg.GetMessageNumber(),
+		MessageType:     msg.GetMessageType(),
+		AcceptedHash:    acceptedHash,
+		ConflictingHash: conflictingHash,
+		Policy:          r.config.ConflictPolicy,
+		DetectedAt:      r.config.Now(),
+	}
+	r.conflicts[rocketID] = append(r.conflicts[rocketID], conflict)
+	if len(r.conflicts[rocketID]) > DefaultConflictHistory {
+		r.conflicts[rocketID] = r.conflicts[rocketID][len(r.conflicts[rocketID])-DefaultConflictHistory:]
+	}
+
+	// Observers can only be told about rockets that exist
+	if rocket, exists := r.rockets[rocketID]; exists && r.config.ConflictPolicy == ConflictAlert {
+		r.recordChange(ChangeConflict, rocket.Clone(), rocket, msg)
+	}
+
+	reason := fmt.Sprintf("message %d was already %s with a different payload", msg.GetMessageNumber(), state)
+	if r.config.ConflictPolicy == ConflictReject {
+		return ProcessResult{Outcome: OutcomeRejected, Reason: reason, Conflict: &conflict}
+	}
+	return ProcessResult{Outcome: OutcomeConflict, Reason: reason + ", the first message stands", Conflict: &conflict}
+}
+
+// GetConflicts returns the most recent conflicts of a rocket, oldest first
+func (r *RocketRepository) GetConflicts(rocketID string) []Conflict {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	return append([]Conflict(nil), r.conflicts[rocketID]...)
+}
@@ -0,0 +1,120 @@
+package storage
+
+import (
+	"sync"
+	"time"
+
+	"lunar-backend-challenge/internal/models"
+)
+
+// Kinds of state change
+const (
+	// ChangeApplied means a message was applied to the rocket
+	ChangeApplied = "applied"
+	// ChangeSilent means no message was received from a rocket in flight for the silence timeout
+	ChangeSilent = "silent"
+	// ChangeRecovered means a message was received from a silent rocket
+	ChangeRecovered = "recovered"
+	// ChangeConflict means a message reused an accepted message number with a different payload.
+	// The state is unchanged; it is only reported with ConflictAlert.
+	ChangeConflict = "conflict"
+)
+
+// StateChange describes a change to a rocket's state
+type StateChange struct {
+	Kind     string // ChangeApplied, ChangeSilent, ChangeRecovered or ChangeConflict
+	RocketID string
+	Before   *models.RocketState   // Copy of the state before the change, nil for a new rocket
+	After    *models.RocketState   // Copy of the state after the change
+	Message  *models.RocketMessage // Message that caused the change (the conflicting one for ChangeConflict), nil when a rocket went silent
+	At       time.Time             // When the change happened, by the repository clock
+}
+
+// Observer is called with the state changes of rockets, in the order they were applied.
+// Observers run after the repository lock is released and may read from the repository,
+// but must not process messages, as that would wait for their own delivery to finish.
+type Observer func(StateChange)
+
+// Subscribe registers an observer for every state change
+func (r *RocketRepository) Subscribe(observer Observer) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.observers = append(r.observers, observer)
+}
+
+// recordChange queues a state change for the observers, unless a rocket's history is being replayed
+func (r *RocketRepository) recordChange(kind string, before *models.RocketState, rocket *models.RocketState, msg *models.RocketMessage) {
+	if len(r.observers) == 0 || r.replaying {
+		return
+	}
+	r.changes = append(r.changes, StateChange{
+		Kind:     kind,
+		RocketID: rocket.ID,
+		Before:   before,
+		After:    rocket.Clone(),
+		Message:  msg,
+		At:       r.config.Now(),
+	})
+}
+
+// queueChanges hands the state changes recorded so far to the notifier, in the order they were
+// applied. Must hold the lock.
+func (r *RocketRepository) queueChanges() {
+	if len(r.changes) == 0 {
+		return
+	}
+	r.notifier.enqueue(r.changes, r.observers)
+	r.changes = nil
+}
+
+// deliverChanges notifies the observers of every queued state change. Must not hold the lock.
+func (r *RocketRepository) deliverChanges() {
+	r.notifier.deliver()
+}
+
+// notifier delivers state changes to the observers outside the repository lock, but one at a time
+// and in the order they were queued, so concurrent writers cannot overtake each other
+type notifier struct {
+	queue        []notification
+	queueMutex   sync.Mutex
+	deliverMutex sync.Mutex // Held while delivering, so only one caller notifies at a time
+}
+
+// notification is a batch of state changes with the observers subscribed when they were applied
+type notification struct {
+	changes   []StateChange
+	observers []Observer
+}
+
+// enqueue adds state changes to the end of the queue
+func (n *notifier) enqueue(changes []StateChange, observers []Observer) {
+	n.queueMutex.Lock()
+	defer n.queueMutex.Unlock()
+	n.queue = append(n.queue, notification{changes: changes, observers: observers})
+}
+
+// deliver notifies the observers until the queue is empty. A caller whose changes are being
+// delivered by another caller waits for it, so its changes are delivered when deliver returns.
+func (n *notifier) deliver() {
+	n.deliverMutex.Lock()
+	defer n.deliverMutex.Unlock()
+
+	for {
+		n.queueMutex.Lock()
+		queue := n.queue
+		n.queue = nil
+		n.queueMutex.Unlock()
+		if len(queue) == 0 {
+			return
+		}
+
+		for _, batch := range queue {
+			for _, change := range batch.changes {
+				for _, observer := range batch.observers {
+					observer(change)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package storage
+
+import (
+	"time"
+
+	"lunar-backend-challenge/internal/models"
+)
+
+// DefaultEventRetention is the number of message events kept per rocket
+const DefaultEventRetention = 1000
+
+// MessageEvent is a message processed in sequence for a rocket, with the state it left the rocket in
+type MessageEvent struct {
+	MessageNumber int       `json:"messageNumber" example:"3"`
+	MessageType   string    `json:"messageType" example:"RocketSpeedIncreased"`
+	MessageTime   time.Time `json:"messageTime" example:"2024-03-14T19:39:05.86337+01:00"`
+	ReceivedAt    time.Time `json:"receivedAt" example:"2024-03-14T19:39:05.91337+01:00"`
+	Outcome       Outcome   `json:"outcome" example:"applied"` // applied or ignored
+	Reason        string    `json:"reason" example:""`         // Why the message was ignored
+	Speed         int       `json:"speed" example:"3500"`
+	Mission       string    `json:"mission" example:"ARTEMIS"`
+	Status        string    `json:"status" example:"active"`
+}
+
+// recordEvent appends a message processed in sequence to the rocket's event history,
+// dropping the oldest events beyond the retention limit
+func (r *RocketRepository) recordEvent(rocket *models.RocketState, msg *models.RocketMessage, result ProcessResult) {
+	events := append(r.events[rocket.ID], MessageEvent{
+		MessageNumber: msg.GetMessageNumber(),
+		MessageType:   msg.GetMessageType(),
+		MessageTime:   msg.GetMessageTime(),
+		ReceivedAt:    msg.ReceivedAt,
+		Outcome:       result.Outcome,
+		Reason:        result.Reason,
+		Speed:         rocket.Speed,
+		Mission:       rocket.Mission,
+		Status:        rocket.Status,
+	})
+	if len(events) > r.config.EventRetention {
+		events = events[len(events)-r.config.EventRetention:]
+	}
+	r.events[rocket.ID] = events
+}
+
+// GetEvents returns the most recent messages processed in sequence for a rocket, in message
+// number order. It returns false if the rocket does not exist.
+func (r *RocketRepository) GetEvents(rocketID string) ([]MessageEvent, bool) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	if _, exists := r.rockets[rocketID]; !exists {
+		return nil, false
+	}
+	return append([]MessageEvent{}, r.events[rocketID]...), true
+}
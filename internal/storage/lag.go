@@ -0,0 +1,117 @@
+package storage
+
+import (
+	"time"
+
+	"lunar-backend-challenge/internal/metrics"
+	"lunar-backend-challenge/internal/models"
+)
+
+// LagStats describes how far behind real time the state of a rocket is. Receive lag is the
+// receive time minus the messageTime, apply lag the apply time minus the receive time.
+type LagStats struct {
+	Messages                 int       `json:"messages" example:"42"` // Messages applied since the rocket was first seen
+	LastReceivedAt           time.Time `json:"lastReceivedAt" example:"2024-03-14T19:39:05.86337+01:00"`
+	LastAppliedAt            time.Time `json:"lastAppliedAt" example:"2024-03-14T19:39:06.12345+01:00"`
+	LastReceiveLagSeconds    float64   `json:"lastReceiveLagSeconds" example:"0.12"`
+	LastApplyLagSeconds      float64   `json:"lastApplyLagSeconds" example:"0.26"`
+	AverageReceiveLagSeconds float64   `json:"averageReceiveLagSeconds" example:"0.08"`
+	AverageApplyLagSeconds   float64   `json:"averageApplyLagSeconds" example:"0.01"`
+	MaxReceiveLagSeconds     float64   `json:"maxReceiveLagSeconds" example:"1.5"`
+	MaxApplyLagSeconds       float64   `json:"maxApplyLagSeconds" example:"3.2"`
+}
+
+// lagHistograms holds the receive and apply lag distributions of a rocket or the fleet
+type lagHistograms struct {
+	receive *metrics.Histogram
+	apply   *metrics.Histogram
+}
+
+func newLagHistograms() lagHistograms {
+	return lagHistograms{
+		receive: metrics.NewHistogram(metrics.DefaultLatencyBuckets),
+		apply:   metrics.NewHistogram(metrics.DefaultLatencyBuckets),
+	}
+}
+
+// rocketLag tracks the lag of a rocket's applied messages
+type rocketLag struct {
+	lagHistograms
+	stats LagStats
+}
+
+// stampReceived returns the copy of a message the repository keeps, with the receive time set if the
+// caller did not set it. The caller's message is left untouched, as it may be reused or shared.
+func (r *RocketRepository) stampReceived(msg *models.RocketMessage) *models.RocketMessage {
+	stamped := *msg
+	if stamped.ReceivedAt.IsZero() {
+		stamped.ReceivedAt = r.config.Now()
+	}
+	return &stamped
+}
+
+// recordLag stamps the repository's copy of a message with its apply time and records its lags. Negative lags, from a
+// rocket clock running ahead, count as zero. History replayed when reconciling keeps its apply
+// time and only rebuilds the lag of the rocket, as the fleet already counted it.
+func (r *RocketRepository) recordLag(rocket *models.RocketState, msg *models.RocketMessage) {
+	firstApply := msg.AppliedAt.IsZero()
+	if firstApply {
+		msg.AppliedAt = r.config.Now()
+	}
+	receiveLag := max(msg.ReceivedAt.Sub(msg.GetMessageTime()), 0).Seconds()
+	applyLag := max(msg.AppliedAt.Sub(msg.ReceivedAt), 0).Seconds()
+
+	lag := r.lag[rocket.ID]
+	if lag == nil {
+		lag = &rocketLag{lagHistograms: newLagHistograms()}
+		r.lag[rocket.ID] = lag
+	}
+	histograms := []lagHistograms{lag.lagHistograms}
+	if firstApply {
+		histograms = append(histograms, r.fleetLag)
+	}
+	for _, h := range histograms {
+		h.receive.Observe(receiveLag)
+		h.apply.Observe(applyLag)
+	}
+
+	stats := &lag.stats
+	stats.Messages++
+	stats.LastReceivedAt = msg.ReceivedAt
+	stats.LastAppliedAt = msg.AppliedAt
+	stats.LastReceiveLagSeconds = receiveLag
+	stats.LastApplyLagSeconds = applyLag
+	stats.AverageReceiveLagSeconds = lag.receive.Sum() / float64(lag.receive.Count())
+	stats.AverageApplyLagSeconds = lag.apply.Sum() / float64(lag.apply.Count())
+	stats.MaxReceiveLagSeconds = max(stats.MaxReceiveLagSeconds, receiveLag)
+	stats.MaxApplyLagSeconds = max(stats.MaxApplyLagSeconds, applyLag)
+}
+
+// GetLag returns the lag of a rocket's applied messages
+func (r *RocketRepository) GetLag(rocketID string) (LagStats, bool) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	lag, exists := r.lag[rocketID]
+	if !exists {
+		return LagStats{}, false
+	}
+	return lag.stats, true
+}
+
+// lagSamples returns the fleet-wide and per-rocket lag histograms. Must hold the lock.
+func (r *RocketRepository) lagSamples() []metrics.Sample {
+	samples := r.fleetLag.receive.Samples("rocket_message_receive_lag_seconds",
+		"Time between the messageTime and the receipt of applied messages, across the fleet", nil)
+	samples = append(samples, r.fleetLag.apply.Samples("rocket_message_apply_lag_seconds",
+		"Time between the receipt and the application of messages, including time spent buffered, across the fleet", nil)...)
+
+	for rocketID, lag := range r.lag {
+		labels := map[string]string{"rocket": rocketID}
+		samples = append(samples, lag.receive.Samples("rocket_receive_lag_seconds",
+			"Time between the messageTime and the receipt of applied messages, per rocket", labels)...)
+		samples = append(samples, lag.apply.Samples("rocket_apply_lag_seconds",
+			"Time between the receipt and the application of messages, including time spent buffered, per rocket", labels)...)
+	}
+	return samples
+}
@@ -0,0 +1,107 @@
+package storage
+
+import (
+	"sort"
+
+	"lunar-backend-challenge/internal/models"
+)
+
+// missionIndex collects the assignments of every mission while scanning the rockets
+type missionIndex struct {
+	missions map[string]*models.Mission
+	rockets  map[string]map[string]bool // Distinct rockets per mission key
+	lastUsed map[string]int64           // Start of the latest assignment per mission key, to pick the display name
+}
+
+// buildMissionIndex groups the mission assignments of all rockets by normalized mission name.
+// A non-empty onlyKey limits the index to that mission.
+func (r *RocketRepository) buildMissionIndex(onlyKey string) *missionIndex {
+	index := &missionIndex{
+		missions: make(map[string]*models.Mission),
+		rockets:  make(map[string]map[string]bool),
+		lastUsed: make(map[string]int64),
+	}
+
+	for _, rocket := range r.rockets {
+		for _, assignment := range rocket.Assignments {
+			key := models.MissionKey(assignment.Mission)
+			if onlyKey != "" && key != onlyKey {
+				continue
+			}
+			mission, exists := index.missions[key]
+			if !exists {
+				mission = &models.Mission{
+					CurrentRockets:    []models.MissionRocket{},
+					HistoricalRockets: []models.MissionRocket{},
+				}
+				index.missions[key] = mission
+				index.rockets[key] = make(map[string]bool)
+			}
+
+			if from := assignment.From.UnixNano(); !exists || from >= index.lastUsed[key] {
+				mission.Name = assignment.Mission
+				index.lastUsed[key] = from
+			}
+
+			entry := models.MissionRocket{
+				RocketID:  rocket.ID,
+				Type:      rocket.Type,
+				From:      assignment.From,
+				To:        assignment.To,
+				EndReason: assignment.EndReason,
+			}
+			if assignment.EndReason == "" {
+				mission.CurrentRockets = append(mission.CurrentRockets, entry)
+			} else {
+				mission.HistoricalRockets = append(mission.HistoricalRockets, entry)
+			}
+			index.rockets[key][rocket.ID] = true
+		}
+	}
+
+	for key, mission := range index.missions {
+		mission.CurrentRocketCount = len(mission.CurrentRockets)
+		mission.RocketCount = len(index.rockets[key])
+		mission.AssignmentCount = len(mission.CurrentRockets) + len(mission.HistoricalRockets)
+		sortMissionRockets(mission.CurrentRockets)
+		sortMissionRockets(mission.HistoricalRockets)
+	}
+	return index
+}
+
+// sortMissionRockets orders assignments by start time, then rocket ID
+func sortMissionRockets(rockets []models.MissionRocket) {
+	sort.Slice(rockets, func(i, j int) bool {
+		if !rockets[i].From.Equal(rockets[j].From) {
+			return rockets[i].From.Before(rockets[j].From)
+		}
+		return rockets[i].RocketID < rockets[j].RocketID
+	})
+}
+
+// GetMissions returns a summary of every mission any rocket was assigned to, ordered by name
+func (r *RocketRepository) GetMissions() []models.MissionSummary {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	index := r.buildMissionIndex("")
+	summaries := make([]models.MissionSummary, 0, len(index.missions))
+	for _, mission := range index.missions {
+		summaries = append(summaries, mission.MissionSummary)
+	}
+
+	sort.Slice(summaries, func(i, j int) bool {
+		return models.MissionKey(summaries[i].Name) < models.MissionKey(summaries[j].Name)
+	})
+	return summaries
+}
+
+// GetMission returns the current and historical rockets of a mission, matching the name case-insensitively
+func (r *RocketRepository) GetMission(name string) (*models.Mission, bool) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	key := models.MissionKey(name)
+	mission, exists := r.buildMissionIndex(key).missions[key]
+	return mission, exists
+}
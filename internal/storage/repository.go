@@ -2,27 +2,74 @@ package storage
 
 import (
 	"sync"
+	"time"
 
+	"lunar-backend-challenge/internal/lifecycle"
 	"lunar-backend-challenge/internal/models"
+	"lunar-backend-challenge/internal/registry"
 )
 
 // RocketRepository provides storage for rockets with out-of-order message handling
 type RocketRepository struct {
 	rockets           map[string]*models.RocketState
-	processedMessages map[string]map[int]bool                  // Track processed messages for deduplication
+	processedMessages map[string]map[int]string                // Content hash of each processed message, for deduplication
 	pendingMessages   map[string]map[int]*models.RocketMessage // Buffer for out-of-order messages
+	firstBufferedAt   map[string]time.Time                     // When messages started buffering for a rocket that does not exist yet
+	backfill          map[string]map[int]*models.RocketMessage // Missing history received for partially bootstrapped rockets
+	partialHistory    map[string][]*models.RocketMessage       // Messages applied to partially bootstrapped rockets, replayed on reconcile
+	conflicts         map[string][]Conflict                    // Recent conflicting duplicates per rocket
+	messageTimes      map[string]map[int]time.Time             // messageTime of the message numbers seen near the sequence head, for anomaly detection
+	anomalies         map[string][]Anomaly                     // Recent timing anomalies per rocket
+	anomalyCounts     map[string]int                           // Timing anomalies detected per kind
+	skewRejected      int                                      // Messages rejected for clock skew
+	lag               map[string]*rocketLag                    // Receive and apply lag per rocket
+	fleetLag          lagHistograms                            // Receive and apply lag across the fleet
+	speedSeries       map[string]*speedSeries                  // Speed telemetry per rocket
+	events            map[string][]MessageEvent                // Recent messages processed in sequence per rocket
+	stats             *fleetStats                              // Incrementally maintained fleet statistics
+	observers         []Observer                               // Notified of every state change
+	changes           []StateChange                            // State changes not yet queued for the observers
+	notifier          notifier                                 // Delivers queued state changes in apply order
+	replaying         bool                                     // Set while reconciling, so replayed history is not reported again
+	registry          *registry.Registry                       // Decoders, validators and reducers per message type
+	config            Config                                   // Bootstrap policy, retention limits and clock
 	mutex             sync.RWMutex                             // Thread-safe access
 }
 
-// NewRocketRepository creates a new rocket repository
+// NewRocketRepository creates a new rocket repository with the default configuration
 func NewRocketRepository() *RocketRepository {
+	return NewRocketRepositoryWithConfig(DefaultConfig())
+}
+
+// NewRocketRepositoryWithConfig creates a new rocket repository with the given configuration
+func NewRocketRepositoryWithConfig(config Config) *RocketRepository {
+	config = config.withDefaults()
 	return &RocketRepository{
 		rockets:           make(map[string]*models.RocketState),
-		processedMessages: make(map[string]map[int]bool),
+		processedMessages: make(map[string]map[int]string),
 		pendingMessages:   make(map[string]map[int]*models.RocketMessage),
+		firstBufferedAt:   make(map[string]time.Time),
+		backfill:          make(map[string]map[int]*models.RocketMessage),
+		partialHistory:    make(map[string][]*models.RocketMessage),
+		conflicts:         make(map[string][]Conflict),
+		messageTimes:      make(map[string]map[int]time.Time),
+		anomalies:         make(map[string][]Anomaly),
+		anomalyCounts:     make(map[string]int),
+		lag:               make(map[string]*rocketLag),
+		fleetLag:          newLagHistograms(),
+		speedSeries:       make(map[string]*speedSeries),
+		events:            make(map[string][]MessageEvent),
+		stats:             newFleetStats(),
+		registry:          config.Registry,
+		config:            config,
 	}
 }
 
+// Registry returns the message types the repository applies, for decoding and validating its input
+func (r *RocketRepository) Registry() *registry.Registry {
+	return r.registry
+}
+
 // GetRocket retrieves a rocket by its ID
 func (r *RocketRepository) GetRocket(id string) (*models.RocketState, bool) {
 	r.mutex.RLock()
@@ -34,8 +81,7 @@ func (r *RocketRepository) GetRocket(id string) (*models.RocketState, bool) {
 	}
 
 	// Return a copy to avoid data races
-	rocketCopy := *rocket
-	return &rocketCopy, true
+	return rocket.Clone(), true
 }
 
 // GetAllRockets returns all rockets as summaries
@@ -52,48 +98,132 @@ func (r *RocketRepository) GetAllRockets() []models.RocketSummary {
 			Speed:     rocket.Speed,
 			Mission:   rocket.Mission,
 			Exploded:  rocket.Exploded,
+			Status:    rocket.Status,
 			UpdatedAt: rocket.UpdatedAt,
+			Partial:   rocket.Partial,
+			Silent:    rocket.Silent,
+			LastSeen:  rocket.LastSeen,
 		})
 	}
 
 	return summaries
 }
 
-// ProcessMessage processes a rocket message with deduplication and out-of-order handling
+// Outcome describes what happened to a message handed to the repository
+type Outcome string
+
+const (
+	// OutcomeApplied means the message changed the rocket state
+	OutcomeApplied Outcome = "applied"
+	// OutcomeBuffered means the message is held until the messages before it arrive
+	OutcomeBuffered Outcome = "buffered"
+	// OutcomeDuplicate means the message was already processed or buffered
+	OutcomeDuplicate Outcome = "duplicate"
+	// OutcomeConflict means a message with the same number but a different payload was already
+	// processed or buffered. The first message stands; with ConflictReject the outcome is OutcomeRejected instead.
+	OutcomeConflict Outcome = "conflict"
+	// OutcomeIgnored means the message was accounted for in the sequence but had no effect,
+	// e.g. a speed change for a rocket that has exploded and not been relaunched yet
+	OutcomeIgnored Outcome = "ignored"
+	// OutcomeRejected means the message could not be applied and the sequence did not advance
+	OutcomeRejected Outcome = "rejected"
+)
+
+// ProcessResult reports the outcome of processing a message and why
+type ProcessResult struct {
+	Outcome  Outcome
+	Reason   string
+	Conflict *Conflict // Set if the message conflicts with an accepted one, whatever the outcome
+	Anomaly  *Anomaly  // Set if the message was rejected for a timing anomaly
+}
+
+// ProcessMessage processes a rocket message and reports whether it was accepted,
+// i.e. the outcome is anything but OutcomeRejected
 func (r *RocketRepository) ProcessMessage(msg *models.RocketMessage) bool {
+	return r.Process(msg).Outcome != OutcomeRejected
+}
+
+// Process processes a rocket message with deduplication and out-of-order handling
+// and notifies the observers of the resulting state changes
+func (r *RocketRepository) Process(msg *models.RocketMessage) ProcessResult {
 	r.mutex.Lock()
-	defer r.mutex.Unlock()
+	result := r.process(msg)
+	r.queueChanges()
+	r.mutex.Unlock()
 
+	r.deliverChanges()
+	return result
+}
+
+// process processes a rocket message. Must hold the lock.
+func (r *RocketRepository) process(msg *models.RocketMessage) ProcessResult {
 	rocketID := msg.GetChannel()
 	msgNumber := msg.GetMessageNumber()
 
+	// Keep the fleet statistics in line with whatever this message changed
+	defer r.refreshStats(rocketID)
+
+	msg = r.stampReceived(msg)
+
 	// Initialize maps for this rocket if they don't exist
 	if r.processedMessages[rocketID] == nil {
-		r.processedMessages[rocketID] = make(map[int]bool)
+		r.processedMessages[rocketID] = make(map[int]string)
 	}
 	if r.pendingMessages[rocketID] == nil {
 		r.pendingMessages[rocketID] = make(map[int]*models.RocketMessage)
 	}
 
-	// Check for duplicate message (at-least-once guarantee)
-	if r.processedMessages[rocketID][msgNumber] {
-		return true // Already processed, ignore duplicate
+	// Any message shows the rocket is still transmitting, even a retransmission or one that cannot be applied
+	rocket, exists := r.rockets[rocketID]
+	if exists {
+		r.markSeen(rocket, msg)
 	}
 
-	// Get or create rocket
-	rocket, exists := r.rockets[rocketID]
+	// Check for duplicate message (at-least-once guarantee), telling retransmissions
+	// apart from conflicting messages that reuse an accepted message number
+	if result, repeated := r.checkProcessed(msg); repeated {
+		return result
+	}
+
+	// Check the message time against the server clock and the neighbouring message numbers
+	if anomaly := r.checkMessageTime(msg); anomaly != nil {
+		return ProcessResult{Outcome: OutcomeRejected, Reason: anomaly.Detail, Anomaly: anomaly}
+	}
+
+	// Create the rocket if it does not exist yet
 	if !exists {
-		// Only create new rocket if it's a launch message
-		if msg.GetMessageType() != models.MessageTypeRocketLaunched {
-			// Buffer non-launch messages for rockets that don't exist yet
+		// Only create new rocket from a message that starts the sequence and can create one (a launch message)
+		if msgNumber != 1 || !r.createsRocket(msg) {
+			// Buffer other messages for rockets that don't exist yet, see BootstrapPending
+			if result, repeated := r.checkBuffered(r.pendingMessages[rocketID], msg); repeated {
+				return result
+			}
 			r.pendingMessages[rocketID][msgNumber] = msg
-			return true
+			if _, waiting := r.firstBufferedAt[rocketID]; !waiting {
+				r.firstBufferedAt[rocketID] = r.config.Now()
+			}
+			return ProcessResult{Outcome: OutcomeBuffered, Reason: "rocket has not been launched yet"}
 		}
 		rocket = &models.RocketState{
 			ID:                         rocketID,
+			Status:                     models.RocketStatusPendingLaunch,
 			LastProcessedMessageNumber: 0,
 		}
+		// Messages buffered before the launch were received from the rocket too
+		for _, pending := range r.pendingMessages[rocketID] {
+			if pending.GetMessageTime().After(rocket.LastMessageTime) {
+				rocket.LastMessageTime = pending.GetMessageTime()
+			}
+		}
 		r.rockets[rocketID] = rocket
+		delete(r.firstBufferedAt, rocketID)
+		r.markSeen(rocket, msg)
+	}
+	r.markMessageTime(rocket, msg)
+
+	// Missing history of a partially bootstrapped rocket is kept until the rocket can be reconciled
+	if rocket.Partial && msgNumber < rocket.BootstrappedFrom {
+		return r.bufferBackfill(rocket, msg)
 	}
 
 	// Check if this is the next expected message in sequence
@@ -101,24 +231,23 @@ func (r *RocketRepository) ProcessMessage(msg *models.RocketMessage) bool {
 
 	if msgNumber == expectedMsgNumber {
 		// Process this message immediately
-		if r.processMessageByType(rocket, msg) {
-			r.processedMessages[rocketID][msgNumber] = true
-			rocket.LastProcessedMessageNumber = msgNumber
-			rocket.UpdatedAt = msg.GetMessageTime()
-
+		result := r.applyInSequence(rocket, msg)
+		if result.Outcome != OutcomeRejected {
 			// Try to process any pending messages that are now in sequence
 			r.processPendingMessages(rocketID)
-			return true
 		}
-		return false
+		return result
 	} else if msgNumber > expectedMsgNumber {
 		// Message is out of order - buffer it for later processing
+		if result, repeated := r.checkBuffered(r.pendingMessages[rocketID], msg); repeated {
+			return result
+		}
 		r.pendingMessages[rocketID][msgNumber] = msg
-		return true
+		return ProcessResult{Outcome: OutcomeBuffered, Reason: "waiting for earlier messages"}
 	}
 
-	// Message is older than expected (already processed or very old)
-	return false
+	// Message is older than expected but was never processed
+	return ProcessResult{Outcome: OutcomeRejected, Reason: "message is older than the last processed message"}
 }
 
 // processPendingMessages processes any buffered messages that are now in sequence
@@ -135,104 +264,89 @@ func (r *RocketRepository) processPendingMessages(rocketID string) {
 			break // No more sequential messages available
 		}
 
-		// Check if rocket exploded and only allow relaunch messages
-		if rocket.Exploded && msg.GetMessageType() != models.MessageTypeRocketLaunched {
-			// Remove the message from pending and continue
-			delete(pendingForRocket, nextMsgNumber)
-			continue
-		}
+		// Remove the message from pending whatever the outcome
+		delete(pendingForRocket, nextMsgNumber)
 
-		// Process the message
-		if r.processMessageByType(rocket, msg) {
-			r.processedMessages[rocketID][nextMsgNumber] = true
-			rocket.LastProcessedMessageNumber = nextMsgNumber
-			rocket.UpdatedAt = msg.GetMessageTime()
-
-			// Remove processed message from pending
-			delete(pendingForRocket, nextMsgNumber)
-		} else {
-			// Failed to process - remove from pending and stop
-			delete(pendingForRocket, nextMsgNumber)
-			break
+		if result := r.applyInSequence(rocket, msg); result.Outcome == OutcomeRejected {
+			break // Failed to process - stop until the message is sent again
 		}
 	}
 }
 
-// processMessageByType handles different message types
-func (r *RocketRepository) processMessageByType(rocket *models.RocketState, msg *models.RocketMessage) bool {
-	// If rocket exploded, only allow relaunch messages
-	if rocket.Exploded && msg.GetMessageType() != models.MessageTypeRocketLaunched {
-		return false
+// applyInSequence applies the next expected message and advances the sequence unless it was rejected.
+// Ignored messages still advance the sequence so later messages (e.g. a relaunch) are not stalled.
+func (r *RocketRepository) applyInSequence(rocket *models.RocketState, msg *models.RocketMessage) ProcessResult {
+	var before *models.RocketState
+	if len(r.observers) > 0 && rocket.Status != models.RocketStatusPendingLaunch {
+		before = rocket.Clone()
 	}
 
-	switch msg.GetMessageType() {
+	result := r.processMessageByType(rocket, msg)
+	if result.Outcome == OutcomeRejected {
+		return result
+	}
 
-	case models.MessageTypeRocketLaunched:
-		// Validate required fields
-		if msg.Message.Type == "" || msg.Message.Mission == "" {
-			return false
-		}
+	// A buffered copy of this message number is superseded now that the number is processed
+	delete(r.pendingMessages[rocket.ID], msg.GetMessageNumber())
 
-		// Reset rocket state for new launch (can relaunch exploded rockets)
-		rocket.Type = msg.Message.Type
-		rocket.Mission = msg.Message.Mission
-		rocket.Speed = msg.Message.LaunchSpeed
-		rocket.Exploded = false
-		rocket.Reason = ""
+	r.processedMessages[rocket.ID][msg.GetMessageNumber()] = r.registry.ContentHash(msg)
+	previous := rocket.LastProcessedMessageNumber
+	rocket.LastProcessedMessageNumber = msg.GetMessageNumber()
+	r.pruneMessageTimes(rocket, previous)
+	if rocket.Partial {
+		r.partialHistory[rocket.ID] = append(r.partialHistory[rocket.ID], msg)
+	}
+	r.recordLag(rocket, msg)
+	r.recordEvent(rocket, msg, result)
+	if result.Outcome == OutcomeApplied {
+		rocket.UpdatedAt = msg.GetMessageTime()
+		r.recordChange(ChangeApplied, before, rocket, msg)
+	}
+	return result
+}
 
-		// Set created time only for first launch
-		if rocket.CreatedAt.IsZero() {
-			rocket.CreatedAt = msg.GetMessageTime()
-		}
-		return true
+// processMessageByType applies a message using the reducer registered for its type
+func (r *RocketRepository) processMessageByType(rocket *models.RocketState, msg *models.RocketMessage) ProcessResult {
+	messageType, exists := r.registry.Lookup(msg.GetMessageType())
+	if !exists {
+		return ProcessResult{Outcome: OutcomeRejected, Reason: "unknown message type " + msg.GetMessageType()}
+	}
 
-	case models.MessageTypeRocketSpeedIncreased:
-		if msg.Message.By <= 0 {
-			return false
-		}
-		rocket.Speed += msg.Message.By
-		return true
+	// Illegal lifecycle transitions (e.g. a speed change after an explosion) have no effect
+	// but are still accounted for, so they do not stall a later relaunch
+	status, err := lifecycle.Transition(rocket.Status, messageType.Event)
+	if err != nil {
+		return ProcessResult{Outcome: OutcomeIgnored, Reason: err.Error()}
+	}
 
-	case models.MessageTypeRocketSpeedDecreased:
-		if msg.Message.By <= 0 {
-			return false
-		}
-		rocket.Speed -= msg.Message.By
-		if rocket.Speed < 0 {
-			rocket.Speed = 0
-		}
-		return true
+	speedBefore := rocket.Speed
+	if !messageType.Apply(rocket, msg) {
+		return ProcessResult{Outcome: OutcomeRejected, Reason: "message payload could not be applied"}
+	}
 
-	case models.MessageTypeRocketExploded:
-		if msg.Message.Reason == "" {
-			return false
-		}
-		rocket.Exploded = true
-		rocket.Reason = msg.Message.Reason
-
-		// Clear pending messages for exploded rocket (except launch messages)
-		rocketID := rocket.ID
-		if pendingForRocket, exists := r.pendingMessages[rocketID]; exists {
-			for msgNum, pendingMsg := range pendingForRocket {
-				if pendingMsg.GetMessageType() != models.MessageTypeRocketLaunched {
-					delete(pendingForRocket, msgNum)
-				}
-			}
-		}
-		return true
+	rocket.Status = status
+	rocket.Exploded = status == models.RocketStatusExploded
 
-	case models.MessageTypeRocketMissionChanged:
-		if msg.Message.NewMission == "" {
-			return false
-		}
-		rocket.Mission = msg.Message.NewMission
-		return true
+	// A launch starts the speed chart even if the speed did not change
+	if rocket.Speed != speedBefore || messageType.Event == lifecycle.EventLaunch {
+		r.recordSpeed(rocket, msg)
+	}
+	return ProcessResult{Outcome: OutcomeApplied}
+}
 
-	default:
-		return false
+// refreshStats updates the fleet statistics with the current state of a rocket, if it exists
+func (r *RocketRepository) refreshStats(rocketID string) {
+	if rocket, exists := r.rockets[rocketID]; exists {
+		r.stats.update(rocket)
 	}
 }
 
+// createsRocket reports whether a message may create a rocket that does not exist yet
+func (r *RocketRepository) createsRocket(msg *models.RocketMessage) bool {
+	messageType, exists := r.registry.Lookup(msg.GetMessageType())
+	return exists && lifecycle.CanTransition(models.RocketStatusPendingLaunch, messageType.Event)
+}
+
 // GetDebugInfo returns debug information for a rocket
 func (r *RocketRepository) GetDebugInfo(rocketID string) (processedCount int, pendingMessages []int) {
 	r.mutex.RLock()
@@ -248,5 +362,10 @@ func (r *RocketRepository) GetDebugInfo(rocketID string) (processedCount int, pe
 		}
 	}
 
+	// Backfilled history of a partial rocket is also still waiting to be applied
+	for msgNum := range r.backfill[rocketID] {
+		pendingMessages = append(pendingMessages, msgNum)
+	}
+
 	return processedCount, pendingMessages
 }
@@ -0,0 +1,68 @@
+package storage
+
+import (
+	"sort"
+	"time"
+
+	"lunar-backend-challenge/internal/lifecycle"
+	"lunar-backend-challenge/internal/models"
+)
+
+// markSeen records that a message of the rocket was received, recovering it if it was silent,
+// whether the message is accepted or not. Must hold the lock.
+func (r *RocketRepository) markSeen(rocket *models.RocketState, msg *models.RocketMessage) {
+	rocket.LastSeen = r.config.Now()
+
+	if rocket.Silent {
+		before := rocket.Clone()
+		rocket.Silent = false
+		rocket.SilentSince = nil
+		r.recordChange(ChangeRecovered, before, rocket, msg)
+	}
+}
+
+// markMessageTime records the message time of a message that passed the timing checks. Must hold the lock.
+func (r *RocketRepository) markMessageTime(rocket *models.RocketState, msg *models.RocketMessage) {
+	if msg.GetMessageTime().After(rocket.LastMessageTime) {
+		rocket.LastMessageTime = msg.GetMessageTime()
+	}
+}
+
+// DetectSilence marks rockets in flight silent when no message was received for the silence
+// timeout, measured by the configured clock, and notifies the observers.
+// It returns the IDs of the rockets that went silent.
+func (r *RocketRepository) DetectSilence(now time.Time) []string {
+	if r.config.SilenceTimeout < 0 {
+		return nil
+	}
+
+	r.mutex.Lock()
+	var silenced []string
+	for rocketID, rocket := range r.rockets {
+		if rocket.Silent || !lifecycle.InFlight(rocket.Status) {
+			continue
+		}
+
+		last := rocket.LastSeen
+		if r.config.SilenceClock == SilenceByMessageTime {
+			last = rocket.LastMessageTime
+		}
+		if last.IsZero() || now.Sub(last) < r.config.SilenceTimeout {
+			continue
+		}
+
+		before := rocket.Clone()
+		silentSince := now
+		rocket.Silent = true
+		rocket.SilentSince = &silentSince
+		r.recordChange(ChangeSilent, before, rocket, nil)
+		r.refreshStats(rocketID)
+		silenced = append(silenced, rocketID)
+	}
+	r.queueChanges()
+	r.mutex.Unlock()
+	r.deliverChanges()
+
+	sort.Strings(silenced)
+	return silenced
+}
@@ -0,0 +1,139 @@
+package storage
+
+import (
+	"sort"
+	"time"
+
+	"lunar-backend-challenge/internal/models"
+)
+
+// DefaultSpeedRetention is the number of speed points kept per rocket
+const DefaultSpeedRetention = 1000
+
+// speedSample is a compact speed point, times are kept as Unix nanoseconds
+type speedSample struct {
+	messageNumber int
+	messageTime   int64
+	speed         int
+}
+
+// speedSeries holds the most recent speed samples of a rocket in message sequence order
+type speedSeries struct {
+	samples []speedSample
+	limit   int
+}
+
+// add appends a sample, dropping the oldest ones beyond the retention limit
+func (s *speedSeries) add(sample speedSample) {
+	s.samples = append(s.samples, sample)
+	if len(s.samples) > 2*s.limit {
+		// Reallocate now and then instead of on every append so old samples can be freed
+		kept := make([]speedSample, s.limit, 2*s.limit)
+		copy(kept, s.samples[len(s.samples)-s.limit:])
+		s.samples = kept
+	}
+}
+
+// retained returns the samples within the retention limit
+func (s *speedSeries) retained() []speedSample {
+	if len(s.samples) > s.limit {
+		return s.samples[len(s.samples)-s.limit:]
+	}
+	return s.samples
+}
+
+// recordSpeed adds a speed point after a launch or an applied speed change
+func (r *RocketRepository) recordSpeed(rocket *models.RocketState, msg *models.RocketMessage) {
+	series, exists := r.speedSeries[rocket.ID]
+	if !exists {
+		series = &speedSeries{limit: r.config.SpeedRetention}
+		r.speedSeries[rocket.ID] = series
+	}
+	series.add(speedSample{
+		messageNumber: msg.GetMessageNumber(),
+		messageTime:   msg.GetMessageTime().UnixNano(),
+		speed:         rocket.Speed,
+	})
+}
+
+// SpeedBucket summarizes the speed points of a rocket within a time interval
+type SpeedBucket struct {
+	Start              time.Time `json:"start" example:"2024-03-14T19:39:00Z"`
+	End                time.Time `json:"end" example:"2024-03-14T19:40:00Z"`
+	Count              int       `json:"count" example:"4"`
+	Min                int       `json:"min" example:"500"`
+	Max                int       `json:"max" example:"3500"`
+	Avg                float64   `json:"avg" example:"2125.5"`
+	Last               int       `json:"last" example:"3000"` // Speed after the highest message number in the bucket
+	FirstMessageNumber int       `json:"firstMessageNumber" example:"1"`
+	LastMessageNumber  int       `json:"lastMessageNumber" example:"5"`
+}
+
+// GetSpeedBuckets downsamples the speed series of a rocket into buckets of step width, keeping
+// points with from <= messageTime < to (zero times are unbounded). A zero step returns one bucket per point.
+// Buckets are ordered by time, points within a bucket by message number.
+func (r *RocketRepository) GetSpeedBuckets(rocketID string, from, to time.Time, step time.Duration) ([]SpeedBucket, bool) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	if _, exists := r.rockets[rocketID]; !exists {
+		return nil, false
+	}
+
+	buckets := []SpeedBucket{}
+	series, exists := r.speedSeries[rocketID]
+	if !exists {
+		return buckets, true
+	}
+
+	index := make(map[int64]int) // Bucket start (or message number without step) to position in buckets
+	for _, sample := range series.retained() {
+		at := time.Unix(0, sample.messageTime).UTC()
+		if (!from.IsZero() && at.Before(from)) || (!to.IsZero() && !at.Before(to)) {
+			continue
+		}
+
+		start, end, key := at, at, int64(sample.messageNumber)
+		if step > 0 {
+			start = at.Truncate(step)
+			end = start.Add(step)
+			key = start.UnixNano()
+		}
+
+		position, exists := index[key]
+		if !exists {
+			index[key] = len(buckets)
+			buckets = append(buckets, SpeedBucket{
+				Start:              start,
+				End:                end,
+				Min:                sample.speed,
+				Max:                sample.speed,
+				FirstMessageNumber: sample.messageNumber,
+			})
+			position = len(buckets) - 1
+		}
+
+		// Samples are in message sequence order, so the last one seen is the latest
+		bucket := &buckets[position]
+		bucket.Count++
+		bucket.Avg += float64(sample.speed)
+		bucket.Min = min(bucket.Min, sample.speed)
+		bucket.Max = max(bucket.Max, sample.speed)
+		bucket.Last = sample.speed
+		bucket.LastMessageNumber = sample.messageNumber
+	}
+
+	for i := range buckets {
+		buckets[i].Avg /= float64(buckets[i].Count)
+	}
+
+	// Message times may disagree with message numbers, order buckets by time and then sequence
+	sort.SliceStable(buckets, func(i, j int) bool {
+		if !buckets[i].Start.Equal(buckets[j].Start) {
+			return buckets[i].Start.Before(buckets[j].Start)
+		}
+		return buckets[i].FirstMessageNumber < buckets[j].FirstMessageNumber
+	})
+
+	return buckets, true
+}
@@ -0,0 +1,162 @@
+package storage
+
+import (
+	"lunar-backend-challenge/internal/filtering"
+	"lunar-backend-challenge/internal/models"
+)
+
+// statsKey groups rockets that contribute to the same statistics
+type statsKey struct {
+	rocketType      string
+	mission         string
+	status          string
+	explosionReason string // Only set for exploded rockets
+	silent          bool
+}
+
+// statsCell aggregates the speeds of the rockets sharing a key
+type statsCell struct {
+	count    int
+	speedSum int64
+	speeds   map[int]int // Number of rockets per speed, so the maximum survives removals
+	maxSpeed int         // Highest speed in speeds, recomputed only when the last rocket at it leaves
+}
+
+// statsContribution is what a rocket currently adds to the aggregates
+type statsContribution struct {
+	key   statsKey
+	speed int
+}
+
+// fleetStats keeps fleet statistics up to date as rockets change, so reading them does
+// not need a scan over all rockets
+type fleetStats struct {
+	cells         map[statsKey]*statsCell
+	contributions map[string]statsContribution
+}
+
+func newFleetStats() *fleetStats {
+	return &fleetStats{
+		cells:         make(map[statsKey]*statsCell),
+		contributions: make(map[string]statsContribution),
+	}
+}
+
+// update replaces the contribution of a rocket with one reflecting its current state
+func (s *fleetStats) update(rocket *models.RocketState) {
+	key := statsKey{rocketType: rocket.Type, mission: rocket.Mission, status: rocket.Status, silent: rocket.Silent}
+	if rocket.Status == models.RocketStatusExploded {
+		key.explosionReason = rocket.Reason
+	}
+	contribution := statsContribution{key: key, speed: rocket.Speed}
+
+	previous, exists := s.contributions[rocket.ID]
+	if exists {
+		if previous == contribution {
+			return
+		}
+		s.remove(previous)
+	}
+	s.add(contribution)
+	s.contributions[rocket.ID] = contribution
+}
+
+func (s *fleetStats) add(contribution statsContribution) {
+	cell, exists := s.cells[contribution.key]
+	if !exists {
+		cell = &statsCell{speeds: make(map[int]int)}
+		s.cells[contribution.key] = cell
+	}
+	cell.count++
+	cell.speedSum += int64(contribution.speed)
+	cell.speeds[contribution.speed]++
+	cell.maxSpeed = max(cell.maxSpeed, contribution.speed)
+}
+
+func (s *fleetStats) remove(contribution statsContribution) {
+	cell := s.cells[contribution.key]
+	cell.count--
+	cell.speedSum -= int64(contribution.speed)
+	if cell.speeds[contribution.speed]--; cell.speeds[contribution.speed] == 0 {
+		delete(cell.speeds, contribution.speed)
+		if contribution.speed == cell.maxSpeed {
+			cell.maxSpeed = 0
+			for speed := range cell.speeds {
+				cell.maxSpeed = max(cell.maxSpeed, speed) // Speeds are never negative
+			}
+		}
+	}
+	if cell.count == 0 {
+		delete(s.cells, contribution.key)
+	}
+}
+
+// groupAccumulator merges cells into the statistics of a type or mission
+type groupAccumulator struct {
+	count    int
+	speedSum int64
+	maxSpeed int
+}
+
+func (g *groupAccumulator) merge(cell *statsCell) {
+	g.maxSpeed = max(g.maxSpeed, cell.maxSpeed)
+	g.count += cell.count
+	g.speedSum += cell.speedSum
+}
+
+func (g *groupAccumulator) stats() models.GroupStats {
+	return models.GroupStats{
+		Count:        g.count,
+		AverageSpeed: float64(g.speedSum) / float64(g.count),
+		MaxSpeed:     g.maxSpeed,
+	}
+}
+
+// GetStats returns fleet statistics for the rockets matching the filter
+func (r *RocketRepository) GetStats(filter filtering.Filter) models.FleetStats {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	stats := models.FleetStats{
+		ByType:             make(map[string]models.GroupStats),
+		ByMission:          make(map[string]models.GroupStats),
+		ExplosionsByReason: make(map[string]int),
+	}
+	byType := make(map[string]*groupAccumulator)
+	byMission := make(map[string]*groupAccumulator)
+
+	for key, cell := range r.stats.cells {
+		if !filter.Matches(key.rocketType, key.mission, key.status, key.silent) {
+			continue
+		}
+
+		stats.TotalRockets += cell.count
+		if key.silent {
+			stats.Silent += cell.count
+		}
+		switch key.status {
+		case models.RocketStatusActive, models.RocketStatusRelaunched:
+			stats.Active += cell.count
+		case models.RocketStatusExploded:
+			stats.Exploded += cell.count
+			stats.ExplosionsByReason[key.explosionReason] += cell.count
+		}
+
+		if byType[key.rocketType] == nil {
+			byType[key.rocketType] = &groupAccumulator{}
+		}
+		byType[key.rocketType].merge(cell)
+		if byMission[key.mission] == nil {
+			byMission[key.mission] = &groupAccumulator{}
+		}
+		byMission[key.mission].merge(cell)
+	}
+
+	for rocketType, group := range byType {
+		stats.ByType[rocketType] = group.stats()
+	}
+	for mission, group := range byMission {
+		stats.ByMission[mission] = group.stats()
+	}
+	return stats
+}
@@ -0,0 +1,414 @@
+package webhooks
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"lunar-backend-challenge/internal/metrics"
+	"lunar-backend-challenge/internal/storage"
+)
+
+// Options tune delivery of webhook events
+type Options struct {
+	MaxAttempts    int           // Attempts per event, including the first
+	InitialBackoff time.Duration // Wait before the first retry, doubled for every further retry
+	MaxBackoff     time.Duration // Upper bound of the wait between retries
+	DisableAfter   int           // Consecutive failed events after which a subscription is disabled
+	Timeout        time.Duration // Timeout of a single attempt
+	QueueSize      int           // Events waiting per subscription before new ones are dropped
+	LogSize        int           // Delivery attempts kept per subscription
+	Client         *http.Client  // Defaults to a client with Timeout
+	Now            func() time.Time
+}
+
+// DefaultOptions returns the production delivery options
+func DefaultOptions() Options {
+	return Options{
+		MaxAttempts:    5,
+		InitialBackoff: time.Second,
+		MaxBackoff:     time.Minute,
+		DisableAfter:   3,
+		Timeout:        10 * time.Second,
+		QueueSize:      1000,
+		LogSize:        100,
+		Now:            time.Now,
+	}
+}
+
+// Delivery records one attempt to deliver an event
+type Delivery struct {
+	EventID    string    `json:"eventId" example:"42"`
+	Attempt    int       `json:"attempt" example:"1"`
+	At         time.Time `json:"at" example:"2024-03-14T19:39:05.86337+01:00"`
+	StatusCode int       `json:"statusCode,omitempty" example:"200"`
+	Error      string    `json:"error,omitempty" example:""`
+	DurationMs int64     `json:"durationMs" example:"12"`
+	Success    bool      `json:"success" example:"true"`
+}
+
+// subscriber is a subscription with its queue and delivery log, delivered by its own goroutine
+// so a slow or failing receiver only delays its own events
+type subscriber struct {
+	subscription Subscription
+	queue        chan Event
+	quit         chan struct{}
+	deliveries   []Delivery // Oldest first, at most LogSize
+}
+
+// Dispatcher delivers rocket state changes to webhook subscriptions
+type Dispatcher struct {
+	options     Options
+	subscribers map[string]*subscriber
+	nextID      int
+	nextEventID int
+	succeeded   int
+	failed      int
+	dropped     int
+	mutex       sync.Mutex
+}
+
+// NewDispatcher creates a dispatcher without subscriptions. Zero options take their DefaultOptions value.
+func NewDispatcher(options Options) *Dispatcher {
+	defaults := DefaultOptions()
+	if options.MaxAttempts <= 0 {
+		options.MaxAttempts = defaults.MaxAttempts
+	}
+	if options.InitialBackoff <= 0 {
+		options.InitialBackoff = defaults.InitialBackoff
+	}
+	if options.MaxBackoff <= 0 {
+		options.MaxBackoff = defaults.MaxBackoff
+	}
+	if options.DisableAfter <= 0 {
+		options.DisableAfter = defaults.DisableAfter
+	}
+	if options.Timeout <= 0 {
+		options.Timeout = defaults.Timeout
+	}
+	if options.QueueSize <= 0 {
+		options.QueueSize = defaults.QueueSize
+	}
+	if options.LogSize <= 0 {
+		options.LogSize = defaults.LogSize
+	}
+	if options.Now == nil {
+		options.Now = defaults.Now
+	}
+	if options.Client == nil {
+		options.Client = &http.Client{Timeout: options.Timeout}
+	}
+	return &Dispatcher{
+		options:     options,
+		subscribers: make(map[string]*subscriber),
+	}
+}
+
+// Subscribe validates and adds a subscription, generating a secret if it has none.
+// The returned subscription is the only copy that includes the secret.
+func (d *Dispatcher) Subscribe(subscription Subscription) (Subscription, error) {
+	if err := subscription.Validate(); err != nil {
+		return Subscription{}, err
+	}
+	if subscription.Secret == "" {
+		secret, err := newSecret()
+		if err != nil {
+			return Subscription{}, err
+		}
+		subscription.Secret = secret
+	}
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.nextID++
+	subscription.ID = strconv.Itoa(d.nextID)
+	subscription.Enabled = true
+	subscription.DisabledReason = ""
+	subscription.DisabledAt = nil
+	subscription.ConsecutiveFailures = 0
+	subscription.CreatedAt = d.options.Now()
+
+	s := &subscriber{
+		subscription: subscription,
+		queue:        make(chan Event, d.options.QueueSize),
+		quit:         make(chan struct{}),
+	}
+	d.subscribers[subscription.ID] = s
+	go d.run(s)
+
+	return subscription, nil
+}
+
+// Unsubscribe removes a subscription and stops its deliveries, reporting whether it existed
+func (d *Dispatcher) Unsubscribe(id string) bool {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	s, exists := d.subscribers[id]
+	if !exists {
+		return false
+	}
+	delete(d.subscribers, id)
+	close(s.quit)
+	return true
+}
+
+// Close stops every subscription's deliveries
+func (d *Dispatcher) Close() {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	for id, s := range d.subscribers {
+		delete(d.subscribers, id)
+		close(s.quit)
+	}
+}
+
+// Get returns a subscription by ID, without its secret
+func (d *Dispatcher) Get(id string) (Subscription, bool) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	s, exists := d.subscribers[id]
+	if !exists {
+		return Subscription{}, false
+	}
+	return redacted(s.subscription), true
+}
+
+// List returns every subscription ordered by ID, without secrets
+func (d *Dispatcher) List() []Subscription {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	subscriptions := make([]Subscription, 0, len(d.subscribers))
+	for _, s := range d.subscribers {
+		subscriptions = append(subscriptions, redacted(s.subscription))
+	}
+	sort.Slice(subscriptions, func(i, j int) bool {
+		left, _ := strconv.Atoi(subscriptions[i].ID)
+		right, _ := strconv.Atoi(subscriptions[j].ID)
+		return left < right
+	})
+	return subscriptions
+}
+
+// Deliveries returns the delivery log of a subscription, most recent first
+func (d *Dispatcher) Deliveries(id string) ([]Delivery, bool) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	s, exists := d.subscribers[id]
+	if !exists {
+		return nil, false
+	}
+	deliveries := make([]Delivery, len(s.deliveries))
+	for i, delivery := range s.deliveries {
+		deliveries[len(s.deliveries)-1-i] = delivery
+	}
+	return deliveries, true
+}
+
+// Enable re-enables a disabled subscription and resets its failure count
+func (d *Dispatcher) Enable(id string) (Subscription, bool) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	s, exists := d.subscribers[id]
+	if !exists {
+		return Subscription{}, false
+	}
+	s.subscription.Enabled = true
+	s.subscription.DisabledReason = ""
+	s.subscription.DisabledAt = nil
+	s.subscription.ConsecutiveFailures = 0
+	return redacted(s.subscription), true
+}
+
+// Observe queues a state change for every enabled subscription it matches. It never blocks:
+// events for a subscription whose queue is full are dropped. It is meant to be subscribed
+// to the repository with RocketRepository.Subscribe.
+func (d *Dispatcher) Observe(change storage.StateChange) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	var event *Event
+	for _, s := range d.subscribers {
+		if !s.subscription.Enabled || !s.subscription.Matches(change) {
+			continue
+		}
+		if event == nil {
+			d.nextEventID++
+			event = &Event{
+				ID:         strconv.Itoa(d.nextEventID),
+				Type:       eventTypes[change.Kind],
+				RocketID:   change.RocketID,
+				OccurredAt: change.At,
+				Before:     change.Before,
+				After:      change.After,
+			}
+			if change.Message != nil {
+				event.MessageType = change.Message.GetMessageType()
+				event.MessageNumber = change.Message.GetMessageNumber()
+			}
+		}
+
+		select {
+		case s.queue <- *event:
+		default:
+			d.dropped++
+			d.record(s, Delivery{EventID: event.ID, At: d.options.Now(), Error: "queue full, event dropped"})
+		}
+	}
+}
+
+// Collect returns webhook metrics, see metrics.Collector
+func (d *Dispatcher) Collect() []metrics.Sample {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	enabled := 0
+	for _, s := range d.subscribers {
+		if s.subscription.Enabled {
+			enabled++
+		}
+	}
+
+	return []metrics.Sample{
+		{Name: "rocket_webhook_subscriptions", Help: "Webhook subscriptions by state", Type: metrics.TypeGauge, Labels: map[string]string{"state": "enabled"}, Value: float64(enabled)},
+		{Name: "rocket_webhook_subscriptions", Help: "Webhook subscriptions by state", Type: metrics.TypeGauge, Labels: map[string]string{"state": "disabled"}, Value: float64(len(d.subscribers) - enabled)},
+		{Name: "rocket_webhook_events_total", Help: "Webhook events by delivery result", Type: metrics.TypeCounter, Labels: map[string]string{"result": "delivered"}, Value: float64(d.succeeded)},
+		{Name: "rocket_webhook_events_total", Help: "Webhook events by delivery result", Type: metrics.TypeCounter, Labels: map[string]string{"result": "failed"}, Value: float64(d.failed)},
+		{Name: "rocket_webhook_events_total", Help: "Webhook events by delivery result", Type: metrics.TypeCounter, Labels: map[string]string{"result": "dropped"}, Value: float64(d.dropped)},
+	}
+}
+
+// run delivers the queued events of a subscriber until it is unsubscribed
+func (d *Dispatcher) run(s *subscriber) {
+	for {
+		select {
+		case <-s.quit:
+			return
+		case event := <-s.queue:
+			d.deliver(s, event)
+		}
+	}
+}
+
+// deliver sends an event, retrying with exponential backoff, and disables the
+// subscription after too many consecutive failed events
+func (d *Dispatcher) deliver(s *subscriber, event Event) {
+	body, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("Failed to encode webhook event %s: %v", event.ID, err)
+		return
+	}
+
+	backoff := d.options.InitialBackoff
+	for attempt := 1; attempt <= d.options.MaxAttempts; attempt++ {
+		d.mutex.Lock()
+		subscription := s.subscription
+		d.mutex.Unlock()
+		if !subscription.Enabled {
+			return // Disabled while the event was queued or between retries
+		}
+
+		delivery := d.attempt(subscription, event, body, attempt)
+
+		d.mutex.Lock()
+		d.record(s, delivery)
+		if delivery.Success {
+			s.subscription.ConsecutiveFailures = 0
+			d.succeeded++
+			d.mutex.Unlock()
+			return
+		}
+		d.mutex.Unlock()
+
+		if attempt == d.options.MaxAttempts {
+			break
+		}
+		select {
+		case <-s.quit:
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(2*backoff, d.options.MaxBackoff)
+	}
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.failed++
+	s.subscription.ConsecutiveFailures++
+	if s.subscription.ConsecutiveFailures >= d.options.DisableAfter && s.subscription.Enabled {
+		disabledAt := d.options.Now()
+		s.subscription.Enabled = false
+		s.subscription.DisabledAt = &disabledAt
+		s.subscription.DisabledReason = fmt.Sprintf("%d consecutive events failed after %d attempts each", s.subscription.ConsecutiveFailures, d.options.MaxAttempts)
+		log.Printf("Disabled webhook %s (%s): %s", s.subscription.ID, s.subscription.URL, s.subscription.DisabledReason)
+	}
+}
+
+// attempt sends a signed event once
+func (d *Dispatcher) attempt(subscription Subscription, event Event, body []byte, attempt int) Delivery {
+	delivery := Delivery{EventID: event.ID, Attempt: attempt, At: d.options.Now()}
+
+	start := time.Now()
+	statusCode, err := d.send(subscription, event, body)
+	delivery.DurationMs = time.Since(start).Milliseconds()
+	delivery.StatusCode = statusCode
+
+	switch {
+	case err != nil:
+		delivery.Error = err.Error()
+	case statusCode < 200 || statusCode >= 300:
+		delivery.Error = "unexpected status " + strconv.Itoa(statusCode)
+	default:
+		delivery.Success = true
+	}
+	return delivery
+}
+
+// send posts the event body to the subscription URL and returns the response status
+func (d *Dispatcher) send(subscription Subscription, event Event, body []byte) (int, error) {
+	req, err := http.NewRequest(http.MethodPost, subscription.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderSignature, Sign(subscription.Secret, body))
+	req.Header.Set(HeaderEvent, event.Type)
+	req.Header.Set(HeaderDelivery, event.ID)
+
+	resp, err := d.options.Client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	return resp.StatusCode, nil
+}
+
+// record appends to the delivery log of a subscriber. Must hold the lock.
+func (d *Dispatcher) record(s *subscriber, delivery Delivery) {
+	s.deliveries = append(s.deliveries, delivery)
+	if len(s.deliveries) > d.options.LogSize {
+		s.deliveries = s.deliveries[len(s.deliveries)-d.options.LogSize:]
+	}
+}
+
+// redacted returns a copy of a subscription without its secret
+func redacted(subscription Subscription) Subscription {
+	subscription.Secret = ""
+	return subscription
+}
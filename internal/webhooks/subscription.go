@@ -0,0 +1,123 @@
+package webhooks
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"time"
+
+	"lunar-backend-challenge/internal/models"
+	"lunar-backend-challenge/internal/registry"
+	"lunar-backend-challenge/internal/storage"
+)
+
+// Request headers of a delivery
+const (
+	HeaderSignature = "X-Webhook-Signature" // "sha256=" followed by the hex HMAC-SHA256 of the body
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderDelivery  = "X-Webhook-Delivery" // Event ID, the same for every attempt
+)
+
+// Event types
+const (
+	EventStateChanged = "rocket.state_changed" // A message was applied
+	EventSilent       = "rocket.silent"        // A rocket in flight stopped sending messages
+	EventRecovered    = "rocket.recovered"     // A silent rocket sent a message again
+	EventConflict     = "rocket.conflict"      // A message number was sent again with a different payload, alert conflict policy only
+)
+
+// eventTypes maps repository state change kinds to event types
+var eventTypes = map[string]string{
+	storage.ChangeApplied:   EventStateChanged,
+	storage.ChangeSilent:    EventSilent,
+	storage.ChangeRecovered: EventRecovered,
+	storage.ChangeConflict:  EventConflict,
+}
+
+// Subscription is a webhook receiving rocket state changes that pass its filters.
+// With a message type filter it only receives applied and conflicting messages, not silence events.
+type Subscription struct {
+	ID                  string     `json:"id" example:"1"`
+	URL                 string     `json:"url" example:"https://example.com/hooks/rockets"`
+	Secret              string     `json:"secret,omitempty" example:"s3cr3t"` // Signing key, only returned when the subscription is created
+	MessageTypes        []string   `json:"messageTypes,omitempty"`            // Only these message types, all if empty
+	RocketID            string     `json:"rocketId,omitempty" example:""`     // Only this rocket, all if empty
+	Mission             string     `json:"mission,omitempty" example:""`      // Only rockets on this mission (case-insensitive), all if empty
+	Enabled             bool       `json:"enabled" example:"true"`
+	DisabledReason      string     `json:"disabledReason,omitempty" example:""`
+	ConsecutiveFailures int        `json:"consecutiveFailures" example:"0"` // Deliveries that failed after every retry, since the last success
+	CreatedAt           time.Time  `json:"createdAt" example:"2024-03-14T19:39:05.86337+01:00"`
+	DisabledAt          *time.Time `json:"disabledAt,omitempty" example:"2024-03-14T19:45:12.12345+01:00"`
+}
+
+// Validate checks the URL and filters of a new subscription
+func (s *Subscription) Validate() error {
+	target, err := url.Parse(s.URL)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		return fmt.Errorf("url must be an absolute http or https URL")
+	}
+	for _, messageType := range s.MessageTypes {
+		if _, exists := registry.Lookup(messageType); !exists {
+			return fmt.Errorf("unknown message type %q", messageType)
+		}
+	}
+	return nil
+}
+
+// Matches reports whether a state change passes the subscription filters
+func (s *Subscription) Matches(change storage.StateChange) bool {
+	if s.RocketID != "" && s.RocketID != change.RocketID {
+		return false
+	}
+	if s.Mission != "" && models.MissionKey(s.Mission) != models.MissionKey(change.After.Mission) {
+		return false
+	}
+	if len(s.MessageTypes) == 0 {
+		return true
+	}
+	if change.Kind != storage.ChangeApplied && change.Kind != storage.ChangeConflict {
+		return false // Silence and recovery are not caused by a message type
+	}
+	for _, messageType := range s.MessageTypes {
+		if messageType == change.Message.GetMessageType() {
+			return true
+		}
+	}
+	return false
+}
+
+// Event is the JSON payload delivered to a webhook
+type Event struct {
+	ID            string              `json:"id" example:"42"`
+	Type          string              `json:"type" example:"rocket.state_changed"` // rocket.state_changed, rocket.silent, rocket.recovered or rocket.conflict
+	RocketID      string              `json:"rocketId" example:"193270a9-c9cf-404a-8f83-838e71d9ae67"`
+	MessageType   string              `json:"messageType,omitempty" example:"RocketSpeedIncreased"` // Absent for rocket.silent
+	MessageNumber int                 `json:"messageNumber,omitempty" example:"3"`
+	OccurredAt    time.Time           `json:"occurredAt" example:"2024-03-14T19:39:05.86337+01:00"`
+	Before        *models.RocketState `json:"before,omitempty"` // Absent for a new rocket
+	After         *models.RocketState `json:"after"`
+}
+
+// Sign returns the signature header value of a body, "sha256=" followed by the hex HMAC-SHA256
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify reports whether a signature header value matches the body, for receivers
+func Verify(secret string, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, body)), []byte(signature))
+}
+
+// newSecret generates a random signing key
+func newSecret() (string, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(key), nil
+}
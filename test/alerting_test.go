@@ -0,0 +1,263 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"lunar-backend-challenge/internal/alerting"
+	"lunar-backend-challenge/internal/api"
+	"lunar-backend-challenge/internal/models"
+	"lunar-backend-challenge/internal/storage"
+)
+
+// createAlertingRepository creates a repository observed by a new alerting engine with the given rules
+func createAlertingRepository(t *testing.T, now func() time.Time, rules ...alerting.Rule) (*storage.RocketRepository, *alerting.Engine) {
+	t.Helper()
+
+	config := storage.DefaultConfig()
+	config.Now = now
+	repo := storage.NewRocketRepositoryWithConfig(config)
+
+	engine := alerting.NewEngine()
+	for _, rule := range rules {
+		if err := engine.AddRule(rule); err != nil {
+			t.Fatalf("Failed to add rule %s: %v", rule.ID, err)
+		}
+	}
+	repo.Subscribe(engine.Observe)
+	return repo, engine
+}
+
+// createSpeedChangeMessage creates a speed change message by the given amount
+func createSpeedChangeMessage(channel string, messageNumber int, by int) *models.RocketMessage {
+	messageType := models.MessageTypeRocketSpeedIncreased
+	if by < 0 {
+		messageType = models.MessageTypeRocketSpeedDecreased
+		by = -by
+	}
+	msg := createTestMessage(channel, messageNumber, messageType)
+	msg.Message.By = by
+	return msg
+}
+
+// Test that speed alerts are deduplicated and only resolve below the hysteresis band
+func TestSpeedAlertHysteresis(t *testing.T) {
+	rule := alerting.Rule{ID: "fast", Kind: alerting.KindSpeedAbove, Threshold: 2000, Hysteresis: 500}
+	repo, engine := createAlertingRepository(t, time.Now, rule)
+	rocketID := "fast-rocket"
+
+	repo.ProcessMessage(createTestMessage(rocketID, 1, models.MessageTypeRocketLaunched)) // 1000
+	if len(engine.Active()) != 0 {
+		t.Fatal("Expected no alert below the threshold")
+	}
+
+	repo.ProcessMessage(createSpeedChangeMessage(rocketID, 2, 1500)) // 2500
+	repo.ProcessMessage(createSpeedChangeMessage(rocketID, 3, 500))  // 3000
+	active := engine.Active()
+	if len(active) != 1 || active[0].RocketID != rocketID || active[0].Occurrences != 2 {
+		t.Fatalf("Expected one firing alert seen twice, got %+v", active)
+	}
+
+	repo.ProcessMessage(createSpeedChangeMessage(rocketID, 4, -1200)) // 1800, within the hysteresis band
+	if len(engine.Active()) != 1 {
+		t.Fatal("Expected alert to keep firing within the hysteresis band")
+	}
+
+	repo.ProcessMessage(createSpeedChangeMessage(rocketID, 5, -300)) // 1500
+	if len(engine.Active()) != 0 {
+		t.Fatal("Expected alert to resolve below the hysteresis band")
+	}
+	resolved := engine.Resolved()
+	if len(resolved) != 1 || resolved[0].State != alerting.StateResolved || resolved[0].ResolvedAt == nil {
+		t.Errorf("Expected one resolved alert, got %+v", resolved)
+	}
+
+	repo.ProcessMessage(createSpeedChangeMessage(rocketID, 6, 1000)) // 2500
+	if active := engine.Active(); len(active) != 1 || active[0].Occurrences != 1 {
+		t.Errorf("Expected a new alert after resolving, got %+v", active)
+	}
+}
+
+// Test explosion and mission change alerts
+func TestExplosionAndMissionAlerts(t *testing.T) {
+	repo, engine := createAlertingRepository(t, time.Now,
+		alerting.Rule{ID: "boom", Kind: alerting.KindExploded},
+		alerting.Rule{ID: "off-mission", Kind: alerting.KindMissionChanged, ExpectedMissions: []string{"Test Mission", "ARTEMIS"}},
+	)
+	rocketID := "alert-rocket"
+
+	repo.ProcessMessage(createTestMessage(rocketID, 1, models.MessageTypeRocketLaunched))
+	repo.ProcessMessage(createTestMessage(rocketID, 2, models.MessageTypeRocketMissionChanged)) // "New Mission"
+	active := engine.Active()
+	if len(active) != 1 || active[0].RuleID != "off-mission" {
+		t.Fatalf("Expected an unexpected mission alert, got %+v", active)
+	}
+
+	repo.ProcessMessage(createMissionChangeMessage(rocketID, 3, "artemis", time.Now()))
+	if active := engine.Active(); len(active) != 0 {
+		t.Fatalf("Expected mission alert to resolve on an expected mission, got %+v", active)
+	}
+
+	repo.ProcessMessage(createTestMessage(rocketID, 4, models.MessageTypeRocketExploded))
+	active = engine.Active()
+	if len(active) != 1 || active[0].RuleID != "boom" || active[0].Message != "rocket exploded: Engine failure" {
+		t.Fatalf("Expected an explosion alert, got %+v", active)
+	}
+
+	repo.ProcessMessage(createTestMessage(rocketID, 5, models.MessageTypeRocketLaunched))
+	if active := engine.Active(); len(active) != 0 {
+		t.Errorf("Expected explosion alert to resolve on relaunch, got %+v", active)
+	}
+}
+
+// Test that silence alerts follow the rockets the repository marks silent, optionally after a delay
+func TestSilentAlert(t *testing.T) {
+	start := time.Date(2024, 3, 14, 19, 39, 0, 0, time.UTC)
+	now := start
+	repo := createSilenceRepository(&now, storage.SilenceByReceiveTime)
+	engine := alerting.NewEngine()
+	for _, rule := range []alerting.Rule{
+		{ID: "quiet", Kind: alerting.KindSilent},
+		{ID: "long-quiet", Kind: alerting.KindSilent, SilenceAfter: alerting.Duration(time.Minute)},
+	} {
+		if err := engine.AddRule(rule); err != nil {
+			t.Fatalf("Failed to add rule %s: %v", rule.ID, err)
+		}
+	}
+	repo.Subscribe(engine.Observe)
+
+	repo.ProcessMessage(createTestMessage("quiet-rocket", 1, models.MessageTypeRocketLaunched))
+	repo.ProcessMessage(createTestMessage("exploded-rocket", 1, models.MessageTypeRocketLaunched))
+	repo.ProcessMessage(createTestMessage("exploded-rocket", 2, models.MessageTypeRocketExploded))
+
+	// Evaluating does not detect silence by itself, only the repository does
+	engine.Evaluate(start.Add(10 * time.Minute))
+	if len(engine.Active()) != 0 {
+		t.Fatal("Expected no silence alert before the repository marks a rocket silent")
+	}
+
+	now = start.Add(2 * time.Minute)
+	silentAt := now
+	silenced := repo.DetectSilence(silentAt)
+	active := engine.Active()
+	if len(active) != 1 || active[0].RuleID != "quiet" || active[0].RocketID != "quiet-rocket" {
+		t.Fatalf("Expected the rocket in flight to be silent right away, got %+v", active)
+	}
+	if len(silenced) != 1 || silenced[0] != active[0].RocketID {
+		t.Errorf("Expected the alert to agree with the silent rockets, got %v", silenced)
+	}
+
+	engine.Evaluate(silentAt.Add(30 * time.Second))
+	if len(engine.Active()) != 1 {
+		t.Fatal("Expected no delayed silence alert before silenceAfter")
+	}
+	engine.Evaluate(silentAt.Add(time.Minute))
+	if active := engine.Active(); len(active) != 2 || active[1].RuleID != "long-quiet" {
+		t.Fatalf("Expected the delayed silence alert after silenceAfter, got %+v", active)
+	}
+
+	now = start.Add(4 * time.Minute)
+	repo.ProcessMessage(createTestMessage("quiet-rocket", 2, models.MessageTypeRocketSpeedIncreased))
+	if len(engine.Active()) != 0 {
+		t.Error("Expected silence alerts to resolve when the rocket recovers")
+	}
+	engine.Evaluate(now.Add(time.Hour))
+	if len(engine.Active()) != 0 {
+		t.Error("Expected no silence alert for a recovered rocket")
+	}
+}
+
+// Test that rules can be loaded from a file and invalid rules are refused
+func TestLoadRules(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rules.json")
+	content := `[{"id": "fast", "kind": "speed_above", "threshold": 10000, "hysteresis": 500},
+		{"id": "quiet", "kind": "silent", "silenceAfter": "5m"}]`
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("Failed to write rules file: %v", err)
+	}
+
+	rules, err := alerting.LoadRules(path)
+	if err != nil {
+		t.Fatalf("Failed to load rules: %v", err)
+	}
+	if len(rules) != 2 || rules[0].Threshold != 10000 || time.Duration(rules[1].SilenceAfter) != 5*time.Minute {
+		t.Errorf("Unexpected rules: %+v", rules)
+	}
+
+	invalid := []alerting.Rule{
+		{Kind: alerting.KindExploded},
+		{ID: "unknown", Kind: "speed_below"},
+		{ID: "no-threshold", Kind: alerting.KindSpeedAbove},
+		{ID: "wide-band", Kind: alerting.KindSpeedAbove, Threshold: 100, Hysteresis: 200},
+		{ID: "negative-silence", Kind: alerting.KindSilent, SilenceAfter: alerting.Duration(-time.Minute)},
+	}
+	for _, rule := range invalid {
+		if err := rule.Validate(); err == nil {
+			t.Errorf("Expected rule %+v to be invalid", rule)
+		}
+	}
+}
+
+// Test the rule management and alert endpoints
+func TestHandleRulesAndAlerts(t *testing.T) {
+	handler := api.NewAPIHandler()
+
+	body, _ := json.Marshal(alerting.Rule{ID: "boom", Kind: alerting.KindExploded})
+	rr := httptest.NewRecorder()
+	handler.HandleCreateRule(rr, httptest.NewRequest(http.MethodPost, "/admin/rules", bytes.NewReader(body)))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status %d creating a rule, got %d", http.StatusOK, rr.Code)
+	}
+
+	rr = httptest.NewRecorder()
+	handler.HandleCreateRule(rr, httptest.NewRequest(http.MethodPost, "/admin/rules", bytes.NewReader(body)))
+	if rr.Code != http.StatusConflict {
+		t.Errorf("Expected status %d for a duplicate rule, got %d", http.StatusConflict, rr.Code)
+	}
+
+	rr = httptest.NewRecorder()
+	handler.HandleCreateRule(rr, httptest.NewRequest(http.MethodPost, "/admin/rules", bytes.NewBufferString(`{"id": "x", "kind": "nope"}`)))
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d for an invalid rule, got %d", http.StatusBadRequest, rr.Code)
+	}
+
+	handler.Repository.ProcessMessage(createTestHTTPMessage("boom-rocket", 1, models.MessageTypeRocketLaunched))
+	handler.Repository.ProcessMessage(createTestHTTPMessage("boom-rocket", 2, models.MessageTypeRocketExploded))
+
+	rr = httptest.NewRecorder()
+	handler.HandleGetAlerts(rr, httptest.NewRequest(http.MethodGet, "/alerts", nil))
+	var alerts []alerting.Alert
+	if err := json.NewDecoder(rr.Body).Decode(&alerts); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(alerts) != 1 || alerts[0].ID != "boom/boom-rocket" {
+		t.Fatalf("Expected one explosion alert, got %+v", alerts)
+	}
+
+	// Deleting the rule resolves its alerts
+	req := httptest.NewRequest(http.MethodDelete, "/admin/rules/boom", nil)
+	req.SetPathValue("id", "boom")
+	rr = httptest.NewRecorder()
+	handler.HandleDeleteRule(rr, req)
+	if rr.Code != http.StatusOK || len(handler.Alerts.Active()) != 0 || len(handler.Alerts.Resolved()) != 1 {
+		t.Errorf("Expected rule deletion to resolve its alert, got status %d", rr.Code)
+	}
+
+	rr = httptest.NewRecorder()
+	handler.HandleDeleteRule(rr, req)
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d deleting a missing rule, got %d", http.StatusNotFound, rr.Code)
+	}
+
+	rr = httptest.NewRecorder()
+	handler.HandleGetAlerts(rr, httptest.NewRequest(http.MethodGet, "/alerts?state=pending", nil))
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d for an invalid state, got %d", http.StatusBadRequest, rr.Code)
+	}
+}
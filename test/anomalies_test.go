@@ -0,0 +1,153 @@
+package test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"lunar-backend-challenge/internal/api"
+	"lunar-backend-challenge/internal/deadletter"
+	"lunar-backend-challenge/internal/models"
+	"lunar-backend-challenge/internal/storage"
+)
+
+// createAnomalyRepository creates a repository with a fixed clock and the given clock skew policy
+func createAnomalyRepository(now time.Time, policy storage.ClockSkewPolicy) *storage.RocketRepository {
+	config := storage.DefaultConfig()
+	config.MaxClockSkew = time.Minute
+	config.MaxMessageGap = 10 * time.Minute
+	config.ClockSkewPolicy = policy
+	config.Now = func() time.Time { return now }
+	return storage.NewRocketRepositoryWithConfig(config)
+}
+
+// Test that the message times of a long sequence are checked near its head, and old retransmissions are not checked again
+func TestMessageTimeAnomaliesLongSequence(t *testing.T) {
+	now := time.Date(2024, 3, 14, 19, 39, 0, 0, time.UTC)
+	repo := createAnomalyRepository(now, storage.ClockSkewFlag)
+	rocketID := "long-anomaly-rocket"
+	start := now.Add(-time.Hour)
+
+	repo.ProcessMessage(createTimedMessage(rocketID, 1, models.MessageTypeRocketLaunched, start))
+	for number := 2; number <= 100; number++ {
+		repo.ProcessMessage(createTimedMessage(rocketID, number, models.MessageTypeRocketSpeedIncreased, start.Add(time.Duration(number)*time.Second)))
+	}
+
+	// A retransmission with a different time is a duplicate, not an anomaly
+	repo.ProcessMessage(createTimedMessage(rocketID, 50, models.MessageTypeRocketSpeedIncreased, start))
+	if anomalies := repo.GetAnomalies(rocketID); len(anomalies) != 0 {
+		t.Errorf("Expected no anomalies, got %+v", anomalies)
+	}
+
+	// The next message is still checked against the last processed one
+	repo.ProcessMessage(createTimedMessage(rocketID, 101, models.MessageTypeRocketSpeedIncreased, start.Add(99*time.Second)))
+	anomalies := repo.GetAnomalies(rocketID)
+	if len(anomalies) != 1 || anomalies[0].Kind != storage.AnomalyTimeReversed || anomalies[0].MessageNumber != 101 {
+		t.Errorf("Expected message 101 to be flagged as reversed, got %+v", anomalies)
+	}
+}
+
+// Test that reversed, distant and future message times are flagged
+func TestMessageTimeAnomalies(t *testing.T) {
+	now := time.Date(2024, 3, 14, 19, 39, 0, 0, time.UTC)
+	repo := createAnomalyRepository(now, storage.ClockSkewFlag)
+	rocketID := "anomaly-rocket"
+	start := now.Add(-time.Hour)
+
+	messages := []*models.RocketMessage{
+		createTimedMessage(rocketID, 1, models.MessageTypeRocketLaunched, start),
+		createTimedMessage(rocketID, 3, models.MessageTypeRocketSpeedIncreased, start.Add(2*time.Second)),
+		// Out of order with message 3, which is only buffered, once it arrives
+		createTimedMessage(rocketID, 2, models.MessageTypeRocketSpeedIncreased, start.Add(5*time.Second)),
+		createTimedMessage(rocketID, 4, models.MessageTypeRocketSpeedIncreased, start.Add(20*time.Minute)),
+		createTimedMessage(rocketID, 5, models.MessageTypeRocketSpeedIncreased, now.Add(2*time.Minute)),
+	}
+	for _, msg := range messages {
+		if !repo.ProcessMessage(msg) {
+			t.Fatalf("Expected message %d to be accepted with the flag policy", msg.GetMessageNumber())
+		}
+	}
+	// Repeats are not checked again
+	repo.ProcessMessage(messages[1])
+
+	anomalies := repo.GetAnomalies(rocketID)
+	expected := []struct {
+		kind          string
+		messageNumber int
+	}{
+		{storage.AnomalyTimeReversed, 2},
+		{storage.AnomalyTimeGap, 4},
+		{storage.AnomalyFutureTime, 5},
+		{storage.AnomalyTimeGap, 5},
+	}
+	if len(anomalies) != len(expected) {
+		t.Fatalf("Expected %d anomalies, got %+v", len(expected), anomalies)
+	}
+	for i, anomaly := range anomalies {
+		if anomaly.Kind != expected[i].kind || anomaly.MessageNumber != expected[i].messageNumber || anomaly.Rejected || anomaly.Detail == "" {
+			t.Errorf("Anomaly %d: expected %s on message %d, got %+v", i, expected[i].kind, expected[i].messageNumber, anomaly)
+		}
+	}
+
+	if rocket, _ := repo.GetRocket(rocketID); rocket.LastProcessedMessageNumber != 5 {
+		t.Errorf("Expected every message to be processed, got last processed %d", rocket.LastProcessedMessageNumber)
+	}
+}
+
+// Test that the reject policy rejects and dead-letters messages beyond the skew tolerance
+func TestHandleMessageClockSkewReject(t *testing.T) {
+	now := time.Now()
+	handler := api.NewAPIHandlerWithRepository(createAnomalyRepository(now, storage.ClockSkewReject))
+	t.Cleanup(handler.Webhooks.Close)
+	rocketID := "skewed-rocket"
+
+	postMessage(t, handler, createTimedMessage(rocketID, 1, models.MessageTypeRocketLaunched, now))
+	if rr := postMessage(t, handler, createTimedMessage(rocketID, 2, models.MessageTypeRocketSpeedIncreased, now.Add(30*time.Second))); rr.Code != http.StatusOK {
+		t.Errorf("Expected a message within the skew tolerance to be accepted, got %d", rr.Code)
+	}
+	rr := postMessage(t, handler, createTimedMessage(rocketID, 3, models.MessageTypeRocketSpeedIncreased, now.Add(time.Hour)))
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("Expected status %d for a skewed message, got %d", http.StatusBadRequest, rr.Code)
+	}
+
+	if rocket, _ := handler.Repository.GetRocket(rocketID); rocket.LastProcessedMessageNumber != 2 {
+		t.Errorf("Expected the skewed message not to be processed, got last processed %d", rocket.LastProcessedMessageNumber)
+	}
+	entries := handler.DeadLetters.List(deadletter.Filter{Reason: deadletter.ReasonClockSkew})
+	if len(entries) != 1 || entries[0].MessageNumber != 3 {
+		t.Errorf("Expected the skewed message to be dead-lettered, got %+v", entries)
+	}
+
+	// A corrected retransmission of the rejected message is checked again and accepted
+	if rr := postMessage(t, handler, createTimedMessage(rocketID, 3, models.MessageTypeRocketSpeedIncreased, now.Add(40*time.Second))); rr.Code != http.StatusOK {
+		t.Errorf("Expected the corrected message to be accepted, got %d", rr.Code)
+	}
+
+	debug := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/debug/rockets/"+rocketID, nil)
+	req.SetPathValue("id", rocketID)
+	handler.HandleDebugRocket(debug, req)
+
+	var debugInfo api.DebugInfo
+	if err := json.NewDecoder(debug.Body).Decode(&debugInfo); err != nil {
+		t.Fatalf("Failed to decode debug info: %v", err)
+	}
+	if debugInfo.AnomalyCount != 1 || debugInfo.Anomalies[0].Kind != storage.AnomalyFutureTime || !debugInfo.Anomalies[0].Rejected {
+		t.Errorf("Expected one rejected future_time anomaly in the debug info, got %+v", debugInfo)
+	}
+
+	metrics := httptest.NewRecorder()
+	handler.HandleMetrics(metrics, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	for _, line := range []string{
+		`rocket_message_anomalies_total{kind="future_time"} 1`,
+		`rocket_message_anomalies_total{kind="time_reversed"} 0`,
+		`rocket_messages_skew_rejected_total 1`,
+	} {
+		if !strings.Contains(metrics.Body.String(), line) {
+			t.Errorf("Expected metrics to contain %q, got:\n%s", line, metrics.Body.String())
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package test
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"testing"
+	"time"
+
+	"lunar-backend-challenge/internal/models"
+	"lunar-backend-challenge/internal/sorting"
+	"lunar-backend-challenge/internal/storage"
+)
+
+// Benchmarks of the repository, run with e.g. go test ./test -run '^$' -bench . -benchmem.
+// Compare runs with benchstat; cmd/loadtest measures the whole service over HTTP.
+
+// benchmarkHistoryLength is the number of messages per rocket in the ingestion benchmarks
+const benchmarkHistoryLength = 100
+
+// benchmarkHistory returns messages 1 to benchmarkHistoryLength of a rocket: its launch followed by speed changes
+func benchmarkHistory(rocketID string, start time.Time) []*models.RocketMessage {
+	history := make([]*models.RocketMessage, benchmarkHistoryLength)
+	for i := range history {
+		messageType := models.MessageTypeRocketSpeedIncreased
+		switch {
+		case i == 0:
+			messageType = models.MessageTypeRocketLaunched
+		case i%3 == 0:
+			messageType = models.MessageTypeRocketSpeedDecreased
+		}
+		history[i] = createTimedMessage(rocketID, i+1, messageType, start.Add(time.Duration(i)*time.Second))
+	}
+	return history
+}
+
+// benchmarkDeliveries returns at least count deliveries of whole rocket histories in the given order:
+// in-order, reversed (every message buffered until the launch), shuffled across rockets, or
+// duplicates (every other message retransmitted after the next one)
+func benchmarkDeliveries(count int, order string) []*models.RocketMessage {
+	start := time.Now().Add(-time.Hour).Truncate(time.Second)
+	random := rand.New(rand.NewPCG(1, 1))
+
+	var deliveries []*models.RocketMessage
+	for rocket := 0; len(deliveries) < count; rocket++ {
+		history := benchmarkHistory(fmt.Sprintf("bench-rocket-%06d", rocket), start)
+		switch order {
+		case "reversed":
+			slices.Reverse(history)
+		case "duplicates":
+			var delivered []*models.RocketMessage
+			for i, msg := range history {
+				delivered = append(delivered, msg)
+				if i > 0 && i%2 == 0 {
+					retransmission := *history[i-1]
+					delivered = append(delivered, &retransmission)
+				}
+			}
+			history = delivered
+		}
+		deliveries = append(deliveries, history...)
+	}
+	if order == "shuffled" {
+		random.Shuffle(len(deliveries), func(i, j int) { deliveries[i], deliveries[j] = deliveries[j], deliveries[i] })
+	}
+	return deliveries
+}
+
+// Benchmark processing a message, per arrival order
+func BenchmarkProcessMessage(b *testing.B) {
+	for _, order := range []string{"in-order", "reversed", "shuffled", "duplicates"} {
+		b.Run(order, func(b *testing.B) {
+			deliveries := benchmarkDeliveries(b.N, order)
+			repository := storage.NewRocketRepository()
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				repository.ProcessMessage(deliveries[i])
+			}
+			b.ReportMetric(float64(b.N)/b.Elapsed().Seconds(), "msgs/s")
+		})
+	}
+}
+
+// benchmarkFleet returns a repository with size launched rockets of different speeds and missions
+func benchmarkFleet(size int) *storage.RocketRepository {
+	repository := storage.NewRocketRepository()
+	start := time.Now().Add(-time.Hour).Truncate(time.Second)
+	for i := range size {
+		msg := createTimedMessage(fmt.Sprintf("bench-rocket-%06d", i), 1, models.MessageTypeRocketLaunched, start)
+		msg.Message.LaunchSpeed = (i * 7919) % 10000
+		msg.Message.Mission = fmt.Sprintf("MISSION-%d", i%50)
+		repository.ProcessMessage(msg)
+	}
+	return repository
+}
+
+// Benchmark listing and sorting the rockets, as GET /rockets does, per fleet size. Each fleet is
+// built by its first sub-benchmark, so filtering with -bench does not build the others.
+func BenchmarkListRockets(b *testing.B) {
+	fleets := make(map[int]*storage.RocketRepository)
+	for _, size := range []int{10, 1000, 100000} {
+		for _, sortBy := range []string{"id", "speed"} {
+			b.Run(fmt.Sprintf("rockets=%d/sortBy=%s", size, sortBy), func(b *testing.B) {
+				repository, built := fleets[size]
+				if !built {
+					repository = benchmarkFleet(size)
+					fleets[size] = repository
+				}
+				b.ReportAllocs()
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					sorting.SortRockets(repository.GetAllRockets(), sortBy, "desc")
+				}
+			})
+		}
+	}
+}
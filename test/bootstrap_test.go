@@ -0,0 +1,204 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"lunar-backend-challenge/internal/models"
+	"lunar-backend-challenge/internal/storage"
+)
+
+// Helper function to create a repository with the timeout bootstrap policy and a fixed clock
+func createBootstrapRepository(now time.Time) *storage.RocketRepository {
+	config := storage.DefaultConfig()
+	config.BootstrapPolicy = storage.BootstrapAfterTimeout
+	config.BootstrapTimeout = 10 * time.Second
+	config.Now = func() time.Time { return now }
+	return storage.NewRocketRepositoryWithConfig(config)
+}
+
+// Test that the wait policy never materializes rockets without a launch
+func TestBootstrap_WaitPolicy(t *testing.T) {
+	repo := storage.NewRocketRepository()
+	rocketID := "bootstrap-wait-rocket"
+
+	repo.ProcessMessage(createTestMessage(rocketID, 5, models.MessageTypeRocketSpeedIncreased))
+
+	if bootstrapped := repo.BootstrapPending(time.Now().Add(time.Hour)); len(bootstrapped) != 0 {
+		t.Errorf("Expected no rockets to be bootstrapped, got %v", bootstrapped)
+	}
+
+	if _, exists := repo.GetRocket(rocketID); exists {
+		t.Error("Expected rocket to not exist until launch message")
+	}
+}
+
+// Test that a launch which does not start the sequence is buffered rather than creating a rocket
+func TestBootstrap_LateLaunchIsBuffered(t *testing.T) {
+	repo := storage.NewRocketRepository()
+	rocketID := "bootstrap-late-launch-rocket"
+
+	result := repo.Process(createTestMessage(rocketID, 3, models.MessageTypeRocketLaunched))
+	if result.Outcome != storage.OutcomeBuffered {
+		t.Errorf("Expected launch message 3 to be buffered, got %s", result.Outcome)
+	}
+
+	if _, exists := repo.GetRocket(rocketID); exists {
+		t.Error("Expected rocket to not exist before its sequence starts")
+	}
+}
+
+// Test that the timeout policy materializes a partial rocket and reconciles it once history arrives
+func TestBootstrap_TimeoutAndReconcile(t *testing.T) {
+	now := time.Now()
+	repo := createBootstrapRepository(now)
+	rocketID := "bootstrap-timeout-rocket"
+
+	// Service started after messages 1-4 were sent
+	repo.ProcessMessage(createTestMessage(rocketID, 5, models.MessageTypeRocketSpeedIncreased))
+	repo.ProcessMessage(createTestMessage(rocketID, 6, models.MessageTypeRocketMissionChanged))
+	repo.ProcessMessage(createTestMessage(rocketID, 8, models.MessageTypeRocketSpeedDecreased))
+
+	if bootstrapped := repo.BootstrapPending(now.Add(9 * time.Second)); len(bootstrapped) != 0 {
+		t.Errorf("Expected no rockets to be bootstrapped before the timeout, got %v", bootstrapped)
+	}
+
+	bootstrapped := repo.BootstrapPending(now.Add(10 * time.Second))
+	if len(bootstrapped) != 1 || bootstrapped[0] != rocketID {
+		t.Fatalf("Expected %s to be bootstrapped, got %v", rocketID, bootstrapped)
+	}
+
+	rocket, exists := repo.GetRocket(rocketID)
+	if !exists {
+		t.Fatal("Expected partial rocket to exist")
+	}
+	if !rocket.Partial || rocket.BootstrappedFrom != 5 {
+		t.Errorf("Expected partial rocket bootstrapped from 5, got partial=%v from=%d", rocket.Partial, rocket.BootstrappedFrom)
+	}
+	if rocket.Type != models.UnknownValue {
+		t.Errorf("Expected unknown type, got %s", rocket.Type)
+	}
+	if rocket.Mission != "New Mission" || rocket.Speed != 500 {
+		t.Errorf("Expected mission New Mission and speed 500, got %s and %d", rocket.Mission, rocket.Speed)
+	}
+	if rocket.LastProcessedMessageNumber != 6 {
+		t.Errorf("Expected last processed message number 6, got %d", rocket.LastProcessedMessageNumber)
+	}
+
+	summaries := repo.GetAllRockets()
+	if len(summaries) != 1 || !summaries[0].Partial {
+		t.Errorf("Expected summary to be flagged partial, got %+v", summaries)
+	}
+
+	// The missing history arrives, out of order
+	history := []*models.RocketMessage{
+		createTestMessage(rocketID, 3, models.MessageTypeRocketSpeedDecreased),
+		createTestMessage(rocketID, 1, models.MessageTypeRocketLaunched),
+		createTestMessage(rocketID, 4, models.MessageTypeRocketSpeedIncreased),
+	}
+	for _, msg := range history {
+		if result := repo.Process(msg); result.Outcome != storage.OutcomeBuffered {
+			t.Errorf("Expected history message %d to be buffered, got %s", msg.GetMessageNumber(), result.Outcome)
+		}
+	}
+
+	if _, pending := repo.GetDebugInfo(rocketID); len(pending) != 4 {
+		t.Errorf("Expected 4 waiting messages, got %v", pending)
+	}
+
+	result := repo.Process(createTestMessage(rocketID, 2, models.MessageTypeRocketSpeedIncreased))
+	if result.Outcome != storage.OutcomeApplied {
+		t.Errorf("Expected final history message to reconcile the rocket, got %s", result.Outcome)
+	}
+
+	rocket, _ = repo.GetRocket(rocketID)
+	if rocket.Partial {
+		t.Error("Expected rocket to no longer be partial")
+	}
+	if rocket.Type != "Falcon Heavy" || rocket.Mission != "New Mission" {
+		t.Errorf("Expected reconciled type and mission, got %s and %s", rocket.Type, rocket.Mission)
+	}
+
+	// 1000 +500 -300 +500 +500
+	if rocket.Speed != 2200 {
+		t.Errorf("Expected reconciled speed 2200, got %d", rocket.Speed)
+	}
+	if rocket.LastProcessedMessageNumber != 6 {
+		t.Errorf("Expected last processed message number 6, got %d", rocket.LastProcessedMessageNumber)
+	}
+
+	processedCount, pending := repo.GetDebugInfo(rocketID)
+	if processedCount != 6 || len(pending) != 1 || pending[0] != 8 {
+		t.Errorf("Expected 6 processed and [8] pending, got %d and %v", processedCount, pending)
+	}
+
+	// The gap closes and processing continues normally
+	repo.ProcessMessage(createTestMessage(rocketID, 7, models.MessageTypeRocketSpeedIncreased))
+	rocket, _ = repo.GetRocket(rocketID)
+	if rocket.LastProcessedMessageNumber != 8 || rocket.Speed != 2400 {
+		t.Errorf("Expected message 8 and speed 2400, got %d and %d", rocket.LastProcessedMessageNumber, rocket.Speed)
+	}
+}
+
+// Test that a buffered relaunch provides the type of a partial rocket
+func TestBootstrap_BufferedLaunch(t *testing.T) {
+	now := time.Now()
+	repo := createBootstrapRepository(now)
+	rocketID := "bootstrap-launch-rocket"
+
+	repo.ProcessMessage(createTestMessage(rocketID, 3, models.MessageTypeRocketLaunched))
+	repo.ProcessMessage(createTestMessage(rocketID, 4, models.MessageTypeRocketSpeedIncreased))
+	repo.BootstrapPending(now.Add(time.Minute))
+
+	rocket, exists := repo.GetRocket(rocketID)
+	if !exists {
+		t.Fatal("Expected partial rocket to exist")
+	}
+	if !rocket.Partial || rocket.Type != "Falcon Heavy" || rocket.Speed != 1500 {
+		t.Errorf("Expected partial Falcon Heavy at speed 1500, got partial=%v type=%s speed=%d", rocket.Partial, rocket.Type, rocket.Speed)
+	}
+}
+
+// Test that a partial rocket whose history cannot be replayed is kept, so its sequence does not move back
+func TestBootstrap_ReconcileKeepsPartialOnRejectedMessage(t *testing.T) {
+	now := time.Date(2024, 3, 14, 19, 39, 0, 0, time.UTC)
+	repo := createBootstrapRepository(now)
+	rocketID := "bootstrap-rejected-rocket"
+
+	repo.ProcessMessage(createTimedMessage(rocketID, 3, models.MessageTypeRocketSpeedIncreased, now))
+	repo.ProcessMessage(createTimedMessage(rocketID, 4, models.MessageTypeRocketSpeedIncreased, now))
+	if bootstrapped := repo.BootstrapPending(now.Add(10 * time.Second)); len(bootstrapped) != 1 {
+		t.Fatalf("Expected the rocket to be bootstrapped, got %v", bootstrapped)
+	}
+
+	// Message 2 passes the buffer but its reducer refuses it when the history is replayed
+	repo.ProcessMessage(createTimedMessage(rocketID, 1, models.MessageTypeRocketLaunched, now))
+	invalid := createTimedMessage(rocketID, 2, models.MessageTypeRocketSpeedIncreased, now)
+	invalid.Message.By = 0
+	if result := repo.Process(invalid); result.Outcome != storage.OutcomeRejected {
+		t.Errorf("Expected the message refused by the replay to be rejected, got %+v", result)
+	}
+
+	// The partial rocket is kept rather than moving its sequence back to message 1
+	rocket, _ := repo.GetRocket(rocketID)
+	if !rocket.Partial || rocket.LastProcessedMessageNumber != 4 || rocket.Speed != 1000 {
+		t.Errorf("Expected the partial rocket to be kept, got partial=%v last=%d speed=%d", rocket.Partial, rocket.LastProcessedMessageNumber, rocket.Speed)
+	}
+	if processed, pending := repo.GetDebugInfo(rocketID); processed != 2 || len(pending) != 1 {
+		t.Errorf("Expected 2 processed and 1 backfilled message, got %d and %v", processed, pending)
+	}
+	anomalies := repo.GetAnomalies(rocketID)
+	if len(anomalies) == 0 || anomalies[len(anomalies)-1].Kind != storage.AnomalyReplayRejected || anomalies[len(anomalies)-1].MessageNumber != 2 {
+		t.Errorf("Expected the rejected message to be reported, got %+v", anomalies)
+	}
+
+	// Once message 2 is sent again, the rocket is reconciled with its complete history
+	repo.ProcessMessage(createTimedMessage(rocketID, 2, models.MessageTypeRocketSpeedIncreased, now))
+	rocket, _ = repo.GetRocket(rocketID)
+	if rocket.Partial || rocket.LastProcessedMessageNumber != 4 || rocket.Speed != 2500 {
+		t.Errorf("Expected a reconciled rocket at message 4 and speed 2500, got partial=%v last=%d speed=%d", rocket.Partial, rocket.LastProcessedMessageNumber, rocket.Speed)
+	}
+	if lag, _ := repo.GetLag(rocketID); lag.Messages != 4 {
+		t.Errorf("Expected the lag of the 4 messages in the rebuilt history, got %d", lag.Messages)
+	}
+}
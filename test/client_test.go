@@ -0,0 +1,157 @@
+package test
+
+import (
+	"context"
+	stderrors "errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	"lunar-backend-challenge/internal/api"
+	"lunar-backend-challenge/internal/client"
+	"lunar-backend-challenge/internal/errors"
+	"lunar-backend-challenge/internal/graphapi"
+	"lunar-backend-challenge/internal/models"
+)
+
+// createClientServer serves the API routes used by the client and returns a client for them
+func createClientServer(t *testing.T) (*client.Client, *api.ApiHandler, *string) {
+	handler := api.NewAPIHandler()
+	t.Cleanup(handler.Webhooks.Close)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /messages", handler.HandleMessage)
+	mux.HandleFunc("GET /rockets", handler.HandleGetRockets)
+	mux.HandleFunc("GET /rockets/{id}", handler.HandleGetRocket)
+	mux.HandleFunc("GET /debug/rockets/{id}", handler.HandleDebugRocket)
+	mux.Handle("/graphql", graphapi.NewHandler(handler.Repository, graphapi.DefaultOptions()))
+
+	var authorization string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		mux.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+	return client.New(server.URL+"/", "secret"), handler, &authorization
+}
+
+// Test sending messages, then reading them back as rockets and debug info
+func TestClientRockets(t *testing.T) {
+	c, handler, authorization := createClientServer(t)
+	ctx := context.Background()
+
+	for i, speed := range []int{300, 100} {
+		msg := createTestMessage(string(rune('a'+i))+"-rocket", 1, models.MessageTypeRocketLaunched)
+		msg.Message.LaunchSpeed = speed
+		response, err := c.SendMessage(ctx, msg)
+		if err != nil || response.Outcome != "applied" {
+			t.Fatalf("Expected the message to be applied, got %+v (%v)", response, err)
+		}
+	}
+	if *authorization != "Bearer secret" {
+		t.Errorf("Expected the token as a bearer token, got %q", *authorization)
+	}
+
+	rockets, err := c.ListRockets(ctx, url.Values{"sortBy": {"speed"}})
+	if err != nil || len(rockets) != 2 || rockets[0].ID != "b-rocket" {
+		t.Errorf("Expected rockets sorted by speed, got %+v (%v)", rockets, err)
+	}
+
+	rocket, err := c.GetRocket(ctx, "a-rocket")
+	if err != nil || rocket.Speed != 300 {
+		t.Errorf("Expected a-rocket at speed 300, got %+v (%v)", rocket, err)
+	}
+
+	_, err = c.GetRocket(ctx, "unknown-rocket")
+	var apiErr errors.APIError
+	if !stderrors.As(err, &apiErr) || apiErr.Code != http.StatusNotFound || apiErr.Message != "Rocket not found" {
+		t.Errorf("Expected a not found API error, got %v", err)
+	}
+
+	handler.Repository.ProcessMessage(createTestMessage("a-rocket", 5, models.MessageTypeRocketSpeedIncreased))
+	handler.Repository.ProcessMessage(createTestMessage("a-rocket", 3, models.MessageTypeRocketSpeedIncreased))
+	debugInfo, err := c.GetDebugInfo(ctx, "a-rocket")
+	if err != nil || debugInfo.PendingMessageCount != 2 {
+		t.Fatalf("Expected 2 pending messages, got %+v (%v)", debugInfo, err)
+	}
+	expected := []client.Gap{{From: 2, To: 2}, {From: 4, To: 4}}
+	if gaps := client.Gaps(debugInfo.LastProcessedMessage, debugInfo.PendingMessageNumbers); !reflect.DeepEqual(gaps, expected) {
+		t.Errorf("Expected gaps %v, got %v", expected, gaps)
+	}
+}
+
+// Test the missing message ranges before buffered messages
+func TestClientGaps(t *testing.T) {
+	tests := []struct {
+		lastProcessed int
+		pending       []int
+		expected      []client.Gap
+	}{
+		{3, nil, []client.Gap{}},
+		{3, []int{9, 5, 6}, []client.Gap{{From: 4, To: 4}, {From: 7, To: 8}}},
+		{0, []int{4}, []client.Gap{{From: 1, To: 3}}},
+		{10, []int{2, 12}, []client.Gap{{From: 11, To: 11}}}, // Backfilled history below the last processed message
+	}
+	for _, tt := range tests {
+		if gaps := client.Gaps(tt.lastProcessed, tt.pending); !reflect.DeepEqual(gaps, tt.expected) {
+			t.Errorf("Gaps(%d, %v): expected %v, got %v", tt.lastProcessed, tt.pending, tt.expected, gaps)
+		}
+	}
+}
+
+// Test following changes through the subscription and by polling
+func TestClientWatch(t *testing.T) {
+	c, handler, _ := createClientServer(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	for name, watch := range map[string]func(context.Context, func(client.Change)) error{
+		"subscription": func(ctx context.Context, handle func(client.Change)) error {
+			return c.Watch(ctx, "", "Test Mission", handle)
+		},
+		"polling": func(ctx context.Context, handle func(client.Change)) error {
+			return c.Poll(ctx, url.Values{}, 10*time.Millisecond, handle)
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			rocketID := name + "-rocket"
+			changes := make(chan client.Change, 10)
+			watchCtx, stop := context.WithCancel(ctx)
+			done := make(chan error, 1)
+			go func() {
+				done <- watch(watchCtx, func(change client.Change) {
+					if change.RocketID == rocketID {
+						changes <- change
+					}
+				})
+			}()
+
+			// The subscription only sees changes made after it started, so keep sending until one arrives
+			var change client.Change
+			for number := 1; change.RocketID == ""; number++ {
+				messageType := models.MessageTypeRocketSpeedIncreased
+				if number == 1 {
+					messageType = models.MessageTypeRocketLaunched
+				}
+				handler.Repository.ProcessMessage(createTestMessage(rocketID, number, messageType))
+				select {
+				case change = <-changes:
+				case <-time.After(50 * time.Millisecond):
+				case <-ctx.Done():
+					t.Fatalf("No change received")
+				}
+			}
+			if change.After.Mission != "Test Mission" || change.After.Speed == 0 {
+				t.Errorf("Expected the rocket state after the change, got %+v", change)
+			}
+
+			stop()
+			if err := <-done; err != nil {
+				t.Errorf("Expected watching to stop cleanly, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,26 @@
+package test
+
+import (
+	"strings"
+	"testing"
+
+	"lunar-backend-challenge/internal/config"
+)
+
+// Test that housekeeping intervals a ticker cannot run with are rejected
+func TestLoadRejectsNonPositiveMaintenanceInterval(t *testing.T) {
+	for _, interval := range []string{"0", "-1s"} {
+		if _, err := config.Load([]string{"-maintenance-interval=" + interval}); err == nil || !strings.Contains(err.Error(), "maintenance interval") {
+			t.Errorf("Expected interval %s to be rejected, got %v", interval, err)
+		}
+	}
+
+	t.Setenv("MAINTENANCE_INTERVAL", "-1s")
+	if _, err := config.Load(nil); err == nil {
+		t.Error("Expected a negative MAINTENANCE_INTERVAL to be rejected")
+	}
+
+	if cfg, err := config.Load([]string{"-maintenance-interval=250ms"}); err != nil || cfg.MaintenanceInterval.String() != "250ms" {
+		t.Errorf("Expected a positive interval to be accepted, got %+v (%v)", cfg, err)
+	}
+}
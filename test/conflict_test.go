@@ -0,0 +1,229 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"lunar-backend-challenge/internal/alerting"
+	"lunar-backend-challenge/internal/api"
+	"lunar-backend-challenge/internal/deadletter"
+	"lunar-backend-challenge/internal/grpcapi/rocketsv1"
+	"lunar-backend-challenge/internal/models"
+	"lunar-backend-challenge/internal/registry"
+	"lunar-backend-challenge/internal/storage"
+)
+
+// createConflictHandler creates an API handler whose repository uses the given conflict policy
+func createConflictHandler(t *testing.T, policy storage.ConflictPolicy) *api.ApiHandler {
+	config := storage.DefaultConfig()
+	config.ConflictPolicy = policy
+	handler := api.NewAPIHandlerWithRepository(storage.NewRocketRepositoryWithConfig(config))
+	t.Cleanup(handler.Webhooks.Close)
+	return handler
+}
+
+// postMessage sends a message to the handler and returns the response recorder
+func postMessage(t *testing.T, handler *api.ApiHandler, msg *models.RocketMessage) *httptest.ResponseRecorder {
+	rr := httptest.NewRecorder()
+	handler.HandleMessage(rr, httptest.NewRequest(http.MethodPost, "/messages", createJSONRequestBody(t, msg)))
+	return rr
+}
+
+// Test that the content hash ignores JSON formatting but not the payload
+func TestContentHash(t *testing.T) {
+	compact, err := registry.DecodeMessage([]byte(`{"metadata":{"channel":"a","messageNumber":2,"messageType":"RocketSpeedIncreased"},"message":{"by":500}}`))
+	if err != nil {
+		t.Fatalf("Failed to decode message: %v", err)
+	}
+	spaced, err := registry.DecodeMessage([]byte(`{"message": { "by": 500 }, "metadata": {"messageType": "RocketSpeedIncreased", "messageNumber": 2, "channel": "a"}}`))
+	if err != nil {
+		t.Fatalf("Failed to decode message: %v", err)
+	}
+	if registry.Default.ContentHash(compact) != registry.Default.ContentHash(spaced) {
+		t.Error("Expected the same hash for differently formatted payloads")
+	}
+	if registry.Default.ContentHash(compact) != registry.Default.ContentHash(createSpeedChangeMessage("a", 2, 500)) {
+		t.Error("Expected the same hash for a decoded and a constructed message")
+	}
+	if registry.Default.ContentHash(compact) == registry.Default.ContentHash(createSpeedChangeMessage("a", 2, 600)) {
+		t.Error("Expected a different hash for a different payload")
+	}
+	if registry.Default.ContentHash(compact) == registry.Default.ContentHash(createSpeedChangeMessage("a", 2, -500)) {
+		t.Error("Expected a different hash for a different message type")
+	}
+}
+
+// Test that repeats of processed and buffered messages are told apart from conflicts
+func TestProcessConflicts(t *testing.T) {
+	repo := storage.NewRocketRepository()
+	rocketID := "conflict-rocket"
+
+	steps := []struct {
+		msg     *models.RocketMessage
+		outcome storage.Outcome
+	}{
+		{createTestMessage(rocketID, 1, models.MessageTypeRocketLaunched), storage.OutcomeApplied},
+		{createSpeedChangeMessage(rocketID, 3, 100), storage.OutcomeBuffered},
+		{createSpeedChangeMessage(rocketID, 3, 100), storage.OutcomeDuplicate},
+		{createSpeedChangeMessage(rocketID, 3, 900), storage.OutcomeConflict},
+		{createSpeedChangeMessage(rocketID, 2, 200), storage.OutcomeApplied},
+		{createSpeedChangeMessage(rocketID, 2, 200), storage.OutcomeDuplicate},
+		{createSpeedChangeMessage(rocketID, 2, -200), storage.OutcomeConflict},
+	}
+	for i, step := range steps {
+		if result := repo.Process(step.msg); result.Outcome != step.outcome {
+			t.Errorf("Step %d: expected outcome %s, got %s (%s)", i, step.outcome, result.Outcome, result.Reason)
+		}
+	}
+
+	// The first payloads stand
+	rocket, _ := repo.GetRocket(rocketID)
+	if rocket.Speed != 1000+200+100 || rocket.LastProcessedMessageNumber != 3 {
+		t.Errorf("Expected speed 1300 after message 3, got %d after message %d", rocket.Speed, rocket.LastProcessedMessageNumber)
+	}
+
+	conflicts := repo.GetConflicts(rocketID)
+	if len(conflicts) != 2 || conflicts[0].MessageNumber != 3 || conflicts[1].MessageNumber != 2 {
+		t.Fatalf("Expected conflicts on messages 3 and 2, got %+v", conflicts)
+	}
+	if conflicts[1].MessageType != models.MessageTypeRocketSpeedDecreased || conflicts[1].Policy != storage.ConflictFirstWins ||
+		conflicts[1].AcceptedHash == conflicts[1].ConflictingHash {
+		t.Errorf("Unexpected conflict details: %+v", conflicts[1])
+	}
+}
+
+// Test the response, debug info and dead letter of a conflict under each policy
+func TestHandleMessageConflictPolicies(t *testing.T) {
+	for policy, expectedStatus := range map[storage.ConflictPolicy]int{
+		storage.ConflictFirstWins: http.StatusOK,
+		storage.ConflictReject:    http.StatusConflict,
+		storage.ConflictAlert:     http.StatusOK,
+	} {
+		t.Run(string(policy), func(t *testing.T) {
+			handler := createConflictHandler(t, policy)
+			rocketID := "conflict-http-rocket"
+
+			postMessage(t, handler, createTestHTTPMessage(rocketID, 1, models.MessageTypeRocketLaunched))
+			if rr := postMessage(t, handler, createTestHTTPMessage(rocketID, 1, models.MessageTypeRocketLaunched)); rr.Code != http.StatusOK {
+				t.Errorf("Expected status %d for an exact duplicate, got %d", http.StatusOK, rr.Code)
+			}
+
+			conflicting := createTestHTTPMessage(rocketID, 1, models.MessageTypeRocketLaunched)
+			conflicting.Message.Mission = "Spoofed Mission"
+			rr := postMessage(t, handler, conflicting)
+			if rr.Code != expectedStatus {
+				t.Fatalf("Expected status %d, got %d: %s", expectedStatus, rr.Code, rr.Body.String())
+			}
+			if expectedStatus == http.StatusOK {
+				var response api.MessageResponse
+				if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+					t.Fatalf("Failed to decode response: %v", err)
+				}
+				if response.Outcome != string(storage.OutcomeConflict) || response.Reason == "" {
+					t.Errorf("Expected conflict outcome with a reason, got %+v", response)
+				}
+			}
+
+			if rocket, _ := handler.Repository.GetRocket(rocketID); rocket.Mission != "Test Mission" {
+				t.Errorf("Expected the first mission to stand, got %s", rocket.Mission)
+			}
+
+			entries := handler.DeadLetters.List(deadletter.Filter{Reason: deadletter.ReasonConflict})
+			if len(entries) != 1 || entries[0].MessageNumber != 1 || entries[0].Channel != rocketID {
+				t.Errorf("Expected one conflict dead letter, got %+v", entries)
+			}
+
+			debug := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/debug/rockets/"+rocketID, nil)
+			req.SetPathValue("id", rocketID)
+			handler.HandleDebugRocket(debug, req)
+
+			var debugInfo api.DebugInfo
+			if err := json.NewDecoder(debug.Body).Decode(&debugInfo); err != nil {
+				t.Fatalf("Failed to decode debug info: %v", err)
+			}
+			if debugInfo.ConflictCount != 1 || debugInfo.Conflicts[0].Policy != policy {
+				t.Errorf("Expected one %s conflict in the debug info, got %+v", policy, debugInfo)
+			}
+		})
+	}
+}
+
+// Test that retransmissions with explicit zero values or extra fields, over HTTP or gRPC, are
+// duplicates and not conflicts
+func TestRetransmissionEncodings(t *testing.T) {
+	handler := createConflictHandler(t, storage.ConflictReject)
+	client := createGRPCClient(t, handler.Repository, handler.DeadLetters)
+	rocketID := "encoded-rocket"
+	post := func(number int, messageType, payload string) *httptest.ResponseRecorder {
+		body := fmt.Sprintf(`{"metadata": {"channel": %q, "messageNumber": %d, "messageTime": "2024-03-14T19:39:05Z", "messageType": %q}, "message": %s}`,
+			rocketID, number, messageType, payload)
+		rr := httptest.NewRecorder()
+		handler.HandleMessage(rr, httptest.NewRequest(http.MethodPost, "/messages", strings.NewReader(body)))
+		return rr
+	}
+
+	if rr := post(1, models.MessageTypeRocketLaunched, `{"type": "Falcon-9", "launchSpeed": 0, "mission": "ARTEMIS"}`); rr.Code != http.StatusOK {
+		t.Fatalf("Expected the launch to be accepted, got %d: %s", rr.Code, rr.Body.String())
+	}
+	post(2, models.MessageTypeRocketSpeedIncreased, `{"by": 300}`)
+
+	for _, payload := range []string{`{"by": 300, "reason": "", "newMission": null}`, `{"by": 300, "source": "ground-station-2"}`} {
+		rr := post(2, models.MessageTypeRocketSpeedIncreased, payload)
+		if rr.Code != http.StatusOK || !strings.Contains(rr.Body.String(), string(storage.OutcomeDuplicate)) {
+			t.Errorf("Expected %s to be a duplicate, got %d: %s", payload, rr.Code, rr.Body.String())
+		}
+	}
+
+	// The launch again over gRPC, where the zero launch speed is not encoded at all
+	response, err := client.IngestMessage(context.Background(), &rocketsv1.IngestMessageRequest{
+		Message: createProtoMessage(rocketID, 1, models.MessageTypeRocketLaunched, &rocketsv1.MessageContent{Type: "Falcon-9", Mission: "ARTEMIS"}),
+	})
+	if err != nil || response.GetOutcome() != string(storage.OutcomeDuplicate) {
+		t.Errorf("Expected the launch over gRPC to be a duplicate, got %+v (%v)", response, err)
+	}
+
+	if entries := handler.DeadLetters.List(deadletter.Filter{}); len(entries) != 0 {
+		t.Errorf("Expected no dead letters, got %+v", entries)
+	}
+}
+
+// Test that the alert policy raises conflict alerts, resolved by the next state change
+func TestConflictAlert(t *testing.T) {
+	now := time.Date(2024, 3, 14, 19, 39, 0, 0, time.UTC)
+	config := storage.DefaultConfig()
+	config.ConflictPolicy = storage.ConflictAlert
+	config.Now = func() time.Time { return now }
+	repo := storage.NewRocketRepositoryWithConfig(config)
+
+	engine := alerting.NewEngine()
+	if err := engine.AddRule(alerting.Rule{ID: "spoofed", Kind: alerting.KindConflict}); err != nil {
+		t.Fatalf("Failed to add rule: %v", err)
+	}
+	repo.Subscribe(engine.Observe)
+
+	rocketID := "alert-conflict-rocket"
+	repo.ProcessMessage(createTestMessage(rocketID, 1, models.MessageTypeRocketLaunched))
+	repo.ProcessMessage(createSpeedChangeMessage(rocketID, 2, 100))
+	repo.ProcessMessage(createSpeedChangeMessage(rocketID, 2, 100))
+	if active := engine.Active(); len(active) != 0 {
+		t.Fatalf("Expected no alert for an exact duplicate, got %+v", active)
+	}
+
+	repo.ProcessMessage(createSpeedChangeMessage(rocketID, 2, 5000))
+	active := engine.Active()
+	if len(active) != 1 || active[0].Kind != alerting.KindConflict || active[0].RocketID != rocketID {
+		t.Fatalf("Expected a conflict alert, got %+v", active)
+	}
+
+	repo.ProcessMessage(createSpeedChangeMessage(rocketID, 3, 100))
+	if active := engine.Active(); len(active) != 0 {
+		t.Errorf("Expected the conflict alert to resolve on the next state change, got %+v", active)
+	}
+}
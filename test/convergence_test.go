@@ -0,0 +1,180 @@
+package test
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"pgregory.net/rapid"
+
+	"lunar-backend-challenge/internal/models"
+	"lunar-backend-challenge/internal/storage"
+)
+
+// Property tests: whatever order the messages of a rocket arrive in, and however often, the
+// repository ends in the state of applying them once in order. Failures are shrunk by rapid to a
+// minimal history and delivery order; rerun one with -rapid.seed, or run more cases with -rapid.checks.
+
+// historyMessageTypes are the message types after the first launch, launches being relaunches
+var historyMessageTypes = []string{
+	models.MessageTypeRocketSpeedIncreased,
+	models.MessageTypeRocketSpeedDecreased,
+	models.MessageTypeRocketMissionChanged,
+	models.MessageTypeRocketExploded,
+	models.MessageTypeRocketLaunched,
+}
+
+// historyGenerator generates the complete message history of a rocket, numbered from 1
+func historyGenerator(rocketID string) *rapid.Generator[[]*models.RocketMessage] {
+	return rapid.Custom(func(t *rapid.T) []*models.RocketMessage {
+		length := rapid.IntRange(1, 12).Draw(t, rocketID+" length")
+		start := time.Now().Add(-time.Hour).Truncate(time.Second)
+
+		history := make([]*models.RocketMessage, length)
+		for i := range history {
+			messageType := models.MessageTypeRocketLaunched
+			if i > 0 {
+				messageType = rapid.SampledFrom(historyMessageTypes).Draw(t, fmt.Sprintf("%s type %d", rocketID, i+1))
+			}
+			msg := createTestMessage(rocketID, i+1, messageType)
+			msg.Metadata.MessageTime = start.Add(time.Duration(i) * time.Second)
+			switch messageType {
+			case models.MessageTypeRocketLaunched:
+				msg.Message.LaunchSpeed = 100 * rapid.IntRange(1, 50).Draw(t, fmt.Sprintf("%s launch speed %d", rocketID, i+1))
+				msg.Message.Mission = rapid.SampledFrom([]string{"ARTEMIS", "APOLLO"}).Draw(t, fmt.Sprintf("%s mission %d", rocketID, i+1))
+			case models.MessageTypeRocketSpeedIncreased, models.MessageTypeRocketSpeedDecreased:
+				msg.Message.By = 100 * rapid.IntRange(1, 50).Draw(t, fmt.Sprintf("%s by %d", rocketID, i+1))
+			case models.MessageTypeRocketMissionChanged:
+				msg.Message.NewMission = rapid.SampledFrom([]string{"GEMINI", "MERCURY"}).Draw(t, fmt.Sprintf("%s new mission %d", rocketID, i+1))
+			}
+			history[i] = msg
+		}
+		return history
+	})
+}
+
+// deliveryGenerator generates an arrival order for histories: every message once, some of them
+// again, in any order. Shrinking moves towards in-order delivery without duplicates.
+func deliveryGenerator(histories [][]*models.RocketMessage) *rapid.Generator[[]*models.RocketMessage] {
+	return rapid.Custom(func(t *rapid.T) []*models.RocketMessage {
+		var messages []*models.RocketMessage
+		for _, history := range histories {
+			messages = append(messages, history...)
+		}
+		duplicates := rapid.SliceOfN(rapid.SampledFrom(messages), 0, len(messages)).Draw(t, "duplicates")
+		for _, msg := range duplicates {
+			// A retransmission is an equal message, not the same pointer
+			retransmission := *msg
+			messages = append(messages, &retransmission)
+		}
+		return rapid.Permutation(messages).Draw(t, "arrival order")
+	})
+}
+
+// drawHistories draws the histories of up to three rockets
+func drawHistories(t *rapid.T) [][]*models.RocketMessage {
+	count := rapid.IntRange(1, 3).Draw(t, "rockets")
+	histories := make([][]*models.RocketMessage, count)
+	for i := range histories {
+		histories[i] = historyGenerator(fmt.Sprintf("property-rocket-%d", i+1)).Draw(t, fmt.Sprintf("history %d", i+1))
+	}
+	return histories
+}
+
+// inOrderFold applies each history once, in order, to a new repository
+func inOrderFold(histories [][]*models.RocketMessage) *storage.RocketRepository {
+	repository := storage.NewRocketRepository()
+	for _, history := range histories {
+		for _, msg := range history {
+			repository.ProcessMessage(msg)
+		}
+	}
+	return repository
+}
+
+// comparableState returns the state of a rocket without the receive times, which depend on delivery
+func comparableState(repository *storage.RocketRepository, rocketID string) *models.RocketState {
+	rocket, exists := repository.GetRocket(rocketID)
+	if !exists {
+		return nil
+	}
+	rocket.LastSeen = time.Time{}
+	return rocket
+}
+
+// describeDeliveries lists messages compactly, e.g. [property-rocket-1#2:RocketExploded ...]
+func describeDeliveries(messages []*models.RocketMessage) string {
+	descriptions := make([]string, len(messages))
+	for i, msg := range messages {
+		descriptions[i] = fmt.Sprintf("%s#%d:%s", msg.GetChannel(), msg.GetMessageNumber(), msg.GetMessageType())
+	}
+	return fmt.Sprint(descriptions)
+}
+
+// assertConverged fails t unless every rocket of got is in the state of the in-order fold, with nothing left pending
+func assertConverged(t *rapid.T, histories [][]*models.RocketMessage, deliveries []*models.RocketMessage, got *storage.RocketRepository) {
+	expected := inOrderFold(histories)
+	for _, history := range histories {
+		rocketID := history[0].GetChannel()
+		want, have := comparableState(expected, rocketID), comparableState(got, rocketID)
+		if !reflect.DeepEqual(want, have) {
+			t.Fatalf("Rocket %s diverged from the in-order fold after %s:\nexpected %+v\ngot      %+v", rocketID, describeDeliveries(deliveries), want, have)
+		}
+		if processed, pending := got.GetDebugInfo(rocketID); processed != len(history) || len(pending) != 0 {
+			t.Fatalf("Rocket %s: expected %d processed and no pending messages after %s, got %d processed and pending %v", rocketID, len(history), describeDeliveries(deliveries), processed, pending)
+		}
+	}
+}
+
+// Test that any arrival order with any duplicates converges to the in-order fold
+func TestConvergenceSequential(t *testing.T) {
+	rapid.Check(t, func(t *rapid.T) {
+		histories := drawHistories(t)
+		deliveries := deliveryGenerator(histories).Draw(t, "deliveries")
+
+		repository := storage.NewRocketRepository()
+		for _, msg := range deliveries {
+			repository.ProcessMessage(msg)
+		}
+		assertConverged(t, histories, deliveries, repository)
+	})
+}
+
+// Test convergence when the deliveries are split between concurrent senders
+func TestConvergenceConcurrent(t *testing.T) {
+	rapid.Check(t, func(t *rapid.T) {
+		histories := drawHistories(t)
+		deliveries := deliveryGenerator(histories).Draw(t, "deliveries")
+		senders := rapid.IntRange(2, 4).Draw(t, "senders")
+
+		repository := storage.NewRocketRepository()
+		var wg sync.WaitGroup
+		for sender := range senders {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := sender; i < len(deliveries); i += senders {
+					repository.ProcessMessage(deliveries[i])
+				}
+			}()
+		}
+		wg.Wait()
+		assertConverged(t, histories, deliveries, repository)
+	})
+}
+
+// Test that delivering every message again, in any order, after convergence changes nothing
+func TestConvergenceIdempotent(t *testing.T) {
+	rapid.Check(t, func(t *rapid.T) {
+		histories := drawHistories(t)
+		repository := inOrderFold(histories)
+		deliveries := deliveryGenerator(histories).Draw(t, "deliveries")
+		for _, msg := range deliveries {
+			retransmission := *msg
+			repository.ProcessMessage(&retransmission)
+		}
+		assertConverged(t, histories, deliveries, repository)
+	})
+}
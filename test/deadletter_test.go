@@ -0,0 +1,246 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"lunar-backend-challenge/internal/deadletter"
+	"lunar-backend-challenge/internal/models"
+)
+
+// Test dead-letter store filtering and removal
+func TestDeadLetterStore_FilterAndRemove(t *testing.T) {
+	store := deadletter.NewStore(10)
+
+	first := store.Add(deadletter.Entry{Channel: "rocket-a", Reason: deadletter.ReasonValidationFailed})
+	second := store.Add(deadletter.Entry{Channel: "rocket-b", Reason: deadletter.ReasonProcessingFailed})
+	store.Add(deadletter.Entry{Channel: "rocket-a", Reason: deadletter.ReasonProcessingFailed})
+
+	if store.Len() != 3 {
+		t.Fatalf("Expected 3 entries, got %d", store.Len())
+	}
+
+	byChannel := store.List(deadletter.Filter{Channel: "rocket-a"})
+	if len(byChannel) != 2 || byChannel[0].ID != first.ID {
+		t.Errorf("Expected 2 entries for rocket-a starting with %s, got %v", first.ID, byChannel)
+	}
+
+	byReason := store.List(deadletter.Filter{Reason: deadletter.ReasonProcessingFailed})
+	if len(byReason) != 2 || byReason[0].ID != second.ID {
+		t.Errorf("Expected 2 processing_failed entries starting with %s, got %v", second.ID, byReason)
+	}
+
+	both := store.List(deadletter.Filter{Channel: "rocket-a", Reason: deadletter.ReasonValidationFailed})
+	if len(both) != 1 {
+		t.Errorf("Expected 1 entry matching both filters, got %d", len(both))
+	}
+
+	if !store.Discard(first.ID) {
+		t.Error("Expected discard to succeed")
+	}
+	if store.Discard(first.ID) {
+		t.Error("Expected second discard of the same entry to fail")
+	}
+	if !store.MarkReplayed(second.ID) {
+		t.Error("Expected replayed entry to be removed")
+	}
+
+	stats := store.Stats()
+	if stats.Depth != 1 || stats.Discarded != 1 || stats.Replayed != 1 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+}
+
+// Test that the store evicts the oldest entries when full
+func TestDeadLetterStore_Eviction(t *testing.T) {
+	store := deadletter.NewStore(2)
+
+	first := store.Add(deadletter.Entry{Reason: deadletter.ReasonInvalidJSON})
+	store.Add(deadletter.Entry{Reason: deadletter.ReasonInvalidJSON})
+	store.Add(deadletter.Entry{Reason: deadletter.ReasonInvalidJSON})
+
+	if store.Len() != 2 {
+		t.Errorf("Expected 2 entries, got %d", store.Len())
+	}
+	if _, exists := store.Get(first.ID); exists {
+		t.Error("Expected oldest entry to be evicted")
+	}
+	if store.Stats().Evicted != 1 {
+		t.Errorf("Expected 1 evicted entry, got %d", store.Stats().Evicted)
+	}
+}
+
+// Helper function to list dead letters through the API
+func listDeadLetters(t *testing.T, url string) []deadletter.Entry {
+	resp := sendHTTPRequest(t, "GET", url, nil)
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected status %d listing dead letters, got %d", http.StatusOK, resp.StatusCode)
+	}
+
+	var entries []deadletter.Entry
+	if err := json.NewDecoder(resp.Body).Decode(&entries); err != nil {
+		t.Fatalf("Failed to decode dead letters: %v", err)
+	}
+	return entries
+}
+
+// Test that rejected messages are dead-lettered and can be inspected
+func TestIntegration_DeadLetters(t *testing.T) {
+	server := createTestServer()
+	defer server.Close()
+
+	rocketID := "dead-letter-rocket"
+
+	// Invalid JSON
+	resp, err := http.Post(server.URL+"/messages", "application/json", strings.NewReader("{not json"))
+	if err != nil {
+		t.Fatalf("Failed to send request: %v", err)
+	}
+	resp.Body.Close()
+
+	// Validation failure: speed change without amount
+	invalidMsg := createIntegrationTestMessage(rocketID, 2, models.MessageTypeRocketSpeedIncreased)
+	invalidMsg.Message.By = 0
+	resp = sendHTTPRequest(t, "POST", server.URL+"/messages", invalidMsg)
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected status %d for invalid message, got %d", http.StatusBadRequest, resp.StatusCode)
+	}
+
+	entries := listDeadLetters(t, server.URL+"/admin/dead-letters")
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 dead letters, got %d", len(entries))
+	}
+
+	if entries[0].Reason != deadletter.ReasonInvalidJSON || entries[0].Body != "{not json" {
+		t.Errorf("Expected invalid JSON entry with raw body, got %+v", entries[0])
+	}
+
+	if entries[1].Reason != deadletter.ReasonValidationFailed || entries[1].Channel != rocketID || entries[1].MessageNumber != 2 {
+		t.Errorf("Expected validation entry for %s #2, got %+v", rocketID, entries[1])
+	}
+
+	if entries[1].ReceivedAt.IsZero() || time.Since(entries[1].ReceivedAt) > time.Minute {
+		t.Errorf("Expected recent received time, got %v", entries[1].ReceivedAt)
+	}
+
+	filtered := listDeadLetters(t, server.URL+"/admin/dead-letters?channel="+rocketID+"&reason=validation_failed")
+	if len(filtered) != 1 || filtered[0].ID != entries[1].ID {
+		t.Errorf("Expected filtered list to contain only %s, got %v", entries[1].ID, filtered)
+	}
+
+	resp = sendHTTPRequest(t, "GET", server.URL+"/admin/dead-letters?reason=bogus", nil)
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest || !strings.Contains(string(body), "conflict, clock_skew") {
+		t.Errorf("Expected status %d listing all reasons for invalid reason, got %d: %s", http.StatusBadRequest, resp.StatusCode, body)
+	}
+
+	resp = sendHTTPRequest(t, "GET", server.URL+"/admin/dead-letters?reason=clock_skew", nil)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected status %d for the clock_skew reason, got %d", http.StatusOK, resp.StatusCode)
+	}
+
+	resp = sendHTTPRequest(t, "GET", server.URL+"/admin/dead-letters/"+entries[1].ID, nil)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected status %d getting dead letter, got %d", http.StatusOK, resp.StatusCode)
+	}
+}
+
+// Test edit-and-replay and discard of dead-lettered messages
+func TestIntegration_DeadLetterReplayAndDiscard(t *testing.T) {
+	server := createTestServer()
+	defer server.Close()
+
+	rocketID := "replay-rocket"
+
+	launchMsg := createIntegrationTestMessage(rocketID, 1, models.MessageTypeRocketLaunched)
+	resp := sendHTTPRequest(t, "POST", server.URL+"/messages", launchMsg)
+	resp.Body.Close()
+
+	invalidMsg := createIntegrationTestMessage(rocketID, 2, models.MessageTypeRocketSpeedIncreased)
+	invalidMsg.Message.By = 0
+	resp = sendHTTPRequest(t, "POST", server.URL+"/messages", invalidMsg)
+	resp.Body.Close()
+
+	garbage, err := http.Post(server.URL+"/messages", "application/json", strings.NewReader("garbage"))
+	if err != nil {
+		t.Fatalf("Failed to send request: %v", err)
+	}
+	garbage.Body.Close()
+
+	entries := listDeadLetters(t, server.URL+"/admin/dead-letters")
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 dead letters, got %d", len(entries))
+	}
+
+	// Replaying unchanged fails again and keeps the entry
+	resp = sendHTTPRequest(t, "POST", server.URL+"/admin/dead-letters/"+entries[0].ID+"/replay", nil)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected status %d replaying unchanged message, got %d", http.StatusBadRequest, resp.StatusCode)
+	}
+
+	// Edit and replay
+	fixedMsg := createIntegrationTestMessage(rocketID, 2, models.MessageTypeRocketSpeedIncreased)
+	resp = sendHTTPRequest(t, "POST", server.URL+"/admin/dead-letters/"+entries[0].ID+"/replay", fixedMsg)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected status %d replaying edited message, got %d", http.StatusOK, resp.StatusCode)
+	}
+
+	resp = sendHTTPRequest(t, "GET", server.URL+"/rockets/"+rocketID, nil)
+	var rocket models.RocketState
+	if err := json.NewDecoder(resp.Body).Decode(&rocket); err != nil {
+		t.Fatalf("Failed to decode rocket response: %v", err)
+	}
+	resp.Body.Close()
+
+	expectedSpeed := launchMsg.Message.LaunchSpeed + fixedMsg.Message.By
+	if rocket.Speed != expectedSpeed {
+		t.Errorf("Expected speed %d after replay, got %d", expectedSpeed, rocket.Speed)
+	}
+
+	// Discard the remaining entry
+	resp = sendHTTPRequest(t, "DELETE", server.URL+"/admin/dead-letters/"+entries[1].ID, nil)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected status %d discarding dead letter, got %d", http.StatusOK, resp.StatusCode)
+	}
+
+	resp = sendHTTPRequest(t, "DELETE", server.URL+"/admin/dead-letters/"+entries[1].ID, nil)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected status %d discarding missing dead letter, got %d", http.StatusNotFound, resp.StatusCode)
+	}
+
+	if remaining := listDeadLetters(t, server.URL+"/admin/dead-letters"); len(remaining) != 0 {
+		t.Errorf("Expected empty dead-letter store, got %d entries", len(remaining))
+	}
+
+	// Metrics reflect depth and activity
+	resp = sendHTTPRequest(t, "GET", server.URL+"/metrics", nil)
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	for _, expected := range [][]byte{
+		[]byte("rocket_dead_letters_depth 0"),
+		[]byte("rocket_dead_letters_replayed_total 1"),
+		[]byte("rocket_dead_letters_discarded_total 1"),
+		[]byte(`rocket_dead_letters_added_total{reason="validation_failed"} 1`),
+	} {
+		if !bytes.Contains(body, expected) {
+			t.Errorf("Expected metrics to contain %q, got:\n%s", expected, body)
+		}
+	}
+}
@@ -0,0 +1,190 @@
+package test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"lunar-backend-challenge/internal/api"
+	"lunar-backend-challenge/internal/models"
+	"lunar-backend-challenge/internal/storage"
+)
+
+// Helper function to generate every permutation of the given messages
+func permutations(messages []*models.RocketMessage) [][]*models.RocketMessage {
+	if len(messages) <= 1 {
+		return [][]*models.RocketMessage{messages}
+	}
+
+	var result [][]*models.RocketMessage
+	for i := range messages {
+		rest := make([]*models.RocketMessage, 0, len(messages)-1)
+		rest = append(rest, messages[:i]...)
+		rest = append(rest, messages[i+1:]...)
+
+		for _, perm := range permutations(rest) {
+			result = append(result, append([]*models.RocketMessage{messages[i]}, perm...))
+		}
+	}
+	return result
+}
+
+// Helper function to create a relaunch message with a distinct mission and speed
+func createRelaunchMessage(channel string, messageNumber int) *models.RocketMessage {
+	msg := createTestMessage(channel, messageNumber, models.MessageTypeRocketLaunched)
+	msg.Message.Mission = "Relaunch Mission"
+	msg.Message.LaunchSpeed = 2000
+	return msg
+}
+
+// Test that a relaunch is applied after a message that arrives for an exploded rocket
+func TestRelaunchAfterIgnoredMessage(t *testing.T) {
+	repo := storage.NewRocketRepository()
+	rocketID := "relaunch-rocket"
+
+	expected := []struct {
+		msg     *models.RocketMessage
+		outcome storage.Outcome
+	}{
+		{createTestMessage(rocketID, 1, models.MessageTypeRocketLaunched), storage.OutcomeApplied},
+		{createTestMessage(rocketID, 2, models.MessageTypeRocketExploded), storage.OutcomeApplied},
+		{createTestMessage(rocketID, 3, models.MessageTypeRocketSpeedIncreased), storage.OutcomeIgnored},
+		{createRelaunchMessage(rocketID, 4), storage.OutcomeApplied},
+		{createTestMessage(rocketID, 4, models.MessageTypeRocketLaunched), storage.OutcomeConflict},
+	}
+
+	for _, step := range expected {
+		result := repo.Process(step.msg)
+		if result.Outcome != step.outcome {
+			t.Errorf("Message %d (%s): expected outcome %s, got %s (%s)",
+				step.msg.GetMessageNumber(), step.msg.GetMessageType(), step.outcome, result.Outcome, result.Reason)
+		}
+	}
+
+	rocket, _ := repo.GetRocket(rocketID)
+	if rocket.Exploded {
+		t.Error("Expected relaunched rocket not to be exploded")
+	}
+	if rocket.Mission != "Relaunch Mission" || rocket.Speed != 2000 {
+		t.Errorf("Expected relaunch state, got mission %s speed %d", rocket.Mission, rocket.Speed)
+	}
+	if rocket.LastProcessedMessageNumber != 4 {
+		t.Errorf("Expected last processed message number 4, got %d", rocket.LastProcessedMessageNumber)
+	}
+}
+
+// Test that explosion followed by a relaunch converges for every arrival order
+func TestExplosionAndRelaunch_AllArrivalOrders(t *testing.T) {
+	rocketID := "permutation-relaunch-rocket"
+
+	messages := []*models.RocketMessage{
+		createTestMessage(rocketID, 1, models.MessageTypeRocketLaunched),
+		createTestMessage(rocketID, 2, models.MessageTypeRocketSpeedIncreased),
+		createTestMessage(rocketID, 3, models.MessageTypeRocketExploded),
+		createTestMessage(rocketID, 4, models.MessageTypeRocketSpeedIncreased),
+		createTestMessage(rocketID, 5, models.MessageTypeRocketMissionChanged),
+		createRelaunchMessage(rocketID, 6),
+		createTestMessage(rocketID, 7, models.MessageTypeRocketSpeedDecreased),
+	}
+
+	for _, order := range permutations(messages) {
+		repo := storage.NewRocketRepository()
+		for _, msg := range order {
+			if result := repo.Process(msg); result.Outcome == storage.OutcomeRejected {
+				t.Fatalf("Order %v: message %d rejected: %s", messageNumbers(order), msg.GetMessageNumber(), result.Reason)
+			}
+		}
+
+		rocket, exists := repo.GetRocket(rocketID)
+		if !exists {
+			t.Fatalf("Order %v: expected rocket to exist", messageNumbers(order))
+		}
+
+		if rocket.Exploded || rocket.Reason != "" {
+			t.Errorf("Order %v: expected relaunched rocket, got exploded=%v reason=%q", messageNumbers(order), rocket.Exploded, rocket.Reason)
+		}
+		if rocket.Mission != "Relaunch Mission" {
+			t.Errorf("Order %v: expected mission Relaunch Mission, got %s", messageNumbers(order), rocket.Mission)
+		}
+		if rocket.Speed != 2000-300 {
+			t.Errorf("Order %v: expected speed %d, got %d", messageNumbers(order), 2000-300, rocket.Speed)
+		}
+		if rocket.LastProcessedMessageNumber != 7 {
+			t.Errorf("Order %v: expected last processed message number 7, got %d", messageNumbers(order), rocket.LastProcessedMessageNumber)
+		}
+
+		processedCount, pending := repo.GetDebugInfo(rocketID)
+		if processedCount != 7 || len(pending) != 0 {
+			t.Errorf("Order %v: expected 7 processed and no pending messages, got %d and %v", messageNumbers(order), processedCount, pending)
+		}
+	}
+}
+
+// Test that an explosion without relaunch converges for every arrival order
+func TestExplosionWithoutRelaunch_AllArrivalOrders(t *testing.T) {
+	rocketID := "permutation-exploded-rocket"
+
+	messages := []*models.RocketMessage{
+		createTestMessage(rocketID, 1, models.MessageTypeRocketLaunched),
+		createTestMessage(rocketID, 2, models.MessageTypeRocketExploded),
+		createTestMessage(rocketID, 3, models.MessageTypeRocketSpeedIncreased),
+		createTestMessage(rocketID, 4, models.MessageTypeRocketSpeedDecreased),
+		createTestMessage(rocketID, 5, models.MessageTypeRocketMissionChanged),
+	}
+
+	for _, order := range permutations(messages) {
+		repo := storage.NewRocketRepository()
+		for _, msg := range order {
+			repo.Process(msg)
+		}
+
+		rocket, _ := repo.GetRocket(rocketID)
+		if !rocket.Exploded || rocket.Reason != "Engine failure" {
+			t.Errorf("Order %v: expected exploded rocket, got exploded=%v reason=%q", messageNumbers(order), rocket.Exploded, rocket.Reason)
+		}
+		if rocket.Speed != 1000 || rocket.Mission != "Test Mission" {
+			t.Errorf("Order %v: expected state frozen at explosion, got speed %d mission %s", messageNumbers(order), rocket.Speed, rocket.Mission)
+		}
+		if rocket.LastProcessedMessageNumber != 5 {
+			t.Errorf("Order %v: expected last processed message number 5, got %d", messageNumbers(order), rocket.LastProcessedMessageNumber)
+		}
+	}
+}
+
+// Test that the HTTP response reports ignored messages explicitly
+func TestHandleMessage_IgnoredAfterExplosion(t *testing.T) {
+	handler := api.NewAPIHandler()
+	rocketID := "ignored-http-rocket"
+
+	handler.Repository.ProcessMessage(createTestHTTPMessage(rocketID, 1, models.MessageTypeRocketLaunched))
+	handler.Repository.ProcessMessage(createTestHTTPMessage(rocketID, 2, models.MessageTypeRocketExploded))
+
+	msg := createTestHTTPMessage(rocketID, 3, models.MessageTypeRocketSpeedIncreased)
+	req := httptest.NewRequest(http.MethodPost, "/messages", createJSONRequestBody(t, msg))
+	rr := httptest.NewRecorder()
+
+	handler.HandleMessage(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, rr.Code)
+	}
+
+	var response api.MessageResponse
+	if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	if response.Outcome != string(storage.OutcomeIgnored) || response.Reason == "" {
+		t.Errorf("Expected ignored outcome with reason, got %+v", response)
+	}
+}
+
+// Helper function to describe an arrival order in failure messages
+func messageNumbers(messages []*models.RocketMessage) []int {
+	numbers := make([]int, len(messages))
+	for i, msg := range messages {
+		numbers[i] = msg.GetMessageNumber()
+	}
+	return numbers
+}
@@ -0,0 +1,225 @@
+package test
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"lunar-backend-challenge/internal/api"
+	"lunar-backend-challenge/internal/export"
+	"lunar-backend-challenge/internal/models"
+	"lunar-backend-challenge/internal/storage"
+)
+
+// createExportHandler creates a handler with three rockets of different speeds
+func createExportHandler(t *testing.T) *api.ApiHandler {
+	handler := api.NewAPIHandler()
+	t.Cleanup(handler.Webhooks.Close)
+	for i, speed := range []int{300, 100, 200} {
+		msg := createTestMessage(string(rune('a'+i))+"-rocket", 1, models.MessageTypeRocketLaunched)
+		msg.Message.LaunchSpeed = speed
+		handler.Repository.ProcessMessage(msg)
+	}
+	return handler
+}
+
+// Test format negotiation by query parameter and Accept header
+func TestNegotiateFormat(t *testing.T) {
+	tests := []struct {
+		name     string
+		url      string
+		accept   string
+		expected export.Format
+		wantErr  bool
+	}{
+		{"default", "/rockets", "", export.FormatJSON, false},
+		{"accept csv", "/rockets", "text/csv", export.FormatCSV, false},
+		{"accept ndjson with parameters", "/rockets", "application/x-ndjson; charset=utf-8", export.FormatNDJSON, false},
+		{"first supported type wins", "/rockets", "application/xml, text/csv;q=0.9, application/json;q=0.8", export.FormatCSV, false},
+		{"query overrides accept", "/rockets?format=NDJSON", "text/csv", export.FormatNDJSON, false},
+		{"unsupported query format", "/rockets?format=xml", "", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+			format, err := export.Negotiate(req)
+			if (err != nil) != tt.wantErr || format != tt.expected {
+				t.Errorf("Expected %q (error %v), got %q (%v)", tt.expected, tt.wantErr, format, err)
+			}
+		})
+	}
+}
+
+// Test CSV export of rockets with a header from the RocketSummary fields and the requested order
+func TestGetRocketsCSV(t *testing.T) {
+	handler := createExportHandler(t)
+
+	rr := httptest.NewRecorder()
+	handler.HandleGetRockets(rr, httptest.NewRequest(http.MethodGet, "/rockets?format=csv&sortBy=speed&sortOrder=desc", nil))
+	if rr.Code != http.StatusOK || !strings.HasPrefix(rr.Header().Get("Content-Type"), export.MediaTypeCSV) {
+		t.Fatalf("Expected a CSV response, got %d %s", rr.Code, rr.Header().Get("Content-Type"))
+	}
+
+	rows, err := csv.NewReader(rr.Body).ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to parse CSV: %v", err)
+	}
+	columns := export.Columns(reflect.TypeOf(models.RocketSummary{}))
+	if len(rows) != 4 || !reflect.DeepEqual(rows[0], columns) {
+		t.Fatalf("Expected a header %v and 3 rows, got %v", columns, rows)
+	}
+	if columns[0] != "id" || columns[2] != "speed" {
+		t.Errorf("Expected columns named by the JSON fields, got %v", columns)
+	}
+	for i, id := range []string{"a-rocket", "c-rocket", "b-rocket"} {
+		if rows[i+1][0] != id {
+			t.Errorf("Row %d: expected %s, got %v", i+1, id, rows[i+1])
+		}
+	}
+	if rows[1][2] != "300" || rows[1][4] != "false" {
+		t.Errorf("Unexpected values in %v", rows[1])
+	}
+}
+
+// Test that CSV cells spreadsheets would evaluate as formulas are escaped
+func TestCSVEscapesFormulas(t *testing.T) {
+	type record struct {
+		Name  string `json:"name"`
+		Speed int    `json:"speed"`
+	}
+	records := []record{{"=HYPERLINK(\"http://example.com\")", -1}, {"+1", 0}, {"-1", 0}, {"@SUM(A1)", 0}, {"ARTEMIS", 0}}
+
+	rr := httptest.NewRecorder()
+	if err := export.Write(rr, export.FormatCSV, records); err != nil {
+		t.Fatalf("Failed to write CSV: %v", err)
+	}
+	rows, err := csv.NewReader(rr.Body).ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to parse CSV: %v", err)
+	}
+	expected := []string{`'=HYPERLINK("http://example.com")`, "'+1", "'-1", "'@SUM(A1)", "ARTEMIS"}
+	for i, name := range expected {
+		if rows[i+1][0] != name {
+			t.Errorf("Row %d: expected %s, got %s", i+1, name, rows[i+1][0])
+		}
+	}
+	if rows[1][1] != "-1" {
+		t.Errorf("Expected numbers not to be escaped, got %s", rows[1][1])
+	}
+}
+
+// Test that streamed exports are not cut off by the server's write timeout
+func TestExportClearsWriteDeadline(t *testing.T) {
+	handler := createExportHandler(t)
+	server := httptest.NewUnstartedServer(http.HandlerFunc(handler.HandleGetRockets))
+	server.Config.WriteTimeout = time.Nanosecond
+	server.Start()
+	defer server.Close()
+
+	for _, format := range []string{"csv", "ndjson"} {
+		resp, err := http.Get(server.URL + "/rockets?format=" + format)
+		if err != nil {
+			t.Fatalf("Failed to export %s: %v", format, err)
+		}
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil || resp.StatusCode != http.StatusOK || !strings.Contains(string(body), "a-rocket") {
+			t.Errorf("Expected the %s export to be complete, got %d %q (%v)", format, resp.StatusCode, body, err)
+		}
+	}
+}
+
+// Test NDJSON export of rockets negotiated by the Accept header
+func TestGetRocketsNDJSON(t *testing.T) {
+	handler := createExportHandler(t)
+
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/rockets?sortBy=speed", nil)
+	req.Header.Set("Accept", export.MediaTypeNDJSON)
+	handler.HandleGetRockets(rr, req)
+	if rr.Header().Get("Content-Type") != export.MediaTypeNDJSON {
+		t.Fatalf("Expected an NDJSON response, got %s", rr.Header().Get("Content-Type"))
+	}
+
+	var speeds []int
+	scanner := bufio.NewScanner(rr.Body)
+	for scanner.Scan() {
+		var rocket models.RocketSummary
+		if err := json.Unmarshal(scanner.Bytes(), &rocket); err != nil {
+			t.Fatalf("Failed to decode line %q: %v", scanner.Text(), err)
+		}
+		speeds = append(speeds, rocket.Speed)
+	}
+	if !reflect.DeepEqual(speeds, []int{100, 200, 300}) {
+		t.Errorf("Expected rockets sorted by speed, got %v", speeds)
+	}
+
+	rr = httptest.NewRecorder()
+	handler.HandleGetRockets(rr, httptest.NewRequest(http.MethodGet, "/rockets?format=xml", nil))
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d for an unsupported format, got %d", http.StatusBadRequest, rr.Code)
+	}
+}
+
+// Test the message history of a rocket in JSON and CSV
+func TestGetRocketEvents(t *testing.T) {
+	handler := api.NewAPIHandler()
+	t.Cleanup(handler.Webhooks.Close)
+	rocketID := "history-rocket"
+
+	handler.Repository.ProcessMessage(createTestMessage(rocketID, 1, models.MessageTypeRocketLaunched))
+	handler.Repository.ProcessMessage(createTestMessage(rocketID, 3, models.MessageTypeRocketExploded))
+	handler.Repository.ProcessMessage(createTestMessage(rocketID, 4, models.MessageTypeRocketSpeedIncreased))
+	handler.Repository.ProcessMessage(createTestMessage(rocketID, 2, models.MessageTypeRocketSpeedIncreased))
+
+	getEvents := func(url string) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, url, nil)
+		req.SetPathValue("id", strings.Split(url, "/")[2])
+		handler.HandleGetRocketEvents(rr, req)
+		return rr
+	}
+
+	rr := getEvents("/rockets/" + rocketID + "/events")
+	var events []storage.MessageEvent
+	if err := json.NewDecoder(rr.Body).Decode(&events); err != nil {
+		t.Fatalf("Failed to decode events: %v", err)
+	}
+	if len(events) != 4 {
+		t.Fatalf("Expected 4 events, got %+v", events)
+	}
+	for i, event := range events {
+		if event.MessageNumber != i+1 {
+			t.Errorf("Expected events in message order, got %d at %d", event.MessageNumber, i)
+		}
+	}
+	if events[1].Speed != 1500 || events[2].Status != "exploded" {
+		t.Errorf("Expected the state after each message, got %+v", events)
+	}
+	if events[3].Outcome != storage.OutcomeIgnored || events[3].Reason == "" {
+		t.Errorf("Expected the speed change after the explosion to be ignored, got %+v", events[3])
+	}
+
+	rr = getEvents("/rockets/" + rocketID + "/events?format=csv")
+	rows, err := csv.NewReader(rr.Body).ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to parse CSV: %v", err)
+	}
+	if len(rows) != 5 || rows[0][0] != "messageNumber" || rows[4][4] != string(storage.OutcomeIgnored) {
+		t.Errorf("Unexpected CSV: %v", rows)
+	}
+
+	if rr := getEvents("/rockets/unknown-rocket/events"); rr.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d for an unknown rocket, got %d", http.StatusNotFound, rr.Code)
+	}
+}
@@ -0,0 +1,240 @@
+package test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"lunar-backend-challenge/internal/models"
+	"lunar-backend-challenge/internal/registry"
+	"lunar-backend-challenge/internal/storage"
+	"lunar-backend-challenge/internal/validation"
+)
+
+// Fuzz targets for everything between a request body and the rocket state. Without -fuzz they run
+// their seed corpus as regular tests; run one with e.g. go test ./test -run '^$' -fuzz FuzzRepositorySequence.
+
+// fuzzMessageTypes are the message types the repository sequence fuzzer can choose from
+var fuzzMessageTypes = []string{
+	models.MessageTypeRocketLaunched,
+	models.MessageTypeRocketSpeedIncreased,
+	models.MessageTypeRocketSpeedDecreased,
+	models.MessageTypeRocketExploded,
+	models.MessageTypeRocketMissionChanged,
+}
+
+// fuzzRocketIDs are the rockets of the repository sequence fuzzer
+var fuzzRocketIDs = []string{"fuzz-rocket-1", "fuzz-rocket-2"}
+
+// fixtureMessages returns the test fixture of every message type, numbered in order
+func fixtureMessages(rocketID string) []*models.RocketMessage {
+	messages := make([]*models.RocketMessage, len(fuzzMessageTypes))
+	for i, messageType := range fuzzMessageTypes {
+		messages[i] = createTestMessage(rocketID, i+1, messageType)
+	}
+	return messages
+}
+
+// encodeOperation encodes a message for FuzzRepositorySequence, see decodeOperations
+func encodeOperation(rocket, number int, messageType string, amount int16) []byte {
+	typeIndex := 0
+	for i, fuzzType := range fuzzMessageTypes {
+		if fuzzType == messageType {
+			typeIndex = i
+		}
+	}
+	return []byte{byte(typeIndex<<1 | rocket), byte(number - 1), byte(uint16(amount) >> 8), byte(amount)}
+}
+
+// decodeOperations decodes fuzz input into messages, four bytes each: the rocket and message type,
+// the message number, and a speed or speed change. Message times follow the message numbers.
+func decodeOperations(data []byte, start time.Time) []*models.RocketMessage {
+	var messages []*models.RocketMessage
+	for ; len(data) >= 4; data = data[4:] {
+		rocketID := fuzzRocketIDs[int(data[0]&1)]
+		messageType := fuzzMessageTypes[int(data[0]>>1)%len(fuzzMessageTypes)]
+		number := int(data[1])%16 + 1
+		amount := int(int16(uint16(data[2])<<8 | uint16(data[3])))
+
+		msg := createTestMessage(rocketID, number, messageType)
+		msg.Metadata.MessageTime = start.Add(time.Duration(number) * time.Second)
+		switch messageType {
+		case models.MessageTypeRocketLaunched:
+			msg.Message.LaunchSpeed = amount
+		case models.MessageTypeRocketSpeedIncreased, models.MessageTypeRocketSpeedDecreased:
+			msg.Message.By = amount
+		}
+		messages = append(messages, msg)
+	}
+	return messages
+}
+
+// checkRepositoryInvariants fails t if a rocket has a negative speed, went back in its sequence
+// since lastProcessed, or has a message number both processed and pending. It updates lastProcessed.
+func checkRepositoryInvariants(t *testing.T, repository *storage.RocketRepository, rocketIDs []string, lastProcessed map[string]int) {
+	t.Helper()
+	for _, rocketID := range rocketIDs {
+		processed, pending := repository.GetDebugInfo(rocketID)
+		rocket, exists := repository.GetRocket(rocketID)
+		if !exists {
+			if processed != 0 {
+				t.Fatalf("Rocket %s does not exist but has %d processed messages", rocketID, processed)
+			}
+			continue
+		}
+
+		if rocket.Speed < 0 {
+			t.Fatalf("Rocket %s has negative speed %d", rocketID, rocket.Speed)
+		}
+		if rocket.LastProcessedMessageNumber < lastProcessed[rocketID] {
+			t.Fatalf("Rocket %s went back from message %d to %d", rocketID, lastProcessed[rocketID], rocket.LastProcessedMessageNumber)
+		}
+		lastProcessed[rocketID] = rocket.LastProcessedMessageNumber
+
+		// Messages are processed in sequence, so the processed numbers are 1 to the last one
+		if processed != rocket.LastProcessedMessageNumber {
+			t.Fatalf("Rocket %s has %d processed messages, but processed up to message %d", rocketID, processed, rocket.LastProcessedMessageNumber)
+		}
+		for _, number := range pending {
+			if number <= rocket.LastProcessedMessageNumber {
+				t.Fatalf("Rocket %s has message %d pending, but processed up to message %d", rocketID, number, rocket.LastProcessedMessageNumber)
+			}
+		}
+	}
+}
+
+// Fuzz decoding request bodies: valid messages must survive a round trip and be safe to process
+func FuzzDecodeMessage(f *testing.F) {
+	for _, msg := range fixtureMessages("fuzz-rocket") {
+		body, err := json.Marshal(msg)
+		if err != nil {
+			f.Fatalf("Failed to encode fixture: %v", err)
+		}
+		f.Add(body)
+	}
+	f.Add([]byte("{not json"))
+	f.Add([]byte{'{', 0xff, '}'})
+	f.Add([]byte(`{"metadata":{"channel":"ab","messageNumber":-1},"message":[]}`))
+
+	f.Fuzz(func(t *testing.T, body []byte) {
+		msg, err := registry.DecodeMessage(body)
+		if err != nil {
+			return
+		}
+		if msg == nil {
+			t.Fatal("Expected a message or an error")
+		}
+		if validation.ValidateRocketMessage(msg) != nil {
+			return
+		}
+
+		encoded, err := json.Marshal(msg)
+		if err != nil {
+			t.Fatalf("Failed to encode valid message: %v", err)
+		}
+		decoded, err := registry.DecodeMessage(encoded)
+		if err != nil {
+			t.Fatalf("Failed to decode encoded message %s: %v", encoded, err)
+		}
+		decoded.Message.Raw, msg.Message.Raw = nil, nil
+		if decoded.GetChannel() != msg.GetChannel() || decoded.GetMessageNumber() != msg.GetMessageNumber() || decoded.GetMessageType() != msg.GetMessageType() ||
+			!decoded.GetMessageTime().Equal(msg.GetMessageTime()) || !reflect.DeepEqual(decoded.Message, msg.Message) {
+			t.Fatalf("Message changed in a round trip:\nbefore %+v\nafter  %+v", msg, decoded)
+		}
+
+		// Process the message on its own and after a launch
+		rocketIDs := []string{msg.GetChannel()}
+		for _, launched := range []bool{false, true} {
+			repository := storage.NewRocketRepository()
+			lastProcessed := map[string]int{}
+			if launched && msg.GetMessageNumber() > 1 {
+				launch := createTestMessage(msg.GetChannel(), 1, models.MessageTypeRocketLaunched)
+				launch.Metadata.MessageTime = msg.GetMessageTime()
+				repository.Process(launch)
+				checkRepositoryInvariants(t, repository, rocketIDs, lastProcessed)
+			}
+			repository.Process(msg)
+			checkRepositoryInvariants(t, repository, rocketIDs, lastProcessed)
+		}
+	})
+}
+
+// Fuzz validation: whatever it accepts has the fields the repository relies on
+func FuzzValidateMessage(f *testing.F) {
+	for _, msg := range fixtureMessages("fuzz-rocket") {
+		f.Add(msg.GetChannel(), msg.GetMessageNumber(), msg.GetMessageType(), msg.GetMessageTime().UnixMilli(),
+			msg.Message.Type, msg.Message.Mission, msg.Message.LaunchSpeed, msg.Message.By, msg.Message.Reason, msg.Message.NewMission)
+	}
+
+	f.Fuzz(func(t *testing.T, channel string, number int, messageType string, unixMilli int64,
+		rocketType, mission string, launchSpeed, by int, reason, newMission string) {
+		msg := &models.RocketMessage{}
+		msg.Metadata.Channel = channel
+		msg.Metadata.MessageNumber = number
+		msg.Metadata.MessageType = messageType
+		if unixMilli != 0 {
+			msg.Metadata.MessageTime = time.UnixMilli(unixMilli)
+		}
+		msg.Message = models.MessageContent{Type: rocketType, Mission: mission, LaunchSpeed: launchSpeed, By: by, Reason: reason, NewMission: newMission}
+
+		if validation.ValidateRocketMessage(msg) != nil {
+			return
+		}
+		if channel == "" || number <= 0 || msg.Metadata.MessageTime.IsZero() {
+			t.Fatalf("Accepted message without channel, number or time: %+v", msg)
+		}
+		if _, exists := registry.Lookup(messageType); !exists {
+			t.Fatalf("Accepted message of unknown type %q", messageType)
+		}
+		switch messageType {
+		case models.MessageTypeRocketLaunched:
+			if rocketType == "" || mission == "" || launchSpeed < 0 {
+				t.Fatalf("Accepted launch without type, mission or speed: %+v", msg.Message)
+			}
+		case models.MessageTypeRocketSpeedIncreased, models.MessageTypeRocketSpeedDecreased:
+			if by <= 0 {
+				t.Fatalf("Accepted speed change by %d", by)
+			}
+		case models.MessageTypeRocketExploded:
+			if reason == "" {
+				t.Fatal("Accepted explosion without reason")
+			}
+		case models.MessageTypeRocketMissionChanged:
+			if newMission == "" {
+				t.Fatal("Accepted mission change without mission")
+			}
+		}
+	})
+}
+
+// Fuzz sequences of messages of two rockets, in any order and with any duplicates
+func FuzzRepositorySequence(f *testing.F) {
+	// The message sequences of the repository tests
+	f.Add(append(encodeOperation(0, 1, models.MessageTypeRocketLaunched, 1000),
+		encodeOperation(0, 1, models.MessageTypeRocketLaunched, 1000)...))
+	f.Add(append(append(encodeOperation(0, 1, models.MessageTypeRocketLaunched, 1000),
+		encodeOperation(0, 3, models.MessageTypeRocketSpeedIncreased, 500)...),
+		encodeOperation(0, 2, models.MessageTypeRocketSpeedDecreased, 300)...))
+	var fixtures []byte
+	for i, msg := range fixtureMessages(fuzzRocketIDs[1]) {
+		fixtures = append(encodeOperation(1, i+1, msg.GetMessageType(), int16(msg.Message.LaunchSpeed+msg.Message.By)), fixtures...)
+	}
+	f.Add(fixtures)
+	f.Add(append(encodeOperation(0, 1, models.MessageTypeRocketLaunched, 1000),
+		encodeOperation(0, 2, models.MessageTypeRocketSpeedDecreased, 5000)...))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		start := time.Now().Add(-time.Hour).Truncate(time.Second)
+		repository := storage.NewRocketRepository()
+		lastProcessed := map[string]int{}
+		for _, msg := range decodeOperations(data, start) {
+			// The handler only passes on valid messages
+			if validation.ValidateRocketMessage(msg) != nil {
+				continue
+			}
+			repository.Process(msg)
+			checkRepositoryInvariants(t, repository, fuzzRocketIDs, lastProcessed)
+		}
+	})
+}
@@ -0,0 +1,184 @@
+package test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/99designs/gqlgen/client"
+
+	"lunar-backend-challenge/internal/graphapi"
+	"lunar-backend-challenge/internal/models"
+	"lunar-backend-challenge/internal/storage"
+)
+
+// createGraphQLClient serves the GraphQL endpoint for a repository
+func createGraphQLClient(repository *storage.RocketRepository, options graphapi.Options) *client.Client {
+	return client.New(graphapi.NewHandler(repository, options))
+}
+
+// Test selecting a rocket with its launches and debug info in one query
+func TestGraphQLRocket(t *testing.T) {
+	repository := storage.NewRocketRepository()
+	c := createGraphQLClient(repository, graphapi.DefaultOptions())
+	rocketID := "graphql-rocket"
+
+	repository.ProcessMessage(createTestMessage(rocketID, 1, models.MessageTypeRocketLaunched))
+	repository.ProcessMessage(createTestMessage(rocketID, 2, models.MessageTypeRocketSpeedIncreased))
+	repository.ProcessMessage(createTestMessage(rocketID, 4, models.MessageTypeRocketExploded))
+
+	var response struct {
+		Rocket struct {
+			ID       string
+			Speed    int
+			Mission  string
+			Launches []struct {
+				Generation int
+				PeakSpeed  int
+			}
+			Debug struct {
+				LastProcessedMessage  int
+				PendingMessageNumbers []int
+			}
+		}
+		Missing *struct{ ID string }
+	}
+	err := c.Post(`query($id: ID!) {
+		rocket(id: $id) { id speed mission launches { generation peakSpeed } debug { lastProcessedMessage pendingMessageNumbers } }
+		missing: rocket(id: "unknown-rocket") { id }
+	}`, &response, client.Var("id", rocketID))
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+
+	rocket := response.Rocket
+	if rocket.ID != rocketID || rocket.Speed != 1500 || rocket.Mission != "Test Mission" {
+		t.Errorf("Unexpected rocket: %+v", rocket)
+	}
+	if len(rocket.Launches) != 1 || rocket.Launches[0].PeakSpeed != 1500 {
+		t.Errorf("Expected one launch peaking at 1500, got %+v", rocket.Launches)
+	}
+	if rocket.Debug.LastProcessedMessage != 2 || len(rocket.Debug.PendingMessageNumbers) != 1 || rocket.Debug.PendingMessageNumbers[0] != 4 {
+		t.Errorf("Unexpected debug info: %+v", rocket.Debug)
+	}
+	if response.Missing != nil {
+		t.Errorf("Expected null for an unknown rocket, got %+v", response.Missing)
+	}
+}
+
+// Test listing rockets with filtering, sorting and a limit, and listing missions
+func TestGraphQLRocketsAndMissions(t *testing.T) {
+	repository := storage.NewRocketRepository()
+	c := createGraphQLClient(repository, graphapi.DefaultOptions())
+
+	for i, speed := range []int{300, 100, 200} {
+		msg := createTestMessage(string(rune('a'+i))+"-rocket", 1, models.MessageTypeRocketLaunched)
+		msg.Message.LaunchSpeed = speed
+		repository.ProcessMessage(msg)
+	}
+	other := createTestMessage("d-rocket", 1, models.MessageTypeRocketLaunched)
+	other.Message.Mission = "APOLLO"
+	repository.ProcessMessage(other)
+
+	var response struct {
+		Rockets []struct {
+			ID    string
+			Speed int
+		}
+		Missions []struct {
+			Name               string
+			CurrentRocketCount int
+		}
+	}
+	err := c.Post(`{
+		rockets(filter: {mission: "test mission"}, sortBy: SPEED, sortOrder: DESC, limit: 2) { id speed }
+		missions { name currentRocketCount }
+	}`, &response)
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+
+	if len(response.Rockets) != 2 || response.Rockets[0].ID != "a-rocket" || response.Rockets[1].ID != "c-rocket" {
+		t.Errorf("Expected the two fastest Test Mission rockets, got %+v", response.Rockets)
+	}
+	if len(response.Missions) != 2 {
+		t.Errorf("Expected 2 missions, got %+v", response.Missions)
+	}
+
+	err = c.Post(`{ rockets(filter: {status: "hovering"}) { id } }`, &response)
+	if err == nil || !strings.Contains(err.Error(), "invalid status filter") {
+		t.Errorf("Expected an invalid status error, got %v", err)
+	}
+}
+
+// Test that queries above the complexity limit are refused
+func TestGraphQLComplexityLimit(t *testing.T) {
+	repository := storage.NewRocketRepository()
+	c := createGraphQLClient(repository, graphapi.Options{MaxComplexity: 100})
+
+	var response struct {
+		Rockets []struct{ ID string }
+	}
+	// Without a limit the list counts DefaultListSize entries
+	err := c.Post(`{ rockets { id type speed rocket { launches { peakSpeed } } } }`, &response)
+	if err == nil || !strings.Contains(err.Error(), "complexity") {
+		t.Errorf("Expected the query to exceed the complexity limit, got %v", err)
+	}
+
+	if err := c.Post(`{ rockets(limit: 10) { id type speed } }`, &response); err != nil {
+		t.Errorf("Expected a limited query to be accepted, got %v", err)
+	}
+}
+
+// Test that subscribers receive the changes of the rocket they subscribed to
+func TestGraphQLSubscription(t *testing.T) {
+	repository := storage.NewRocketRepository()
+	c := createGraphQLClient(repository, graphapi.DefaultOptions())
+
+	subscription := c.Websocket(`subscription { rocketChanged(rocketId: "watched-rocket") { kind rocketId messageType messageNumber after { speed } } }`)
+	defer subscription.Close()
+
+	type change struct {
+		RocketChanged struct {
+			Kind          string
+			RocketID      string
+			MessageType   string
+			MessageNumber int
+			After         struct{ Speed int }
+		}
+	}
+	changes := make(chan change)
+	go func() {
+		var response change
+		if err := subscription.Next(&response); err == nil {
+			changes <- response
+		}
+		close(changes)
+	}()
+
+	// The subscription starts asynchronously, so keep sending until a change arrives
+	repository.ProcessMessage(createTestMessage("other-rocket", 1, models.MessageTypeRocketLaunched))
+	timeout := time.After(5 * time.Second)
+	for number := 1; ; number++ {
+		messageType := models.MessageTypeRocketSpeedIncreased
+		if number == 1 {
+			messageType = models.MessageTypeRocketLaunched
+		}
+		repository.ProcessMessage(createTestMessage("watched-rocket", number, messageType))
+
+		select {
+		case response, ok := <-changes:
+			if !ok {
+				t.Fatal("Subscription ended without a change")
+			}
+			got := response.RocketChanged
+			if got.Kind != storage.ChangeApplied || got.RocketID != "watched-rocket" || got.MessageNumber < 1 || got.After.Speed < 1000 {
+				t.Errorf("Unexpected change: %+v", got)
+			}
+			return
+		case <-time.After(20 * time.Millisecond):
+		case <-timeout:
+			t.Fatal("Timed out waiting for a change")
+		}
+	}
+}
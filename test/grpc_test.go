@@ -0,0 +1,266 @@
+package test
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"lunar-backend-challenge/internal/deadletter"
+	"lunar-backend-challenge/internal/grpcapi"
+	"lunar-backend-challenge/internal/grpcapi/rocketsv1"
+	"lunar-backend-challenge/internal/models"
+	"lunar-backend-challenge/internal/storage"
+)
+
+// createGRPCClient serves the gRPC API for a repository over an in-memory connection
+func createGRPCClient(t *testing.T, repository *storage.RocketRepository, deadLetters *deadletter.Store) rocketsv1.RocketServiceClient {
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	rocketsv1.RegisterRocketServiceServer(server, grpcapi.NewServer(repository, deadLetters))
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return rocketsv1.NewRocketServiceClient(conn)
+}
+
+// createProtoMessage creates a protobuf rocket message for testing
+func createProtoMessage(channel string, messageNumber int64, messageType string, content *rocketsv1.MessageContent) *rocketsv1.RocketMessage {
+	return &rocketsv1.RocketMessage{
+		Metadata: &rocketsv1.Metadata{
+			Channel:       channel,
+			MessageNumber: messageNumber,
+			MessageTime:   timestamppb.Now(),
+			MessageType:   messageType,
+		},
+		Message: content,
+	}
+}
+
+// Test ingesting single messages and reading the rocket back
+func TestGRPCIngestAndGetRocket(t *testing.T) {
+	repository := storage.NewRocketRepository()
+	deadLetters := deadletter.NewStore(deadletter.DefaultCapacity)
+	client := createGRPCClient(t, repository, deadLetters)
+	ctx := context.Background()
+	rocketID := "193270a9-c9cf-404a-8f83-838e71d9ae67"
+
+	response, err := client.IngestMessage(ctx, &rocketsv1.IngestMessageRequest{
+		Message: createProtoMessage(rocketID, 1, models.MessageTypeRocketLaunched,
+			&rocketsv1.MessageContent{Type: "Falcon-9", LaunchSpeed: 500, Mission: "ARTEMIS"}),
+	})
+	if err != nil {
+		t.Fatalf("Failed to ingest launch: %v", err)
+	}
+	if response.GetOutcome() != string(storage.OutcomeApplied) || response.GetRocketId() != rocketID {
+		t.Errorf("Expected the launch to be applied, got %+v", response)
+	}
+
+	// Payloads given as JSON go through the same decoding as POST /messages
+	_, err = client.IngestMessage(ctx, &rocketsv1.IngestMessageRequest{
+		Message: createProtoMessage(rocketID, 2, models.MessageTypeRocketSpeedIncreased,
+			&rocketsv1.MessageContent{PayloadJson: `{"by": 300}`}),
+	})
+	if err != nil {
+		t.Fatalf("Failed to ingest speed increase: %v", err)
+	}
+
+	rocket, err := client.GetRocket(ctx, &rocketsv1.GetRocketRequest{Id: rocketID})
+	if err != nil {
+		t.Fatalf("Failed to get rocket: %v", err)
+	}
+	if state := rocket.GetRocket(); state.GetSpeed() != 800 || state.GetMission() != "ARTEMIS" || state.GetType() != "Falcon-9" {
+		t.Errorf("Unexpected rocket state: %+v", state)
+	}
+
+	_, err = client.GetRocket(ctx, &rocketsv1.GetRocketRequest{Id: "non-existent"})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("Expected NotFound for an unknown rocket, got %v", err)
+	}
+
+	_, err = client.IngestMessage(ctx, &rocketsv1.IngestMessageRequest{
+		Message: createProtoMessage(rocketID, 3, "RocketTeleported", &rocketsv1.MessageContent{}),
+	})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument for an unknown message type, got %v", err)
+	}
+	if len(deadLetters.List(deadletter.Filter{})) != 1 {
+		t.Errorf("Expected the invalid message to be dead-lettered")
+	}
+}
+
+// Test that speeds and message numbers beyond the int32 range round-trip
+func TestGRPCLargeValues(t *testing.T) {
+	repository := storage.NewRocketRepository()
+	client := createGRPCClient(t, repository, nil)
+	ctx := context.Background()
+	rocketID := "fast-rocket"
+
+	for _, msg := range []*rocketsv1.RocketMessage{
+		createProtoMessage(rocketID, 1, models.MessageTypeRocketLaunched,
+			&rocketsv1.MessageContent{Type: "Falcon-9", LaunchSpeed: 3_000_000_000, Mission: "ARTEMIS"}),
+		createProtoMessage(rocketID, 2, models.MessageTypeRocketSpeedIncreased, &rocketsv1.MessageContent{By: 2_000_000_000}),
+	} {
+		if _, err := client.IngestMessage(ctx, &rocketsv1.IngestMessageRequest{Message: msg}); err != nil {
+			t.Fatalf("Failed to ingest message: %v", err)
+		}
+	}
+	rocket, err := client.GetRocket(ctx, &rocketsv1.GetRocketRequest{Id: rocketID})
+	if err != nil {
+		t.Fatalf("Failed to get rocket: %v", err)
+	}
+	if speed := rocket.GetRocket().GetSpeed(); speed != 5_000_000_000 {
+		t.Errorf("Expected speed 5000000000, got %d", speed)
+	}
+
+	response, err := client.IngestMessage(ctx, &rocketsv1.IngestMessageRequest{
+		Message: createProtoMessage(rocketID, 4_000_000_000, models.MessageTypeRocketSpeedIncreased, &rocketsv1.MessageContent{By: 1}),
+	})
+	if err != nil {
+		t.Fatalf("Failed to ingest message: %v", err)
+	}
+	if response.GetMessageNumber() != 4_000_000_000 || response.GetOutcome() != string(storage.OutcomeBuffered) {
+		t.Errorf("Expected message 4000000000 to be buffered, got %+v", response)
+	}
+}
+
+// Test client-streaming ingestion reports outcomes and per-message errors
+func TestGRPCIngestStream(t *testing.T) {
+	repository := storage.NewRocketRepository()
+	client := createGRPCClient(t, repository, nil)
+	rocketID := "streamed-rocket"
+
+	stream, err := client.IngestStream(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to open stream: %v", err)
+	}
+	messages := []*rocketsv1.RocketMessage{
+		createProtoMessage(rocketID, 1, models.MessageTypeRocketLaunched,
+			&rocketsv1.MessageContent{Type: "Falcon-9", LaunchSpeed: 500, Mission: "ARTEMIS"}),
+		createProtoMessage(rocketID, 3, models.MessageTypeRocketSpeedIncreased, &rocketsv1.MessageContent{By: 100}),
+		createProtoMessage(rocketID, 2, models.MessageTypeRocketSpeedIncreased, &rocketsv1.MessageContent{By: 100}),
+		createProtoMessage(rocketID, 2, models.MessageTypeRocketSpeedIncreased, &rocketsv1.MessageContent{By: 100}),
+		createProtoMessage("", 4, models.MessageTypeRocketSpeedIncreased, &rocketsv1.MessageContent{By: 100}),
+	}
+	for _, msg := range messages {
+		if err := stream.Send(&rocketsv1.IngestStreamRequest{Message: msg}); err != nil {
+			t.Fatalf("Failed to send message: %v", err)
+		}
+	}
+	response, err := stream.CloseAndRecv()
+	if err != nil {
+		t.Fatalf("Failed to close stream: %v", err)
+	}
+
+	if response.GetReceived() != 5 || response.GetAccepted() != 4 || response.GetRejected() != 1 {
+		t.Errorf("Unexpected counts: %+v", response)
+	}
+	outcomes := response.GetOutcomes()
+	if outcomes[string(storage.OutcomeApplied)] != 2 || outcomes[string(storage.OutcomeBuffered)] != 1 || outcomes[string(storage.OutcomeDuplicate)] != 1 {
+		t.Errorf("Unexpected outcomes: %v", outcomes)
+	}
+	if len(response.GetErrors()) != 1 || response.GetErrors()[0].GetIndex() != 4 {
+		t.Errorf("Expected an error for the message without channel, got %+v", response.GetErrors())
+	}
+
+	if rocket, _ := repository.GetRocket(rocketID); rocket.Speed != 700 {
+		t.Errorf("Expected speed 700, got %d", rocket.Speed)
+	}
+}
+
+// Test listing rockets with the REST sort options and filters
+func TestGRPCListRockets(t *testing.T) {
+	repository := storage.NewRocketRepository()
+	client := createGRPCClient(t, repository, nil)
+	ctx := context.Background()
+
+	for i, speed := range []int{300, 100, 200} {
+		msg := createTestMessage(string(rune('a'+i))+"-rocket", 1, models.MessageTypeRocketLaunched)
+		msg.Message.LaunchSpeed = speed
+		repository.ProcessMessage(msg)
+	}
+
+	response, err := client.ListRockets(ctx, &rocketsv1.ListRocketsRequest{
+		SortBy:    rocketsv1.SortField_SORT_FIELD_SPEED,
+		SortOrder: rocketsv1.SortOrder_SORT_ORDER_DESC,
+	})
+	if err != nil {
+		t.Fatalf("Failed to list rockets: %v", err)
+	}
+	var ids []string
+	for _, rocket := range response.GetRockets() {
+		ids = append(ids, rocket.GetId())
+	}
+	if len(ids) != 3 || ids[0] != "a-rocket" || ids[1] != "c-rocket" || ids[2] != "b-rocket" {
+		t.Errorf("Expected rockets sorted by speed descending, got %v", ids)
+	}
+
+	_, err = client.ListRockets(ctx, &rocketsv1.ListRocketsRequest{Status: "hovering"})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument for an unknown status, got %v", err)
+	}
+	_, err = client.ListRockets(ctx, &rocketsv1.ListRocketsRequest{SortBy: rocketsv1.SortField(99)})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument for an unknown sort field, got %v", err)
+	}
+}
+
+// Test that watchers receive the state changes matching their filters
+func TestGRPCWatchRockets(t *testing.T) {
+	repository := storage.NewRocketRepository()
+	client := createGRPCClient(t, repository, nil)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stream, err := client.WatchRockets(ctx, &rocketsv1.WatchRocketsRequest{RocketId: "watched-rocket"})
+	if err != nil {
+		t.Fatalf("Failed to watch rockets: %v", err)
+	}
+
+	// The watcher is registered once the server handles the call, so keep sending until an event arrives
+	events := make(chan *rocketsv1.RocketEvent)
+	go func() {
+		response, err := stream.Recv()
+		if err == nil {
+			events <- response.GetEvent()
+		}
+		close(events)
+	}()
+
+	repository.ProcessMessage(createTestMessage("other-rocket", 1, models.MessageTypeRocketLaunched))
+	for number := 1; ; number++ {
+		messageType := models.MessageTypeRocketSpeedIncreased
+		if number == 1 {
+			messageType = models.MessageTypeRocketLaunched
+		}
+		repository.ProcessMessage(createTestMessage("watched-rocket", number, messageType))
+
+		select {
+		case event, ok := <-events:
+			if !ok {
+				t.Fatal("Watch stream ended without an event")
+			}
+			if event.GetRocketId() != "watched-rocket" || event.GetAfter() == nil || event.GetOccurredAt() == nil {
+				t.Errorf("Unexpected event: %+v", event)
+			}
+			return
+		case <-time.After(20 * time.Millisecond):
+		case <-ctx.Done():
+			t.Fatal("Timed out waiting for an event")
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"lunar-backend-challenge/internal/api"
+	"lunar-backend-challenge/internal/deadletter"
+	"lunar-backend-challenge/internal/importer"
+	"lunar-backend-challenge/internal/ingest"
+	"lunar-backend-challenge/internal/models"
+	"lunar-backend-challenge/internal/storage"
+)
+
+// createCapture builds an NDJSON capture with a blank line, an invalid line, an invalid message and a repeat
+func createCapture(t *testing.T, rocketID string) string {
+	var lines []string
+	for _, msg := range []*models.RocketMessage{
+		createTestMessage(rocketID, 1, models.MessageTypeRocketLaunched),
+		createTestMessage(rocketID, 3, models.MessageTypeRocketSpeedDecreased),
+		createTestMessage(rocketID, 2, models.MessageTypeRocketSpeedIncreased),
+		createTestMessage(rocketID, 2, models.MessageTypeRocketSpeedIncreased),
+		createTestMessage("", 4, models.MessageTypeRocketSpeedIncreased),
+	} {
+		line, err := json.Marshal(msg)
+		if err != nil {
+			t.Fatalf("Failed to encode message: %v", err)
+		}
+		lines = append(lines, string(line))
+	}
+	lines = append(lines[:2], append([]string{"", "{not json"}, lines[2:]...)...)
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// Test importing a capture directly through a repository
+func TestImportIntoRepository(t *testing.T) {
+	repository := storage.NewRocketRepository()
+	deadLetters := deadletter.NewStore(deadletter.DefaultCapacity)
+	var progress strings.Builder
+	imp := &importer.Importer{
+		Sink:          importer.RepositorySink{Ingest: ingest.NewService(repository, deadLetters)},
+		Progress:      &progress,
+		ProgressEvery: 2,
+	}
+
+	report := importer.NewReport()
+	if err := imp.Import("capture.ndjson", strings.NewReader(createCapture(t, "imported-rocket")), report); err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+
+	if report.Lines != 6 {
+		t.Errorf("Expected 6 non-empty lines, got %d", report.Lines)
+	}
+	expected := map[string]int{"applied": 2, "buffered": 1, "duplicate": 1, "rejected": 2}
+	for outcome, count := range expected {
+		if report.Outcomes[outcome] != count {
+			t.Errorf("Expected %d %s, got %v", count, outcome, report.Outcomes)
+		}
+	}
+	if len(report.Failures) != 2 || report.Failures[0].Line != 4 || report.Failures[1].Line != 7 || report.Failures[0].Source != "capture.ndjson" {
+		t.Errorf("Expected failures on lines 4 and 7, got %+v", report.Failures)
+	}
+	if len(deadLetters.List(deadletter.Filter{})) != 2 {
+		t.Errorf("Expected the rejected lines to be dead-lettered")
+	}
+	if rocket, _ := repository.GetRocket("imported-rocket"); rocket.Speed != 1200 {
+		t.Errorf("Expected speed 1200, got %d", rocket.Speed)
+	}
+	if strings.Count(progress.String(), "\n") != 3 || !strings.Contains(progress.String(), "capture.ndjson: line 5, 4 lines") {
+		t.Errorf("Expected a progress report every 2 lines, got:\n%s", progress.String())
+	}
+}
+
+// Test that a dry run validates without applying anything
+func TestImportDryRun(t *testing.T) {
+	imp := &importer.Importer{Sink: importer.DryRunSink{}}
+	report := importer.NewReport()
+	if err := imp.Import("stdin", strings.NewReader(createCapture(t, "dry-rocket")), report); err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+
+	if report.Outcomes[importer.OutcomeValid] != 4 || report.Outcomes["rejected"] != 2 || len(report.Failures) != 2 {
+		t.Errorf("Unexpected report: %s", report)
+	}
+}
+
+// Test importing over HTTP into a running server, and stopping when it cannot be reached
+func TestImportOverHTTP(t *testing.T) {
+	handler := api.NewAPIHandler()
+	t.Cleanup(handler.Webhooks.Close)
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /messages", handler.HandleMessage)
+	server := httptest.NewServer(mux)
+
+	imp := &importer.Importer{Sink: importer.NewHTTPSink(server.URL + "/")}
+	report := importer.NewReport()
+	if err := imp.Import("capture.ndjson", strings.NewReader(createCapture(t, "posted-rocket")), report); err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+
+	if report.Outcomes["applied"] != 2 || report.Outcomes["rejected"] != 2 || len(report.Failures) != 2 {
+		t.Errorf("Unexpected report: %s", report)
+	}
+	if !strings.Contains(report.Failures[1].Error, "channel is required") {
+		t.Errorf("Expected the server's validation error, got %q", report.Failures[1].Error)
+	}
+	if rocket, exists := handler.Repository.GetRocket("posted-rocket"); !exists || rocket.Speed != 1200 {
+		t.Errorf("Expected the server to hold the imported rocket, got %+v", rocket)
+	}
+
+	server.Close()
+	err := imp.Import("capture.ndjson", strings.NewReader(createCapture(t, "posted-rocket")), importer.NewReport())
+	if err == nil || !strings.Contains(err.Error(), "line 1") {
+		t.Errorf("Expected the import to stop at line 1, got %v", err)
+	}
+}
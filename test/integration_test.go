@@ -27,10 +27,34 @@ func createTestServer() *httptest.Server {
 	mux.HandleFunc("POST /messages", apiHandler.HandleMessage)
 	mux.HandleFunc("GET /rockets", apiHandler.HandleGetRockets)
 	mux.HandleFunc("GET /rockets/{id}", apiHandler.HandleGetRocket)
+	mux.HandleFunc("GET /rockets/{id}/launches", apiHandler.HandleGetRocketLaunches)
+	mux.HandleFunc("GET /rockets/{id}/speed", apiHandler.HandleGetRocketSpeed)
+	mux.HandleFunc("GET /stats", apiHandler.HandleGetStats)
+	mux.HandleFunc("GET /missions", apiHandler.HandleGetMissions)
+	mux.HandleFunc("GET /missions/{name}", apiHandler.HandleGetMission)
+	mux.HandleFunc("GET /alerts", apiHandler.HandleGetAlerts)
 
 	// Debug routes
 	mux.HandleFunc("GET /debug/rockets", apiHandler.HandleDebugAll)
 	mux.HandleFunc("GET /debug/rockets/{id}", apiHandler.HandleDebugRocket)
+	mux.HandleFunc("GET /metrics", apiHandler.HandleMetrics)
+
+	// Admin routes
+	mux.HandleFunc("GET /admin/dead-letters", apiHandler.HandleListDeadLetters)
+	mux.HandleFunc("GET /admin/dead-letters/{id}", apiHandler.HandleGetDeadLetter)
+	mux.HandleFunc("POST /admin/dead-letters/{id}/replay", apiHandler.HandleReplayDeadLetter)
+	mux.HandleFunc("DELETE /admin/dead-letters/{id}", apiHandler.HandleDiscardDeadLetter)
+	mux.HandleFunc("GET /admin/rules", apiHandler.HandleListRules)
+	mux.HandleFunc("POST /admin/rules", apiHandler.HandleCreateRule)
+	mux.HandleFunc("GET /admin/rules/{id}", apiHandler.HandleGetRule)
+	mux.HandleFunc("PUT /admin/rules/{id}", apiHandler.HandlePutRule)
+	mux.HandleFunc("DELETE /admin/rules/{id}", apiHandler.HandleDeleteRule)
+	mux.HandleFunc("GET /admin/webhooks", apiHandler.HandleListWebhooks)
+	mux.HandleFunc("POST /admin/webhooks", apiHandler.HandleCreateWebhook)
+	mux.HandleFunc("GET /admin/webhooks/{id}", apiHandler.HandleGetWebhook)
+	mux.HandleFunc("DELETE /admin/webhooks/{id}", apiHandler.HandleDeleteWebhook)
+	mux.HandleFunc("GET /admin/webhooks/{id}/deliveries", apiHandler.HandleGetWebhookDeliveries)
+	mux.HandleFunc("POST /admin/webhooks/{id}/enable", apiHandler.HandleEnableWebhook)
 
 	// Health check endpoint
 	mux.HandleFunc("GET /health", func(w http.ResponseWriter, r *http.Request) {
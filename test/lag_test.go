@@ -0,0 +1,129 @@
+package test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"lunar-backend-challenge/internal/api"
+	"lunar-backend-challenge/internal/metrics"
+	"lunar-backend-challenge/internal/models"
+	"lunar-backend-challenge/internal/storage"
+)
+
+// Test that histogram samples are cumulative and written in the Prometheus format
+func TestHistogramSamples(t *testing.T) {
+	histogram := metrics.NewHistogram([]float64{1, 0.1})
+	for _, value := range []float64{0.05, 0.1, 0.5, 3} {
+		histogram.Observe(value)
+	}
+
+	registry := metrics.NewRegistry()
+	registry.Register(metrics.CollectorFunc(func() []metrics.Sample {
+		return histogram.Samples("test_seconds", "Test histogram", map[string]string{"rocket": "a"})
+	}))
+
+	var output strings.Builder
+	if err := registry.WritePrometheus(&output); err != nil {
+		t.Fatalf("Failed to write metrics: %v", err)
+	}
+	expected := `# HELP test_seconds Test histogram
+# TYPE test_seconds histogram
+test_seconds_bucket{le="0.1",rocket="a"} 2
+test_seconds_bucket{le="1",rocket="a"} 3
+test_seconds_bucket{le="+Inf",rocket="a"} 4
+test_seconds_sum{rocket="a"} 3.65
+test_seconds_count{rocket="a"} 4
+`
+	if output.String() != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, output.String())
+	}
+}
+
+// Test receive and apply lag of messages, including one held until an earlier message arrived
+func TestMessageLag(t *testing.T) {
+	start := time.Date(2024, 3, 14, 19, 39, 0, 0, time.UTC)
+	now := start
+	config := storage.DefaultConfig()
+	config.Now = func() time.Time { return now }
+	handler := api.NewAPIHandlerWithRepository(storage.NewRocketRepositoryWithConfig(config))
+	t.Cleanup(handler.Webhooks.Close)
+	rocketID := "lagging-rocket"
+
+	// Received 2s after it was sent and applied right away
+	now = start.Add(2 * time.Second)
+	handler.Repository.ProcessMessage(createTimedMessage(rocketID, 1, models.MessageTypeRocketLaunched, start))
+
+	// Message 3 is received 1s after it was sent but waits 5s for message 2
+	now = start.Add(11 * time.Second)
+	handler.Repository.ProcessMessage(createTimedMessage(rocketID, 3, models.MessageTypeRocketSpeedIncreased, start.Add(10*time.Second)))
+	now = start.Add(16 * time.Second)
+	handler.Repository.ProcessMessage(createTimedMessage(rocketID, 2, models.MessageTypeRocketSpeedIncreased, start.Add(16*time.Second)))
+
+	lag, exists := handler.Repository.GetLag(rocketID)
+	if !exists {
+		t.Fatal("Expected lag for the rocket")
+	}
+	if lag.Messages != 3 || lag.MaxReceiveLagSeconds != 2 || lag.MaxApplyLagSeconds != 5 ||
+		lag.LastReceiveLagSeconds != 1 || lag.LastApplyLagSeconds != 5 || lag.AverageReceiveLagSeconds != 1 {
+		t.Errorf("Unexpected lag: %+v", lag)
+	}
+	if !lag.LastReceivedAt.Equal(start.Add(11*time.Second)) || !lag.LastAppliedAt.Equal(start.Add(16*time.Second)) {
+		t.Errorf("Expected message 3 received at +11s and applied at +16s, got %+v", lag)
+	}
+
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/debug/rockets/"+rocketID, nil)
+	req.SetPathValue("id", rocketID)
+	handler.HandleDebugRocket(rr, req)
+
+	var debugInfo api.DebugInfo
+	if err := json.NewDecoder(rr.Body).Decode(&debugInfo); err != nil {
+		t.Fatalf("Failed to decode debug info: %v", err)
+	}
+	if debugInfo.Lag == nil || debugInfo.Lag.MaxApplyLagSeconds != 5 {
+		t.Errorf("Expected the lag in the debug info, got %+v", debugInfo.Lag)
+	}
+
+	rr = httptest.NewRecorder()
+	handler.HandleMetrics(rr, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	for _, line := range []string{
+		`rocket_message_receive_lag_seconds_bucket{le="1"} 2`,
+		`rocket_message_receive_lag_seconds_bucket{le="2.5"} 3`,
+		`rocket_message_apply_lag_seconds_bucket{le="2.5"} 2`,
+		`rocket_message_apply_lag_seconds_sum 5`,
+		`rocket_apply_lag_seconds_bucket{le="5",rocket="lagging-rocket"} 3`,
+		`rocket_receive_lag_seconds_count{rocket="lagging-rocket"} 3`,
+	} {
+		if !strings.Contains(rr.Body.String(), line) {
+			t.Errorf("Expected metrics to contain %q, got:\n%s", line, rr.Body.String())
+		}
+	}
+}
+
+// Test that processing leaves the caller's message unchanged, so a reused message gets a fresh receive time
+func TestLagDoesNotStampInput(t *testing.T) {
+	start := time.Date(2024, 3, 14, 19, 39, 0, 0, time.UTC)
+	now := start
+	config := storage.DefaultConfig()
+	config.Now = func() time.Time { return now }
+	repo := storage.NewRocketRepositoryWithConfig(config)
+
+	shared := createTimedMessage("reused-rocket", 1, models.MessageTypeRocketLaunched, start)
+	repo.ProcessMessage(shared)
+	if !shared.ReceivedAt.IsZero() || !shared.AppliedAt.IsZero() {
+		t.Errorf("Expected the message to be left unstamped, got received %v and applied %v", shared.ReceivedAt, shared.AppliedAt)
+	}
+
+	// The same message sent again as the launch of another rocket 10s later
+	now = start.Add(10 * time.Second)
+	shared.Metadata.Channel = "reused-rocket-2"
+	repo.ProcessMessage(shared)
+	lag, exists := repo.GetLag("reused-rocket-2")
+	if !exists || !lag.LastReceivedAt.Equal(now) || lag.LastReceiveLagSeconds != 10 {
+		t.Errorf("Expected a receive lag of 10s, got %+v", lag)
+	}
+}
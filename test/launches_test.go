@@ -0,0 +1,114 @@
+package test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"lunar-backend-challenge/internal/api"
+	"lunar-backend-challenge/internal/models"
+	"lunar-backend-challenge/internal/storage"
+)
+
+// Test that each launch starts a generation and records how the previous one ended
+func TestLaunchGenerations(t *testing.T) {
+	repo := storage.NewRocketRepository()
+	rocketID := "generations-rocket"
+
+	messages := []*models.RocketMessage{
+		createTestMessage(rocketID, 1, models.MessageTypeRocketLaunched),
+		createTestMessage(rocketID, 2, models.MessageTypeRocketSpeedIncreased),
+		createTestMessage(rocketID, 3, models.MessageTypeRocketSpeedDecreased),
+		createTestMessage(rocketID, 4, models.MessageTypeRocketExploded),
+		createRelaunchMessage(rocketID, 5),
+		createTestMessage(rocketID, 6, models.MessageTypeRocketSpeedDecreased),
+		createTestMessage(rocketID, 7, models.MessageTypeRocketLaunched),
+	}
+	for _, msg := range messages {
+		repo.ProcessMessage(msg)
+	}
+
+	rocket, _ := repo.GetRocket(rocketID)
+	if rocket.LaunchCount != 3 || len(rocket.Launches) != 3 {
+		t.Fatalf("Expected 3 launch generations, got count %d and %d entries", rocket.LaunchCount, len(rocket.Launches))
+	}
+
+	first := rocket.Launches[0]
+	if first.Generation != 1 || first.InitialSpeed != 1000 || first.PeakSpeed != 1500 || first.Mission != "Test Mission" {
+		t.Errorf("Unexpected first generation: %+v", first)
+	}
+	if first.EndReason != models.LaunchEndExploded || first.ExplosionReason != "Engine failure" || first.EndedAt == nil {
+		t.Errorf("Expected first generation to end by explosion, got %+v", first)
+	}
+	if !first.LaunchedAt.Equal(messages[0].GetMessageTime()) || !first.EndedAt.Equal(messages[3].GetMessageTime()) {
+		t.Errorf("Expected first generation to span messages 1 to 4, got %v to %v", first.LaunchedAt, first.EndedAt)
+	}
+
+	second := rocket.Launches[1]
+	if second.InitialSpeed != 2000 || second.PeakSpeed != 2000 || second.Mission != "Relaunch Mission" {
+		t.Errorf("Unexpected second generation: %+v", second)
+	}
+	if second.EndReason != models.LaunchEndSuperseded || second.ExplosionReason != "" {
+		t.Errorf("Expected second generation to be superseded, got %+v", second)
+	}
+
+	third := rocket.Launches[2]
+	if third.Generation != 3 || third.EndReason != "" || third.EndedAt != nil {
+		t.Errorf("Expected third generation to be in flight, got %+v", third)
+	}
+
+	if !rocket.CreatedAt.Equal(messages[0].GetMessageTime()) {
+		t.Errorf("Expected created time of first launch, got %v", rocket.CreatedAt)
+	}
+
+	// Returned state is a copy
+	rocket.Launches[0].PeakSpeed = 0
+	again, _ := repo.GetRocket(rocketID)
+	if again.Launches[0].PeakSpeed != 1500 {
+		t.Error("Expected launch history of the repository to be unaffected by changes to a copy")
+	}
+}
+
+// Test HandleGetRocketLaunches
+func TestHandleGetRocketLaunches(t *testing.T) {
+	handler := api.NewAPIHandler()
+	rocketID := "launches-http-rocket"
+
+	handler.Repository.ProcessMessage(createTestHTTPMessage(rocketID, 1, models.MessageTypeRocketLaunched))
+	handler.Repository.ProcessMessage(createTestHTTPMessage(rocketID, 2, models.MessageTypeRocketExploded))
+	handler.Repository.ProcessMessage(createTestHTTPMessage(rocketID, 3, models.MessageTypeRocketLaunched))
+
+	req := httptest.NewRequest(http.MethodGet, "/rockets/"+rocketID+"/launches", nil)
+	req.SetPathValue("id", rocketID)
+	rr := httptest.NewRecorder()
+
+	handler.HandleGetRocketLaunches(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, rr.Code)
+	}
+
+	var history api.LaunchHistory
+	if err := json.NewDecoder(rr.Body).Decode(&history); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	if history.RocketID != rocketID || history.LaunchCount != 2 || len(history.Launches) != 2 {
+		t.Errorf("Expected 2 launches for %s, got %+v", rocketID, history)
+	}
+	if history.Launches[0].EndReason != models.LaunchEndExploded {
+		t.Errorf("Expected first launch to have exploded, got %+v", history.Launches[0])
+	}
+
+	// Unknown rocket
+	req = httptest.NewRequest(http.MethodGet, "/rockets/missing-rocket/launches", nil)
+	req.SetPathValue("id", "missing-rocket")
+	rr = httptest.NewRecorder()
+
+	handler.HandleGetRocketLaunches(rr, req)
+
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d, got %d", http.StatusNotFound, rr.Code)
+	}
+}
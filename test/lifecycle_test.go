@@ -0,0 +1,160 @@
+package test
+
+import (
+	goerrors "errors"
+	"testing"
+
+	"lunar-backend-challenge/internal/lifecycle"
+	"lunar-backend-challenge/internal/models"
+	"lunar-backend-challenge/internal/sorting"
+	"lunar-backend-challenge/internal/storage"
+)
+
+// Test every status and event combination against the expected transition table
+func TestLifecycle_AllTransitions(t *testing.T) {
+	const illegal = ""
+
+	expected := map[string]map[lifecycle.Event]string{
+		models.RocketStatusPendingLaunch: {
+			lifecycle.EventLaunch:  models.RocketStatusActive,
+			lifecycle.EventUpdate:  illegal,
+			lifecycle.EventExplode: illegal,
+			lifecycle.EventLand:    illegal,
+			lifecycle.EventLose:    illegal,
+		},
+		models.RocketStatusActive: {
+			lifecycle.EventLaunch:  models.RocketStatusRelaunched,
+			lifecycle.EventUpdate:  models.RocketStatusActive,
+			lifecycle.EventExplode: models.RocketStatusExploded,
+			lifecycle.EventLand:    models.RocketStatusLanded,
+			lifecycle.EventLose:    models.RocketStatusLost,
+		},
+		models.RocketStatusRelaunched: {
+			lifecycle.EventLaunch:  models.RocketStatusRelaunched,
+			lifecycle.EventUpdate:  models.RocketStatusRelaunched,
+			lifecycle.EventExplode: models.RocketStatusExploded,
+			lifecycle.EventLand:    models.RocketStatusLanded,
+			lifecycle.EventLose:    models.RocketStatusLost,
+		},
+		models.RocketStatusExploded: {
+			lifecycle.EventLaunch:  models.RocketStatusRelaunched,
+			lifecycle.EventUpdate:  illegal,
+			lifecycle.EventExplode: illegal,
+			lifecycle.EventLand:    illegal,
+			lifecycle.EventLose:    illegal,
+		},
+		models.RocketStatusLanded: {
+			lifecycle.EventLaunch:  models.RocketStatusRelaunched,
+			lifecycle.EventUpdate:  illegal,
+			lifecycle.EventExplode: illegal,
+			lifecycle.EventLand:    illegal,
+			lifecycle.EventLose:    illegal,
+		},
+		models.RocketStatusLost: {
+			lifecycle.EventLaunch:  models.RocketStatusRelaunched,
+			lifecycle.EventUpdate:  illegal,
+			lifecycle.EventExplode: illegal,
+			lifecycle.EventLand:    illegal,
+			lifecycle.EventLose:    illegal,
+		},
+	}
+
+	if len(expected) != len(lifecycle.Statuses) {
+		t.Fatalf("Expected table covers %d statuses, lifecycle has %d", len(expected), len(lifecycle.Statuses))
+	}
+
+	for _, from := range lifecycle.Statuses {
+		for _, event := range lifecycle.Events {
+			want := expected[from][event]
+			got, err := lifecycle.Transition(from, event)
+
+			if want == illegal {
+				var transitionErr lifecycle.TransitionError
+				if !goerrors.As(err, &transitionErr) {
+					t.Errorf("%s + %s: expected TransitionError, got %v (to %q)", from, event, err, got)
+					continue
+				}
+				if transitionErr.From != from || transitionErr.Event != event || transitionErr.Reason == "" {
+					t.Errorf("%s + %s: unexpected error details %+v", from, event, transitionErr)
+				}
+				if lifecycle.CanTransition(from, event) {
+					t.Errorf("%s + %s: expected CanTransition to be false", from, event)
+				}
+				continue
+			}
+
+			if err != nil || got != want {
+				t.Errorf("%s + %s: expected %s, got %q (%v)", from, event, want, got, err)
+			}
+		}
+	}
+
+	if _, err := lifecycle.Transition("bogus", lifecycle.EventLaunch); err == nil {
+		t.Error("Expected transition from unknown status to fail")
+	}
+}
+
+// Test that the repository tracks the status through launch, explosion and relaunch
+func TestLifecycle_RepositoryStatus(t *testing.T) {
+	repo := storage.NewRocketRepository()
+	rocketID := "lifecycle-rocket"
+
+	steps := []struct {
+		msg     *models.RocketMessage
+		status  string
+		outcome storage.Outcome
+	}{
+		{createTestMessage(rocketID, 1, models.MessageTypeRocketLaunched), models.RocketStatusActive, storage.OutcomeApplied},
+		{createTestMessage(rocketID, 2, models.MessageTypeRocketSpeedIncreased), models.RocketStatusActive, storage.OutcomeApplied},
+		{createTestMessage(rocketID, 3, models.MessageTypeRocketExploded), models.RocketStatusExploded, storage.OutcomeApplied},
+		{createTestMessage(rocketID, 4, models.MessageTypeRocketExploded), models.RocketStatusExploded, storage.OutcomeIgnored},
+		{createTestMessage(rocketID, 5, models.MessageTypeRocketMissionChanged), models.RocketStatusExploded, storage.OutcomeIgnored},
+		{createRelaunchMessage(rocketID, 6), models.RocketStatusRelaunched, storage.OutcomeApplied},
+		{createTestMessage(rocketID, 7, models.MessageTypeRocketSpeedDecreased), models.RocketStatusRelaunched, storage.OutcomeApplied},
+	}
+
+	for _, step := range steps {
+		result := repo.Process(step.msg)
+		if result.Outcome != step.outcome {
+			t.Errorf("Message %d: expected outcome %s, got %s", step.msg.GetMessageNumber(), step.outcome, result.Outcome)
+		}
+		if result.Outcome == storage.OutcomeIgnored && result.Reason == "" {
+			t.Errorf("Message %d: expected a reason for the illegal transition", step.msg.GetMessageNumber())
+		}
+
+		rocket, _ := repo.GetRocket(rocketID)
+		if rocket.Status != step.status {
+			t.Errorf("Message %d: expected status %s, got %s", step.msg.GetMessageNumber(), step.status, rocket.Status)
+		}
+		if rocket.Exploded != (step.status == models.RocketStatusExploded) {
+			t.Errorf("Message %d: exploded flag %v does not match status %s", step.msg.GetMessageNumber(), rocket.Exploded, rocket.Status)
+		}
+	}
+
+	summaries := repo.GetAllRockets()
+	if len(summaries) != 1 || summaries[0].Status != models.RocketStatusRelaunched {
+		t.Errorf("Expected summary status %s, got %+v", models.RocketStatusRelaunched, summaries)
+	}
+}
+
+// Test sorting rockets by status
+func TestSortRockets_ByStatus(t *testing.T) {
+	rockets := []models.RocketSummary{
+		{ID: "rocket-1", Status: models.RocketStatusRelaunched},
+		{ID: "rocket-2", Status: models.RocketStatusActive},
+		{ID: "rocket-3", Status: models.RocketStatusExploded},
+		{ID: "rocket-0", Status: models.RocketStatusActive},
+	}
+
+	sorted := sorting.SortRockets(rockets, "status", "asc")
+	expectedIDs := []string{"rocket-0", "rocket-2", "rocket-3", "rocket-1"}
+	for i, id := range expectedIDs {
+		if sorted[i].ID != id {
+			t.Errorf("Position %d: expected %s, got %s", i, id, sorted[i].ID)
+		}
+	}
+
+	if !sorting.ValidateSortBy("status") {
+		t.Error("Expected status to be a valid sort field")
+	}
+}
@@ -0,0 +1,90 @@
+package test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"lunar-backend-challenge/internal/api"
+	"lunar-backend-challenge/internal/loadtest"
+	"lunar-backend-challenge/internal/simulator"
+)
+
+// Test the latency percentiles of a known distribution
+func TestLatencySummary(t *testing.T) {
+	latencies := &loadtest.Latencies{}
+	if summary := latencies.Summary(time.Second); summary.Requests != 0 || summary.P99 != 0 {
+		t.Errorf("Expected an empty summary, got %+v", summary)
+	}
+
+	for ms := 100; ms >= 1; ms-- {
+		var err error
+		if ms%25 == 0 {
+			err = context.DeadlineExceeded
+		}
+		latencies.Observe(time.Duration(ms)*time.Millisecond, err)
+	}
+	summary := latencies.Summary(2 * time.Second)
+	expected := loadtest.Summary{Requests: 100, Errors: 4, Throughput: 50, Mean: 50.5, P50: 50, P90: 90, P95: 95, P99: 99, Max: 100}
+	if summary != expected {
+		t.Errorf("Expected %+v, got %+v", expected, summary)
+	}
+}
+
+// Test a load test against a server, and comparing it with a baseline
+func TestLoadTestRun(t *testing.T) {
+	handler := api.NewAPIHandler()
+	t.Cleanup(handler.Webhooks.Close)
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /messages", handler.HandleMessage)
+	mux.HandleFunc("GET /rockets", handler.HandleGetRockets)
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	config := loadtest.Config{
+		Simulation:         simulator.Config{Rockets: 5, Messages: 200, Concurrency: 4, DuplicateRate: 0.1, ReorderWindow: 3, Seed: 7},
+		Preload:            20,
+		ListConcurrency:    2,
+		ListQuery:          url.Values{"sortBy": {"speed"}},
+		MeasureAllocations: true,
+	}
+	if err := config.Validate(); err != nil {
+		t.Fatalf("Invalid configuration: %v", err)
+	}
+	report, err := loadtest.Run(context.Background(), server.URL, config)
+	if err != nil {
+		t.Fatalf("Load test failed: %v", err)
+	}
+
+	if report.Ingest.Requests != report.Simulation.Sent || report.Simulation.Generated != 200 || report.Ingest.Errors != 0 {
+		t.Errorf("Expected every generated message to be posted, got %+v and %+v", report.Simulation, report.Ingest)
+	}
+	if report.List.Requests == 0 || report.List.Errors != 0 {
+		t.Errorf("Expected the rockets to be listed during the run, got %+v", report.List)
+	}
+	if report.Ingest.P50 > report.Ingest.P99 || report.Ingest.P99 > report.Ingest.Max || report.Ingest.Throughput <= 0 {
+		t.Errorf("Inconsistent latencies %+v", report.Ingest)
+	}
+	if report.Allocations == nil || report.Allocations.AllocsPerRequest <= 0 {
+		t.Errorf("Expected allocations to be measured, got %+v", report.Allocations)
+	}
+	if rockets := handler.Repository.GetAllRockets(); len(rockets) < 20+5 {
+		t.Errorf("Expected the preloaded and simulated rockets, got %d", len(rockets))
+	}
+
+	baseline := report
+	baseline.Ingest.P99 = report.Ingest.P99 / 2
+	deltas := loadtest.Compare(baseline, report)
+	if len(deltas) != 8 {
+		t.Fatalf("Expected all metrics to be compared, got %+v", deltas)
+	}
+	if p99 := deltas[2]; p99.Metric != "ingest p99 ms" || p99.Change != 1 || p99.Better {
+		t.Errorf("Expected ingest p99 to be twice as slow, got %+v", p99)
+	}
+	if throughput := deltas[0]; throughput.Change != 0 {
+		t.Errorf("Expected no throughput change, got %+v", throughput)
+	}
+}
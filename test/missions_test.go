@@ -0,0 +1,119 @@
+package test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"lunar-backend-challenge/internal/api"
+	"lunar-backend-challenge/internal/models"
+	"lunar-backend-challenge/internal/storage"
+)
+
+// createMissionChangeMessage creates a mission change message at the given time
+func createMissionChangeMessage(channel string, messageNumber int, mission string, messageTime time.Time) *models.RocketMessage {
+	msg := createTimedMessage(channel, messageNumber, models.MessageTypeRocketMissionChanged, messageTime)
+	msg.Message.NewMission = mission
+	return msg
+}
+
+// createMissionsRepository creates rockets moving between ARTEMIS and APOLLO
+func createMissionsRepository(start time.Time) *storage.RocketRepository {
+	repo := storage.NewRocketRepository()
+
+	// rocket-a: ARTEMIS from launch, APOLLO from +1m, explodes at +2m, relaunched on Artemis at +3m
+	launch := createLaunchMessage("rocket-a", 1, "Falcon-9", "ARTEMIS", 1000)
+	launch.Metadata.MessageTime = start
+	repo.ProcessMessage(launch)
+	repo.ProcessMessage(createMissionChangeMessage("rocket-a", 2, "APOLLO", start.Add(time.Minute)))
+	repo.ProcessMessage(createTimedMessage("rocket-a", 3, models.MessageTypeRocketExploded, start.Add(2*time.Minute)))
+	relaunch := createLaunchMessage("rocket-a", 4, "Falcon-9", "Artemis", 1000)
+	relaunch.Metadata.MessageTime = start.Add(3 * time.Minute)
+	repo.ProcessMessage(relaunch)
+
+	// rocket-b: APOLLO from launch
+	launch = createLaunchMessage("rocket-b", 1, "Saturn-V", "apollo", 2000)
+	launch.Metadata.MessageTime = start.Add(30 * time.Second)
+	repo.ProcessMessage(launch)
+
+	return repo
+}
+
+// Test mission assignment intervals and case-insensitive mission names
+func TestGetMission(t *testing.T) {
+	start := time.Date(2024, 3, 14, 19, 39, 0, 0, time.UTC)
+	repo := createMissionsRepository(start)
+
+	artemis, exists := repo.GetMission("artemis")
+	if !exists {
+		t.Fatal("Expected mission ARTEMIS to exist")
+	}
+	if artemis.Name != "Artemis" || artemis.CurrentRocketCount != 1 || artemis.RocketCount != 1 || artemis.AssignmentCount != 2 {
+		t.Errorf("Unexpected ARTEMIS summary: %+v", artemis.MissionSummary)
+	}
+	if len(artemis.HistoricalRockets) != 1 {
+		t.Fatalf("Expected 1 historical ARTEMIS assignment, got %d", len(artemis.HistoricalRockets))
+	}
+	historical := artemis.HistoricalRockets[0]
+	if historical.EndReason != models.AssignmentEndMissionChanged || !historical.From.Equal(start) || !historical.To.Equal(start.Add(time.Minute)) {
+		t.Errorf("Expected rocket-a on ARTEMIS until its mission changed, got %+v", historical)
+	}
+	if current := artemis.CurrentRockets[0]; current.RocketID != "rocket-a" || current.To != nil || !current.From.Equal(start.Add(3*time.Minute)) {
+		t.Errorf("Expected relaunched rocket-a to be on ARTEMIS, got %+v", current)
+	}
+
+	apollo, _ := repo.GetMission("APOLLO")
+	if apollo.CurrentRocketCount != 1 || apollo.RocketCount != 2 || apollo.CurrentRockets[0].RocketID != "rocket-b" {
+		t.Errorf("Expected rocket-b on APOLLO now and rocket-a in the past, got %+v", apollo)
+	}
+	if len(apollo.HistoricalRockets) != 1 || apollo.HistoricalRockets[0].EndReason != models.AssignmentEndExploded {
+		t.Errorf("Expected rocket-a APOLLO assignment to end by explosion, got %+v", apollo.HistoricalRockets)
+	}
+
+	if _, exists := repo.GetMission("GEMINI"); exists {
+		t.Error("Expected unknown mission not to exist")
+	}
+}
+
+// Test HandleGetMissions and HandleGetMission
+func TestHandleMissions(t *testing.T) {
+	handler := api.NewAPIHandlerWithRepository(createMissionsRepository(time.Now()))
+
+	req := httptest.NewRequest(http.MethodGet, "/missions", nil)
+	rr := httptest.NewRecorder()
+	handler.HandleGetMissions(rr, req)
+
+	var missions []models.MissionSummary
+	if err := json.NewDecoder(rr.Body).Decode(&missions); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(missions) != 2 || missions[0].Name != "APOLLO" || missions[1].Name != "Artemis" {
+		t.Errorf("Expected APOLLO and ARTEMIS ordered by name, got %+v", missions)
+	}
+
+	tests := []struct {
+		name           string
+		mission        string
+		expectedStatus int
+	}{
+		{"Known mission", "ARTEMIS", http.StatusOK},
+		{"Unknown mission", "GEMINI", http.StatusNotFound},
+		{"Empty name", " ", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/missions/x", nil)
+			req.SetPathValue("name", tt.mission)
+			rr := httptest.NewRecorder()
+
+			handler.HandleGetMission(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, rr.Code)
+			}
+		})
+	}
+}
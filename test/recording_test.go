@@ -0,0 +1,255 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"lunar-backend-challenge/internal/api"
+	"lunar-backend-challenge/internal/deadletter"
+	"lunar-backend-challenge/internal/importer"
+	"lunar-backend-challenge/internal/ingest"
+	"lunar-backend-challenge/internal/middleware"
+	"lunar-backend-challenge/internal/models"
+	"lunar-backend-challenge/internal/recording"
+	"lunar-backend-challenge/internal/storage"
+)
+
+// orderSink records the message numbers it receives, taking longer for earlier messages
+type orderSink struct {
+	mutex   sync.Mutex
+	numbers []int
+}
+
+func (s *orderSink) Apply(body []byte) (string, error) {
+	var msg models.RocketMessage
+	if err := json.Unmarshal(body, &msg); err != nil {
+		return string(storage.OutcomeRejected), err
+	}
+	time.Sleep(time.Duration(10-msg.Metadata.MessageNumber) * time.Millisecond)
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.numbers = append(s.numbers, msg.Metadata.MessageNumber)
+	return string(storage.OutcomeApplied), nil
+}
+
+// createRecords creates records of messages 1 to count of a rocket, received interval apart
+func createRecords(t *testing.T, rocketID string, count int, interval time.Duration) []recording.Record {
+	start := time.Now()
+	var records []recording.Record
+	for number := 1; number <= count; number++ {
+		messageType := models.MessageTypeRocketSpeedIncreased
+		if number == 1 {
+			messageType = models.MessageTypeRocketLaunched
+		}
+		body, err := json.Marshal(createTestMessage(rocketID, number, messageType))
+		if err != nil {
+			t.Fatalf("Failed to encode message: %v", err)
+		}
+		records = append(records, recording.NewRecord(int64(number), start.Add(time.Duration(number-1)*interval), body))
+	}
+	return records
+}
+
+// Test that the middleware records message bodies byte for byte and rotates the recording
+func TestRecordingMiddleware(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "messages.ndjson")
+	recorder, err := recording.NewRecorder(path, 600, 2)
+	if err != nil {
+		t.Fatalf("Failed to open recorder: %v", err)
+	}
+	defer recorder.Close()
+
+	handler := api.NewAPIHandler()
+	t.Cleanup(handler.Webhooks.Close)
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /messages", handler.HandleMessage)
+	mux.HandleFunc("GET /rockets", handler.HandleGetRockets)
+	server := middleware.ChainMiddleware(mux, middleware.Recording(recorder))
+
+	var sent [][]byte
+	for number := 1; number <= 8; number++ {
+		body, _ := json.Marshal(createTestMessage("recorded-rocket", number, models.MessageTypeRocketSpeedIncreased))
+		if number == 1 {
+			body, _ = json.Marshal(createTestMessage("recorded-rocket", number, models.MessageTypeRocketLaunched))
+		}
+		if number == 8 {
+			body = []byte{'{', 0xff, '}'} // Not JSON, nor UTF-8
+		}
+		sent = append(sent, body)
+		rr := httptest.NewRecorder()
+		server.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/messages", bytes.NewReader(body)))
+		if number < 8 && rr.Code != http.StatusOK {
+			t.Fatalf("Expected message %d to be processed, got %d: %s", number, rr.Code, rr.Body.String())
+		}
+	}
+	server.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/rockets", nil))
+
+	if rocket, _ := handler.Repository.GetRocket("recorded-rocket"); rocket.Speed != 1000+6*500 {
+		t.Errorf("Expected the handler to receive the bodies, got speed %d", rocket.Speed)
+	}
+
+	rotated, err := recording.RotatedFiles(path)
+	if err != nil || len(rotated) != 2 {
+		t.Fatalf("Expected 2 rotated files to be kept, got %v (%v)", rotated, err)
+	}
+	records, err := recording.ReadFiles(append(rotated, path))
+	if err != nil {
+		t.Fatalf("Failed to read recording: %v", err)
+	}
+	if len(records) == 0 || len(records) >= 8 {
+		t.Fatalf("Expected the oldest records to be rotated out, got %d", len(records))
+	}
+	for _, record := range records {
+		body, err := record.RawBody()
+		if err != nil || !bytes.Equal(body, sent[record.Sequence-1]) {
+			t.Errorf("Record %d: expected %q, got %q (%v)", record.Sequence, sent[record.Sequence-1], body, err)
+		}
+	}
+	if last := records[len(records)-1]; last.Sequence != 8 || last.Encoding != "base64" || last.ReceivedAt.IsZero() {
+		t.Errorf("Expected the last record to be the base64 encoded body, got %+v", last)
+	}
+}
+
+// Test that reopening a recording continues its sequence, also after a rotation or a cut-off line
+func TestRecorderContinuesSequence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "messages.ndjson")
+	start := time.Date(2024, 3, 14, 19, 39, 0, 0, time.UTC)
+	record := func(count int) {
+		t.Helper()
+		recorder, err := recording.NewRecorder(path, 0, 0)
+		if err != nil {
+			t.Fatalf("Failed to open recorder: %v", err)
+		}
+		for range count {
+			if err := recorder.Record(start, []byte("{}")); err != nil {
+				t.Fatalf("Failed to record: %v", err)
+			}
+		}
+		if err := recorder.Close(); err != nil {
+			t.Fatalf("Failed to close recorder: %v", err)
+		}
+	}
+
+	record(3)
+	file, _ := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0o644)
+	file.WriteString(`{"sequence":4,"receivedAt":`)
+	file.Close()
+	os.Rename(path, recording.RotatedPath(path, start))
+	record(2)
+
+	rotated, _ := recording.RotatedFiles(path)
+	if len(rotated) != 1 {
+		t.Fatalf("Expected the rotated recording to be kept, got %v", rotated)
+	}
+	records, err := recording.ReadFiles([]string{path})
+	if err != nil || len(records) != 2 || records[0].Sequence != 4 || records[1].Sequence != 5 {
+		t.Errorf("Expected records 4 and 5 after reopening, got %+v (%v)", records, err)
+	}
+}
+
+// Test replaying as fast as possible into a repository, and stopping when delivery fails
+func TestReplayIntoRepository(t *testing.T) {
+	repository := storage.NewRocketRepository()
+	replayer := &recording.Replayer{
+		Sink:        importer.RepositorySink{Ingest: ingest.NewService(repository, deadletter.NewStore(deadletter.DefaultCapacity))},
+		Concurrency: 4,
+	}
+	records := createRecords(t, "replayed-rocket", 20, time.Hour)
+	records = append(records, recording.NewRecord(21, records[19].ReceivedAt, []byte("{not json")))
+
+	report := importer.NewReport()
+	start := time.Now()
+	if err := replayer.Replay(context.Background(), "recording", records, report); err != nil {
+		t.Fatalf("Replay failed: %v", err)
+	}
+	if time.Since(start) > time.Second {
+		t.Errorf("Expected speed 0 to ignore the recorded pacing")
+	}
+	if report.Lines != 21 || len(report.Failures) != 1 || report.Failures[0].Line != 21 {
+		t.Errorf("Unexpected report: %s %+v", report, report.Failures)
+	}
+	if rocket, _ := repository.GetRocket("replayed-rocket"); rocket.Speed != 1000+19*500 {
+		t.Errorf("Expected all messages to be applied, got speed %d", rocket.Speed)
+	}
+
+	handler := api.NewAPIHandler()
+	t.Cleanup(handler.Webhooks.Close)
+	server := httptest.NewServer(http.HandlerFunc(handler.HandleMessage))
+	server.Close()
+	replayer.Sink = importer.NewHTTPSink(server.URL)
+	err := replayer.Replay(context.Background(), "recording", records, importer.NewReport())
+	if err == nil || !strings.Contains(err.Error(), "recording record") {
+		t.Errorf("Expected the replay to stop at the first record, got %v", err)
+	}
+}
+
+// Test original and scaled pacing, and that the arrival order is preserved when asked
+func TestReplayPacingAndOrder(t *testing.T) {
+	records := createRecords(t, "paced-rocket", 5, 40*time.Millisecond)
+
+	for _, tt := range []struct {
+		speed   float64
+		minimum time.Duration
+	}{
+		{1, 160 * time.Millisecond},
+		{4, 40 * time.Millisecond},
+	} {
+		t.Run(fmt.Sprintf("speed %g", tt.speed), func(t *testing.T) {
+			sink := &orderSink{}
+			replayer := &recording.Replayer{Sink: sink, Speed: tt.speed, PreserveOrder: true, Concurrency: 4}
+			start := time.Now()
+			if err := replayer.Replay(context.Background(), "recording", records, importer.NewReport()); err != nil {
+				t.Fatalf("Replay failed: %v", err)
+			}
+			if elapsed := time.Since(start); elapsed < tt.minimum {
+				t.Errorf("Expected the replay to take at least %s, took %s", tt.minimum, elapsed)
+			}
+			if !reflect.DeepEqual(sink.numbers, []int{1, 2, 3, 4, 5}) {
+				t.Errorf("Expected the original order, got %v", sink.numbers)
+			}
+		})
+	}
+
+	// Concurrent senders let later, faster deliveries overtake earlier ones
+	sink := &orderSink{}
+	replayer := &recording.Replayer{Sink: sink, Concurrency: 5}
+	if err := replayer.Replay(context.Background(), "recording", records, importer.NewReport()); err != nil {
+		t.Fatalf("Replay failed: %v", err)
+	}
+	if len(sink.numbers) != 5 || reflect.DeepEqual(sink.numbers, []int{1, 2, 3, 4, 5}) {
+		t.Errorf("Expected concurrent deliveries to finish out of order, got %v", sink.numbers)
+	}
+}
+
+// Test that records survive a round trip through a file
+func TestReadRecords(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "recording.ndjson")
+	records := createRecords(t, "stored-rocket", 3, time.Second)
+	var lines []string
+	for _, record := range records {
+		line, _ := json.Marshal(record)
+		lines = append(lines, string(line))
+	}
+	os.WriteFile(path, []byte(strings.Join(lines, "\n\n")+"\n"), 0o644)
+
+	read, err := recording.ReadFiles([]string{path})
+	if err != nil || len(read) != 3 || !read[2].ReceivedAt.Equal(records[2].ReceivedAt) || read[1].Body != records[1].Body {
+		t.Errorf("Expected the records back, got %+v (%v)", read, err)
+	}
+
+	os.WriteFile(path, []byte("{\"sequence\":1}\nnot a record\n"), 0o644)
+	if _, err := recording.ReadFiles([]string{path}); err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("Expected an error on line 2, got %v", err)
+	}
+}
@@ -0,0 +1,233 @@
+package test
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"lunar-backend-challenge/internal/api"
+	"lunar-backend-challenge/internal/errors"
+	"lunar-backend-challenge/internal/lifecycle"
+	"lunar-backend-challenge/internal/models"
+	"lunar-backend-challenge/internal/registry"
+	"lunar-backend-challenge/internal/storage"
+	"lunar-backend-challenge/internal/validation"
+)
+
+// Test that the built-in message types are registered
+func TestRegistry_BuiltinTypes(t *testing.T) {
+	builtins := []string{
+		models.MessageTypeRocketLaunched,
+		models.MessageTypeRocketSpeedIncreased,
+		models.MessageTypeRocketSpeedDecreased,
+		models.MessageTypeRocketExploded,
+		models.MessageTypeRocketMissionChanged,
+	}
+
+	for _, name := range builtins {
+		messageType, exists := registry.Lookup(name)
+		if !exists {
+			t.Errorf("Expected built-in message type %s to be registered", name)
+			continue
+		}
+		if messageType.Apply == nil || messageType.Decode == nil || messageType.Validate == nil {
+			t.Errorf("Expected message type %s to have decoder, validator and reducer", name)
+		}
+	}
+
+	launched, _ := registry.Lookup(models.MessageTypeRocketLaunched)
+	if launched.Event != lifecycle.EventLaunch {
+		t.Errorf("Expected RocketLaunched to cause a launch event, got %s", launched.Event)
+	}
+}
+
+// Test that speed increases saturate instead of wrapping around to a negative speed
+func TestRegistry_SpeedIncreaseSaturates(t *testing.T) {
+	increased, _ := registry.Lookup(models.MessageTypeRocketSpeedIncreased)
+	rocket := &models.RocketState{ID: "saturated-rocket", Speed: math.MaxInt - 100}
+	msg := &models.RocketMessage{Message: models.MessageContent{By: 500}}
+
+	if !increased.Apply(rocket, msg) {
+		t.Fatal("Expected the speed increase to be applied")
+	}
+	if rocket.Speed != math.MaxInt {
+		t.Errorf("Expected speed to saturate at %d, got %d", math.MaxInt, rocket.Speed)
+	}
+
+	if !increased.Apply(rocket, msg) || rocket.Speed != math.MaxInt {
+		t.Errorf("Expected a saturated speed to stay at %d, got %d", math.MaxInt, rocket.Speed)
+	}
+}
+
+// Test registration errors
+func TestRegistry_RegisterErrors(t *testing.T) {
+	reg := registry.NewRegistry()
+	apply := func(rocket *models.RocketState, msg *models.RocketMessage) bool { return true }
+
+	if err := reg.Register(registry.MessageType{Apply: apply}); err == nil {
+		t.Error("Expected error when registering a type without a name")
+	}
+
+	if err := reg.Register(registry.MessageType{Name: "RocketNoop"}); err == nil {
+		t.Error("Expected error when registering a type without a reducer")
+	}
+
+	if err := reg.Register(registry.MessageType{Name: "RocketNoop", Apply: apply}); err != nil {
+		t.Fatalf("Expected registration to succeed, got %v", err)
+	}
+
+	if err := reg.Register(registry.MessageType{Name: "RocketNoop", Apply: apply}); err == nil {
+		t.Error("Expected error when registering the same type twice")
+	}
+
+	if names := reg.Names(); len(names) != 1 || names[0] != "RocketNoop" {
+		t.Errorf("Expected names [RocketNoop], got %v", names)
+	}
+
+	if noop, _ := reg.Lookup("RocketNoop"); noop.Event != lifecycle.EventUpdate {
+		t.Errorf("Expected default event %s, got %s", lifecycle.EventUpdate, noop.Event)
+	}
+}
+
+// messageTypeBoosted is a custom message type multiplying the speed by its factor
+const messageTypeBoosted = "TestRocketBoosted"
+
+// createBoostedRegistry creates a private registry with the built-in types and messageTypeBoosted,
+// so the type does not leak into other tests or repeated runs
+func createBoostedRegistry(t *testing.T) *registry.Registry {
+	t.Helper()
+	types := registry.NewBuiltinRegistry()
+	err := types.Register(registry.MessageType{
+		Name: messageTypeBoosted,
+		Validate: func(content *models.MessageContent) error {
+			var payload struct {
+				Factor int `json:"factor"`
+			}
+			if err := json.Unmarshal(content.Raw, &payload); err != nil || payload.Factor <= 0 {
+				return errors.NewValidationError("factor", "factor must be positive")
+			}
+			return nil
+		},
+		Apply: func(rocket *models.RocketState, msg *models.RocketMessage) bool {
+			var payload struct {
+				Factor int `json:"factor"`
+			}
+			if err := json.Unmarshal(msg.Message.Raw, &payload); err != nil {
+				return false
+			}
+			rocket.Speed *= payload.Factor
+			return true
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to register message type: %v", err)
+	}
+	return types
+}
+
+// Test that a new message type can be added without touching validation or storage
+func TestRegistry_CustomMessageType(t *testing.T) {
+	types := createBoostedRegistry(t)
+	config := storage.DefaultConfig()
+	config.Registry = types
+	repo := storage.NewRocketRepositoryWithConfig(config)
+	rocketID := "custom-type-rocket"
+	repo.ProcessMessage(createTestMessage(rocketID, 1, models.MessageTypeRocketLaunched))
+
+	body := []byte(`{
+		"metadata": {
+			"channel": "custom-type-rocket",
+			"messageNumber": 2,
+			"messageTime": "2024-03-14T19:39:05.86337+01:00",
+			"messageType": "TestRocketBoosted"
+		},
+		"message": {"factor": 3}
+	}`)
+
+	msg, err := types.DecodeMessage(body)
+	if err != nil {
+		t.Fatalf("Failed to decode message: %v", err)
+	}
+
+	if err := validation.ValidateRocketMessageWith(types, msg); err != nil {
+		t.Fatalf("Expected custom message to be valid, got %v", err)
+	}
+
+	if !repo.ProcessMessage(msg) {
+		t.Fatal("Expected custom message processing to succeed")
+	}
+
+	rocket, _ := repo.GetRocket(rocketID)
+	if rocket.Speed != 3000 {
+		t.Errorf("Expected speed 3000, got %d", rocket.Speed)
+	}
+
+	if rocket.LastProcessedMessageNumber != 2 {
+		t.Errorf("Expected last processed message number 2, got %d", rocket.LastProcessedMessageNumber)
+	}
+
+	if _, exists := registry.Lookup(messageTypeBoosted); exists {
+		t.Error("Expected the default registry to be left unchanged")
+	}
+}
+
+// Test that messages of a custom type registered in the repository are accepted over HTTP
+func TestRegistry_CustomMessageTypeOverHTTP(t *testing.T) {
+	config := storage.DefaultConfig()
+	config.Registry = createBoostedRegistry(t)
+	handler := api.NewAPIHandlerWithRepository(storage.NewRocketRepositoryWithConfig(config))
+	t.Cleanup(handler.Webhooks.Close)
+	handler.Repository.ProcessMessage(createTestMessage("custom-http-rocket", 1, models.MessageTypeRocketLaunched))
+
+	body := `{
+		"metadata": {
+			"channel": "custom-http-rocket",
+			"messageNumber": 2,
+			"messageTime": "2024-03-14T19:39:05.86337+01:00",
+			"messageType": "TestRocketBoosted"
+		},
+		"message": {"factor": 2}
+	}`
+	rr := httptest.NewRecorder()
+	handler.HandleMessage(rr, httptest.NewRequest(http.MethodPost, "/messages", strings.NewReader(body)))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected the custom message to be accepted, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if rocket, _ := handler.Repository.GetRocket("custom-http-rocket"); rocket.Speed != 2000 {
+		t.Errorf("Expected speed 2000, got %d", rocket.Speed)
+	}
+}
+
+// Test that a repository created from a zero config applies messages with the default registry
+func TestRegistry_ZeroConfigRepository(t *testing.T) {
+	repo := storage.NewRocketRepositoryWithConfig(storage.Config{})
+	if !repo.ProcessMessage(createTestMessage("zero-config-rocket", 1, models.MessageTypeRocketLaunched)) {
+		t.Fatal("Expected the launch to be processed")
+	}
+	if rocket, exists := repo.GetRocket("zero-config-rocket"); !exists || rocket.Speed != 1000 {
+		t.Errorf("Expected the rocket to be launched, got %+v", rocket)
+	}
+}
+
+// Test that unknown message types are rejected by validation
+func TestRegistry_UnknownMessageType(t *testing.T) {
+	msg, err := registry.DecodeMessage([]byte(`{
+		"metadata": {
+			"channel": "unknown-type-rocket",
+			"messageNumber": 1,
+			"messageTime": "2024-03-14T19:39:05.86337+01:00",
+			"messageType": "RocketTeleported"
+		},
+		"message": {"to": "MARS"}
+	}`))
+	if err != nil {
+		t.Fatalf("Expected envelope to decode, got %v", err)
+	}
+
+	if err := validation.ValidateRocketMessage(msg); err == nil {
+		t.Error("Expected validation to reject unknown message type")
+	}
+}
@@ -1,6 +1,8 @@
 package test
 
 import (
+	"fmt"
+	"sync"
 	"testing"
 	"time"
 
@@ -251,9 +253,19 @@ func TestInvalidMessageProcessing(t *testing.T) {
 	repo.ProcessMessage(launchMsg)
 	repo.ProcessMessage(explodeMsg)
 
-	success = repo.ProcessMessage(speedMsg)
-	if success {
-		t.Error("Expected speed change on exploded rocket to fail")
+	// Speed change on exploded rocket is accounted for but has no effect
+	result := repo.Process(speedMsg)
+	if result.Outcome != storage.OutcomeIgnored {
+		t.Errorf("Expected speed change on exploded rocket to be ignored, got %s", result.Outcome)
+	}
+
+	rocket, _ := repo.GetRocket(rocketID)
+	if rocket.Speed != launchMsg.Message.LaunchSpeed {
+		t.Errorf("Expected speed %d to be unchanged, got %d", launchMsg.Message.LaunchSpeed, rocket.Speed)
+	}
+
+	if rocket.LastProcessedMessageNumber != 3 {
+		t.Errorf("Expected last processed message number 3, got %d", rocket.LastProcessedMessageNumber)
 	}
 }
 
@@ -295,6 +307,58 @@ func TestConcurrentMessageProcessing(t *testing.T) {
 
 }
 
+// Test that observers see the state changes of concurrent writers in the order they were applied
+func TestConcurrentChangeOrder(t *testing.T) {
+	now := time.Date(2024, 3, 14, 19, 39, 0, 0, time.UTC)
+	repo := createSilenceRepository(&now, storage.SilenceByReceiveTime)
+
+	var mutex sync.Mutex
+	changes := make(map[string][]storage.StateChange)
+	repo.Subscribe(func(change storage.StateChange) {
+		time.Sleep(10 * time.Microsecond) // Give a later writer the chance to overtake
+		mutex.Lock()
+		defer mutex.Unlock()
+		changes[change.RocketID] = append(changes[change.RocketID], change)
+	})
+
+	const rockets, messages = 4, 50
+	var wg sync.WaitGroup
+	for rocket := range rockets {
+		rocketID := fmt.Sprintf("ordered-rocket-%d", rocket)
+		repo.ProcessMessage(createTestMessage(rocketID, 1, models.MessageTypeRocketLaunched))
+		for number := 2; number <= messages; number++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				repo.ProcessMessage(createTestMessage(rocketID, number, models.MessageTypeRocketSpeedIncreased))
+			}()
+		}
+	}
+	// Going silent and recovering race the messages too
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			repo.DetectSilence(now.Add(time.Hour))
+		}()
+	}
+	wg.Wait()
+
+	for rocketID, observed := range changes {
+		for i := 1; i < len(observed); i++ {
+			previous, change := observed[i-1].After, observed[i].Before
+			if change == nil || change.LastProcessedMessageNumber != previous.LastProcessedMessageNumber ||
+				change.Speed != previous.Speed || change.Silent != previous.Silent {
+				t.Fatalf("%s: %s change %d does not follow the %s change before it, which left message %d, speed %d, silent %v",
+					rocketID, observed[i].Kind, i, observed[i-1].Kind, previous.LastProcessedMessageNumber, previous.Speed, previous.Silent)
+			}
+		}
+		if last := observed[len(observed)-1].After; last.LastProcessedMessageNumber != messages {
+			t.Errorf("%s: expected the last change to apply message %d, got %d", rocketID, messages, last.LastProcessedMessageNumber)
+		}
+	}
+}
+
 // Test GetAllRockets
 func TestGetAllRockets(t *testing.T) {
 	repo := storage.NewRocketRepository()
@@ -0,0 +1,181 @@
+package test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"lunar-backend-challenge/internal/api"
+	"lunar-backend-challenge/internal/filtering"
+	"lunar-backend-challenge/internal/models"
+	"lunar-backend-challenge/internal/storage"
+)
+
+// createSilenceRepository creates a repository with a controllable clock and the given silence clock
+func createSilenceRepository(now *time.Time, clock storage.SilenceClock) *storage.RocketRepository {
+	config := storage.DefaultConfig()
+	config.SilenceTimeout = time.Minute
+	config.SilenceClock = clock
+	config.Now = func() time.Time { return *now }
+	return storage.NewRocketRepositoryWithConfig(config)
+}
+
+// Test that rockets in flight go silent without messages and recover on the next one
+func TestDetectSilence(t *testing.T) {
+	start := time.Date(2024, 3, 14, 19, 39, 0, 0, time.UTC)
+	now := start
+	repo := createSilenceRepository(&now, storage.SilenceByReceiveTime)
+
+	var changes []storage.StateChange
+	repo.Subscribe(func(change storage.StateChange) { changes = append(changes, change) })
+
+	repo.ProcessMessage(createTestMessage("quiet-rocket", 1, models.MessageTypeRocketLaunched))
+	repo.ProcessMessage(createTestMessage("exploded-rocket", 1, models.MessageTypeRocketLaunched))
+	repo.ProcessMessage(createTestMessage("exploded-rocket", 2, models.MessageTypeRocketExploded))
+
+	now = start.Add(30 * time.Second)
+	repo.ProcessMessage(createTestMessage("chatty-rocket", 1, models.MessageTypeRocketLaunched))
+
+	if silenced := repo.DetectSilence(start.Add(59 * time.Second)); len(silenced) != 0 {
+		t.Fatalf("Expected no silent rocket before the timeout, got %v", silenced)
+	}
+
+	now = start.Add(time.Minute)
+	silenced := repo.DetectSilence(now)
+	if len(silenced) != 1 || silenced[0] != "quiet-rocket" {
+		t.Fatalf("Expected only the quiet rocket in flight to go silent, got %v", silenced)
+	}
+	if again := repo.DetectSilence(now.Add(time.Hour)); len(again) != 1 || again[0] != "chatty-rocket" {
+		t.Errorf("Expected a silent rocket to be reported only once, got %v", again)
+	}
+
+	rocket, _ := repo.GetRocket("quiet-rocket")
+	if !rocket.Silent || rocket.SilentSince == nil || !rocket.SilentSince.Equal(now) || !rocket.LastSeen.Equal(start) {
+		t.Errorf("Expected quiet rocket silent since %v and last seen at %v, got %+v", now, start, rocket)
+	}
+
+	stats := repo.GetStats(filtering.Filter{Silent: "true"})
+	if stats.TotalRockets != 2 || stats.Silent != 2 {
+		t.Errorf("Expected 2 silent rockets in the stats, got %+v", stats)
+	}
+
+	// A message that can only be buffered still shows the rocket is alive
+	now = start.Add(2 * time.Hour)
+	repo.ProcessMessage(createTestMessage("quiet-rocket", 3, models.MessageTypeRocketSpeedIncreased))
+	rocket, _ = repo.GetRocket("quiet-rocket")
+	if rocket.Silent || rocket.SilentSince != nil || !rocket.LastSeen.Equal(now) {
+		t.Errorf("Expected quiet rocket to recover, got %+v", rocket)
+	}
+
+	var kinds []string
+	for _, change := range changes {
+		if change.RocketID == "quiet-rocket" {
+			kinds = append(kinds, change.Kind)
+		}
+	}
+	expected := []string{storage.ChangeApplied, storage.ChangeSilent, storage.ChangeRecovered}
+	if len(kinds) != len(expected) {
+		t.Fatalf("Expected changes %v, got %v", expected, kinds)
+	}
+	for i := range expected {
+		if kinds[i] != expected[i] {
+			t.Errorf("Change %d: expected %s, got %s", i, expected[i], kinds[i])
+		}
+	}
+}
+
+// Test silence measured by message time
+func TestDetectSilenceByMessageTime(t *testing.T) {
+	start := time.Date(2024, 3, 14, 19, 39, 0, 0, time.UTC)
+	now := start
+	repo := createSilenceRepository(&now, storage.SilenceByMessageTime)
+
+	// Received just now, but sent two minutes ago
+	msg := createTimedMessage("delayed-rocket", 1, models.MessageTypeRocketLaunched, start.Add(-2*time.Minute))
+	repo.ProcessMessage(msg)
+
+	silenced := repo.DetectSilence(now)
+	if len(silenced) != 1 {
+		t.Fatalf("Expected rocket to be silent by message time, got %v", silenced)
+	}
+	rocket, _ := repo.GetRocket("delayed-rocket")
+	if !rocket.LastMessageTime.Equal(msg.GetMessageTime()) {
+		t.Errorf("Expected last message time %v, got %v", msg.GetMessageTime(), rocket.LastMessageTime)
+	}
+}
+
+// Test that messages received before the launch count towards the last message time
+func TestLastMessageTimeBeforeLaunch(t *testing.T) {
+	start := time.Date(2024, 3, 14, 19, 39, 0, 0, time.UTC)
+	now := start
+	repo := createSilenceRepository(&now, storage.SilenceByMessageTime)
+
+	later := createTimedMessage("early-rocket", 2, models.MessageTypeRocketSpeedIncreased, start.Add(-time.Minute))
+	repo.ProcessMessage(later)
+	repo.ProcessMessage(createTimedMessage("early-rocket", 1, models.MessageTypeRocketLaunched, start.Add(-2*time.Minute)))
+
+	rocket, _ := repo.GetRocket("early-rocket")
+	if !rocket.LastMessageTime.Equal(later.GetMessageTime()) {
+		t.Errorf("Expected last message time %v, got %v", later.GetMessageTime(), rocket.LastMessageTime)
+	}
+}
+
+// Test that retransmissions of processed messages keep a rocket from going silent, and recover it
+func TestRetransmissionsAreActivity(t *testing.T) {
+	start := time.Date(2024, 3, 14, 19, 39, 0, 0, time.UTC)
+	now := start
+	repo := createSilenceRepository(&now, storage.SilenceByReceiveTime)
+	launch := createTestMessage("repeating-rocket", 1, models.MessageTypeRocketLaunched)
+	repo.ProcessMessage(launch)
+
+	now = start.Add(50 * time.Second)
+	if result := repo.Process(launch); result.Outcome != storage.OutcomeDuplicate {
+		t.Fatalf("Expected a duplicate, got %+v", result)
+	}
+	if silenced := repo.DetectSilence(start.Add(90 * time.Second)); len(silenced) != 0 {
+		t.Errorf("Expected a retransmitting rocket not to go silent, got %v", silenced)
+	}
+
+	if silenced := repo.DetectSilence(start.Add(2 * time.Minute)); len(silenced) != 1 {
+		t.Fatalf("Expected the rocket to go silent, got %v", silenced)
+	}
+	now = start.Add(3 * time.Minute)
+	repo.Process(launch)
+	if rocket, _ := repo.GetRocket("repeating-rocket"); rocket.Silent || !rocket.LastSeen.Equal(now) {
+		t.Errorf("Expected a retransmission to recover the rocket, got %+v", rocket)
+	}
+}
+
+// Test the silent filter of the rocket list
+func TestHandleGetRocketsSilentFilter(t *testing.T) {
+	start := time.Date(2024, 3, 14, 19, 39, 0, 0, time.UTC)
+	now := start
+	handler := api.NewAPIHandlerWithRepository(createSilenceRepository(&now, storage.SilenceByReceiveTime))
+	t.Cleanup(handler.Webhooks.Close)
+
+	handler.Repository.ProcessMessage(createTestHTTPMessage("silent-rocket", 1, models.MessageTypeRocketLaunched))
+	now = start.Add(45 * time.Second)
+	handler.Repository.ProcessMessage(createTestHTTPMessage("loud-rocket", 1, models.MessageTypeRocketLaunched))
+	handler.Repository.DetectSilence(start.Add(time.Minute))
+
+	for query, expectedID := range map[string]string{"true": "silent-rocket", "false": "loud-rocket"} {
+		rr := httptest.NewRecorder()
+		handler.HandleGetRockets(rr, httptest.NewRequest(http.MethodGet, "/rockets?silent="+query, nil))
+
+		var rockets []models.RocketSummary
+		if err := json.NewDecoder(rr.Body).Decode(&rockets); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		if len(rockets) != 1 || rockets[0].ID != expectedID || rockets[0].Silent != (query == "true") {
+			t.Errorf("silent=%s: expected only %s, got %+v", query, expectedID, rockets)
+		}
+	}
+
+	rr := httptest.NewRecorder()
+	handler.HandleGetRockets(rr, httptest.NewRequest(http.MethodGet, "/rockets?silent=maybe", nil))
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d for an invalid silent filter, got %d", http.StatusBadRequest, rr.Code)
+	}
+}
@@ -0,0 +1,145 @@
+package test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"lunar-backend-challenge/internal/api"
+	"lunar-backend-challenge/internal/deadletter"
+	"lunar-backend-challenge/internal/importer"
+	"lunar-backend-challenge/internal/ingest"
+	"lunar-backend-challenge/internal/simulator"
+	"lunar-backend-challenge/internal/storage"
+)
+
+// createSimulatorConfig creates a fast simulation with reordering and duplicates
+func createSimulatorConfig(messages int) simulator.Config {
+	config := simulator.DefaultConfig()
+	config.Messages = messages
+	config.Rockets = 6
+	config.MessageDelay = 0
+	config.Concurrency = 4
+	config.ReorderRate = 0.2
+	config.DuplicateRate = 0.1
+	config.Seed = 42
+	return config
+}
+
+// Test that a repository converges to the simulated fleet despite reordering, duplicates and concurrency
+func TestSimulatorIntoRepository(t *testing.T) {
+	repository := storage.NewRocketRepository()
+	sink := importer.RepositorySink{Ingest: ingest.NewService(repository, deadletter.NewStore(deadletter.DefaultCapacity))}
+	sim := simulator.New(createSimulatorConfig(1000))
+
+	report, err := sim.Run(context.Background(), sink)
+	if err != nil {
+		t.Fatalf("Simulation failed: %v", err)
+	}
+	if report.Generated != 1000 || report.Sent != 1000+report.Duplicated || report.Errors != 0 {
+		t.Errorf("Unexpected report: %+v", report)
+	}
+	if report.Reordered == 0 || report.Duplicated == 0 || report.Outcomes["buffered"] == 0 || report.Outcomes["duplicate"] != report.Duplicated {
+		t.Errorf("Expected reordered and duplicate deliveries, got %+v", report)
+	}
+
+	expected := sim.Rockets()
+	if len(expected) < 6 || len(repository.GetAllRockets()) != len(expected) {
+		t.Fatalf("Expected the repository to hold the %d simulated rockets, got %d", len(expected), len(repository.GetAllRockets()))
+	}
+	exploded := 0
+	for _, want := range expected {
+		rocket, exists := repository.GetRocket(want.ID)
+		if !exists || rocket.Type != want.Type || rocket.Speed != want.Speed || rocket.Mission != want.Mission || rocket.Status != want.Status {
+			t.Errorf("Expected %+v, got %+v", want, rocket)
+		}
+		if want.Exploded {
+			exploded++
+		}
+	}
+	if exploded == 0 {
+		t.Errorf("Expected exploded rockets to be replaced by new ones")
+	}
+}
+
+// Test that a seed makes the generated fleet repeatable
+func TestSimulatorSeed(t *testing.T) {
+	run := func(seed uint64) []string {
+		config := createSimulatorConfig(200)
+		config.Concurrency = 1
+		config.Seed = seed
+		sim := simulator.New(config)
+		if _, err := sim.Run(context.Background(), importer.DryRunSink{}); err != nil {
+			t.Fatalf("Simulation failed: %v", err)
+		}
+		var rockets []string
+		for _, rocket := range sim.Rockets() {
+			rockets = append(rockets, rocket.ID+rocket.Type+rocket.Mission)
+		}
+		return rockets
+	}
+
+	if first, second := run(7), run(7); !reflect.DeepEqual(first, second) {
+		t.Errorf("Expected the same fleet for the same seed, got %v and %v", first, second)
+	}
+	if first, other := run(7), run(8); reflect.DeepEqual(first, other) {
+		t.Errorf("Expected different fleets for different seeds")
+	}
+}
+
+// Test posting to a server, stopping on cancellation and when the server cannot be reached
+func TestSimulatorOverHTTP(t *testing.T) {
+	handler := api.NewAPIHandler()
+	t.Cleanup(handler.Webhooks.Close)
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /messages", handler.HandleMessage)
+	server := httptest.NewServer(mux)
+	sink := importer.NewHTTPSink(server.URL)
+
+	report, err := simulator.New(createSimulatorConfig(100)).Run(context.Background(), sink)
+	if err != nil || report.Outcomes["applied"] == 0 {
+		t.Fatalf("Expected messages to be applied, got %+v (%v)", report, err)
+	}
+	if len(handler.Repository.GetAllRockets()) == 0 {
+		t.Errorf("Expected the server to hold the simulated rockets")
+	}
+
+	// Without a message limit the simulation runs until cancelled
+	config := createSimulatorConfig(0)
+	config.MessageDelay = 10 * time.Millisecond
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if report, err := simulator.New(config).Run(ctx, sink); err != nil || report.Sent == 0 {
+		t.Errorf("Expected the simulation to run until cancelled, got %+v (%v)", report, err)
+	}
+
+	server.Close()
+	_, err = simulator.New(createSimulatorConfig(10)).Run(context.Background(), sink)
+	if err == nil || !strings.Contains(err.Error(), "failed to deliver message") {
+		t.Errorf("Expected a delivery error, got %v", err)
+	}
+}
+
+// Test configuration validation
+func TestSimulatorConfigValidation(t *testing.T) {
+	if err := simulator.DefaultConfig().Validate(); err != nil {
+		t.Errorf("Expected the default configuration to be valid, got %v", err)
+	}
+	for name, change := range map[string]func(*simulator.Config){
+		"no rockets":        func(c *simulator.Config) { c.Rockets = 0 },
+		"no senders":        func(c *simulator.Config) { c.Concurrency = 0 },
+		"negative delay":    func(c *simulator.Config) { c.MessageDelay = -time.Second },
+		"rate above 1":      func(c *simulator.Config) { c.DuplicateRate = 1.5 },
+		"no reorder window": func(c *simulator.Config) { c.ReorderRate, c.ReorderWindow = 0.1, 0 },
+	} {
+		config := simulator.DefaultConfig()
+		change(&config)
+		if config.Validate() == nil {
+			t.Errorf("%s: expected a validation error", name)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"lunar-backend-challenge/internal/api"
+	"lunar-backend-challenge/internal/models"
+	"lunar-backend-challenge/internal/storage"
+)
+
+// createTimedMessage creates a test message with the given message time
+func createTimedMessage(channel string, messageNumber int, messageType string, messageTime time.Time) *models.RocketMessage {
+	msg := createTestMessage(channel, messageNumber, messageType)
+	msg.Metadata.MessageTime = messageTime
+	return msg
+}
+
+// Test that speed points are recorded in message sequence order whatever the arrival order
+func TestSpeedSeriesOrderedBySequence(t *testing.T) {
+	repo := storage.NewRocketRepository()
+	rocketID := "speed-series-rocket"
+	start := time.Date(2024, 3, 14, 19, 39, 0, 0, time.UTC)
+
+	// Launch 1000, +500, -300, mission change (no point), +500, all 10 seconds apart
+	messages := []*models.RocketMessage{
+		createTimedMessage(rocketID, 1, models.MessageTypeRocketLaunched, start),
+		createTimedMessage(rocketID, 2, models.MessageTypeRocketSpeedIncreased, start.Add(10*time.Second)),
+		createTimedMessage(rocketID, 3, models.MessageTypeRocketSpeedDecreased, start.Add(20*time.Second)),
+		createTimedMessage(rocketID, 4, models.MessageTypeRocketMissionChanged, start.Add(30*time.Second)),
+		createTimedMessage(rocketID, 5, models.MessageTypeRocketSpeedIncreased, start.Add(40*time.Second)),
+	}
+	for _, i := range []int{4, 2, 0, 3, 1} {
+		repo.ProcessMessage(messages[i])
+	}
+
+	buckets, exists := repo.GetSpeedBuckets(rocketID, time.Time{}, time.Time{}, 0)
+	if !exists {
+		t.Fatal("Expected speed series for existing rocket")
+	}
+
+	expected := []struct{ number, speed int }{{1, 1000}, {2, 1500}, {3, 1200}, {5, 1700}}
+	if len(buckets) != len(expected) {
+		t.Fatalf("Expected %d points, got %d: %+v", len(expected), len(buckets), buckets)
+	}
+	for i, want := range expected {
+		if buckets[i].LastMessageNumber != want.number || buckets[i].Last != want.speed || buckets[i].Count != 1 {
+			t.Errorf("Point %d: expected message %d at speed %d, got %+v", i, want.number, want.speed, buckets[i])
+		}
+	}
+
+	// 30 second buckets: [0s, 30s) holds messages 1-3, [30s, 60s) holds message 5
+	buckets, _ = repo.GetSpeedBuckets(rocketID, time.Time{}, time.Time{}, 30*time.Second)
+	if len(buckets) != 2 {
+		t.Fatalf("Expected 2 buckets, got %d: %+v", len(buckets), buckets)
+	}
+	first := buckets[0]
+	if first.Count != 3 || first.Min != 1000 || first.Max != 1500 || first.Last != 1200 || first.Avg != 1233.3333333333333 {
+		t.Errorf("Unexpected first bucket: %+v", first)
+	}
+	if !first.Start.Equal(start) || !first.End.Equal(start.Add(30*time.Second)) {
+		t.Errorf("Expected first bucket to span %v to %v, got %v to %v", start, start.Add(30*time.Second), first.Start, first.End)
+	}
+	if buckets[1].Count != 1 || buckets[1].Last != 1700 {
+		t.Errorf("Unexpected second bucket: %+v", buckets[1])
+	}
+
+	// Time range keeps from <= messageTime < to
+	buckets, _ = repo.GetSpeedBuckets(rocketID, start.Add(10*time.Second), start.Add(40*time.Second), 0)
+	if len(buckets) != 2 || buckets[0].LastMessageNumber != 2 || buckets[1].LastMessageNumber != 3 {
+		t.Errorf("Expected messages 2 and 3 in range, got %+v", buckets)
+	}
+
+	if _, exists := repo.GetSpeedBuckets("missing-rocket", time.Time{}, time.Time{}, 0); exists {
+		t.Error("Expected no speed series for unknown rocket")
+	}
+}
+
+// Test that only the most recent points are kept
+func TestSpeedSeriesRetention(t *testing.T) {
+	config := storage.DefaultConfig()
+	config.SpeedRetention = 5
+	repo := storage.NewRocketRepositoryWithConfig(config)
+	rocketID := "speed-retention-rocket"
+
+	repo.ProcessMessage(createTestMessage(rocketID, 1, models.MessageTypeRocketLaunched))
+	for i := 2; i <= 20; i++ {
+		repo.ProcessMessage(createTestMessage(rocketID, i, models.MessageTypeRocketSpeedIncreased))
+	}
+
+	buckets, _ := repo.GetSpeedBuckets(rocketID, time.Time{}, time.Time{}, 0)
+	if len(buckets) != 5 {
+		t.Fatalf("Expected 5 retained points, got %d", len(buckets))
+	}
+	if buckets[0].LastMessageNumber != 16 || buckets[4].LastMessageNumber != 20 || buckets[4].Last != 1000+19*500 {
+		t.Errorf("Expected messages 16 to 20 to be retained, got %+v", buckets)
+	}
+}
+
+// Test HandleGetRocketSpeed
+func TestHandleGetRocketSpeed(t *testing.T) {
+	handler := api.NewAPIHandler()
+	rocketID := "speed-http-rocket"
+	start := time.Date(2024, 3, 14, 19, 39, 0, 0, time.UTC)
+
+	handler.Repository.ProcessMessage(createTimedMessage(rocketID, 1, models.MessageTypeRocketLaunched, start))
+	handler.Repository.ProcessMessage(createTimedMessage(rocketID, 2, models.MessageTypeRocketSpeedIncreased, start.Add(time.Minute)))
+
+	tests := []struct {
+		name           string
+		rocketID       string
+		query          string
+		expectedStatus int
+		expectedCount  int
+	}{
+		{"All points", rocketID, "", http.StatusOK, 2},
+		{"Downsampled", rocketID, "?step=1h", http.StatusOK, 1},
+		{"Time range", rocketID, "?from=2024-03-14T19:40:00Z", http.StatusOK, 1},
+		{"Invalid step", rocketID, "?step=-1m", http.StatusBadRequest, 0},
+		{"Invalid from", rocketID, "?from=yesterday", http.StatusBadRequest, 0},
+		{"Empty range", rocketID, "?from=2024-03-14T20:00:00Z&to=2024-03-14T19:00:00Z", http.StatusBadRequest, 0},
+		{"Unknown rocket", "missing-rocket", "", http.StatusNotFound, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/rockets/"+tt.rocketID+"/speed"+tt.query, nil)
+			req.SetPathValue("id", tt.rocketID)
+			rr := httptest.NewRecorder()
+
+			handler.HandleGetRocketSpeed(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d", tt.expectedStatus, rr.Code)
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var series api.SpeedSeries
+			if err := json.NewDecoder(rr.Body).Decode(&series); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if len(series.Buckets) != tt.expectedCount {
+				t.Errorf("Expected %d buckets, got %d", tt.expectedCount, len(series.Buckets))
+			}
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"lunar-backend-challenge/internal/api"
+	"lunar-backend-challenge/internal/filtering"
+	"lunar-backend-challenge/internal/models"
+	"lunar-backend-challenge/internal/storage"
+)
+
+// createLaunchMessage creates a launch message with the given rocket type, mission and speed
+func createLaunchMessage(channel string, messageNumber int, rocketType, mission string, speed int) *models.RocketMessage {
+	msg := createTestMessage(channel, messageNumber, models.MessageTypeRocketLaunched)
+	msg.Message.Type = rocketType
+	msg.Message.Mission = mission
+	msg.Message.LaunchSpeed = speed
+	return msg
+}
+
+// createStatsRepository creates a fleet of four rockets, one of which exploded
+func createStatsRepository() *storage.RocketRepository {
+	repo := storage.NewRocketRepository()
+	repo.ProcessMessage(createLaunchMessage("falcon-1", 1, "Falcon-9", "ARTEMIS", 1000))
+	repo.ProcessMessage(createLaunchMessage("falcon-2", 1, "Falcon-9", "APOLLO", 3000))
+	repo.ProcessMessage(createLaunchMessage("saturn-1", 1, "Saturn-V", "APOLLO", 2000))
+	repo.ProcessMessage(createLaunchMessage("saturn-2", 1, "Saturn-V", "ARTEMIS", 4000))
+	repo.ProcessMessage(createTestMessage("saturn-2", 2, models.MessageTypeRocketExploded))
+	return repo
+}
+
+// Test fleet statistics and that they follow rocket changes
+func TestGetStats(t *testing.T) {
+	repo := createStatsRepository()
+
+	stats := repo.GetStats(filtering.Filter{})
+	if stats.TotalRockets != 4 || stats.Active != 3 || stats.Exploded != 1 {
+		t.Errorf("Expected 4 rockets, 3 active and 1 exploded, got %+v", stats)
+	}
+	if falcon := stats.ByType["Falcon-9"]; falcon.Count != 2 || falcon.AverageSpeed != 2000 || falcon.MaxSpeed != 3000 {
+		t.Errorf("Unexpected Falcon-9 stats: %+v", falcon)
+	}
+	if artemis := stats.ByMission["ARTEMIS"]; artemis.Count != 2 || artemis.AverageSpeed != 2500 || artemis.MaxSpeed != 4000 {
+		t.Errorf("Unexpected ARTEMIS stats: %+v", artemis)
+	}
+	if len(stats.ExplosionsByReason) != 1 || stats.ExplosionsByReason["Engine failure"] != 1 {
+		t.Errorf("Expected one explosion by engine failure, got %v", stats.ExplosionsByReason)
+	}
+
+	// The fastest Falcon-9 slows down from 3000 to 900, below the other one
+	for i := 2; i <= 8; i++ {
+		repo.ProcessMessage(createTestMessage("falcon-2", i, models.MessageTypeRocketSpeedDecreased))
+	}
+	stats = repo.GetStats(filtering.Filter{})
+	if falcon := stats.ByType["Falcon-9"]; falcon.MaxSpeed != 1000 || falcon.AverageSpeed != 950 {
+		t.Errorf("Expected Falcon-9 max speed 1000 and average 950, got %+v", falcon)
+	}
+
+	// A relaunch moves the exploded rocket back to the active rockets
+	repo.ProcessMessage(createLaunchMessage("saturn-2", 3, "Saturn-V", "ARTEMIS", 500))
+	stats = repo.GetStats(filtering.Filter{})
+	if stats.Active != 4 || stats.Exploded != 0 || len(stats.ExplosionsByReason) != 0 {
+		t.Errorf("Expected relaunched rocket to count as active, got %+v", stats)
+	}
+}
+
+// Test fleet statistics with filters
+func TestGetStatsFiltered(t *testing.T) {
+	repo := createStatsRepository()
+
+	stats := repo.GetStats(filtering.Filter{Type: "saturn-v"})
+	if stats.TotalRockets != 2 || len(stats.ByType) != 1 || stats.ByType["Saturn-V"].MaxSpeed != 4000 {
+		t.Errorf("Expected only Saturn-V rockets, got %+v", stats)
+	}
+
+	stats = repo.GetStats(filtering.Filter{Status: models.RocketStatusActive, Mission: "artemis"})
+	if stats.TotalRockets != 1 || stats.ByMission["ARTEMIS"].Count != 1 || stats.Exploded != 0 {
+		t.Errorf("Expected one active ARTEMIS rocket, got %+v", stats)
+	}
+}
+
+// Test HandleGetStats and the matching rocket list filters
+func TestHandleGetStats(t *testing.T) {
+	handler := api.NewAPIHandlerWithRepository(createStatsRepository())
+
+	req := httptest.NewRequest(http.MethodGet, "/stats?mission=APOLLO", nil)
+	rr := httptest.NewRecorder()
+	handler.HandleGetStats(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, rr.Code)
+	}
+	var stats models.FleetStats
+	if err := json.NewDecoder(rr.Body).Decode(&stats); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if stats.TotalRockets != 2 || stats.ByMission["APOLLO"].AverageSpeed != 2500 {
+		t.Errorf("Expected two APOLLO rockets averaging 2500, got %+v", stats)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/rockets?status=exploded", nil)
+	rr = httptest.NewRecorder()
+	handler.HandleGetRockets(rr, req)
+
+	var rockets []models.RocketSummary
+	if err := json.NewDecoder(rr.Body).Decode(&rockets); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(rockets) != 1 || rockets[0].ID != "saturn-2" {
+		t.Errorf("Expected only the exploded rocket, got %+v", rockets)
+	}
+
+	// Unknown status
+	req = httptest.NewRequest(http.MethodGet, "/stats?status=flying", nil)
+	rr = httptest.NewRecorder()
+	handler.HandleGetStats(rr, req)
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d for stats, got %d", http.StatusBadRequest, rr.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/rockets?status=flying", nil)
+	rr = httptest.NewRecorder()
+	handler.HandleGetRockets(rr, req)
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d for rockets, got %d", http.StatusBadRequest, rr.Code)
+	}
+}
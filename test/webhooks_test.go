@@ -0,0 +1,341 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"lunar-backend-challenge/internal/api"
+	"lunar-backend-challenge/internal/models"
+	"lunar-backend-challenge/internal/storage"
+	"lunar-backend-challenge/internal/webhooks"
+)
+
+// webhookReceiver records the requests it receives and answers with the statuses it is given,
+// repeating the last one
+type webhookReceiver struct {
+	server   *httptest.Server
+	statuses []int
+	requests []receivedWebhook
+	mutex    sync.Mutex
+}
+
+type receivedWebhook struct {
+	header http.Header
+	body   []byte
+	event  webhooks.Event
+}
+
+func newWebhookReceiver(t *testing.T, statuses ...int) *webhookReceiver {
+	receiver := &webhookReceiver{statuses: statuses}
+	receiver.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var event webhooks.Event
+		json.Unmarshal(body, &event)
+
+		receiver.mutex.Lock()
+		receiver.requests = append(receiver.requests, receivedWebhook{header: r.Header, body: body, event: event})
+		status := http.StatusOK
+		if len(receiver.statuses) > 0 {
+			status = receiver.statuses[0]
+			if len(receiver.statuses) > 1 {
+				receiver.statuses = receiver.statuses[1:]
+			}
+		}
+		receiver.mutex.Unlock()
+
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(receiver.server.Close)
+	return receiver
+}
+
+func (r *webhookReceiver) received() []receivedWebhook {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return append([]receivedWebhook(nil), r.requests...)
+}
+
+// waitFor polls a condition until it holds or fails the test after a second
+func waitFor(t *testing.T, description string, condition func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatalf("Timed out waiting for %s", description)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// createWebhookRepository creates a repository observed by a dispatcher with fast retries
+func createWebhookRepository(t *testing.T) (*storage.RocketRepository, *webhooks.Dispatcher) {
+	options := webhooks.DefaultOptions()
+	options.MaxAttempts = 3
+	options.InitialBackoff = time.Millisecond
+	options.MaxBackoff = 2 * time.Millisecond
+	options.DisableAfter = 2
+
+	dispatcher := webhooks.NewDispatcher(options)
+	t.Cleanup(dispatcher.Close)
+
+	repo := storage.NewRocketRepository()
+	repo.Subscribe(dispatcher.Observe)
+	return repo, dispatcher
+}
+
+// Test that matching state changes are delivered in order with a valid signature
+func TestWebhookDelivery(t *testing.T) {
+	repo, dispatcher := createWebhookRepository(t)
+	receiver := newWebhookReceiver(t)
+	filtered := newWebhookReceiver(t)
+
+	all, err := dispatcher.Subscribe(webhooks.Subscription{URL: receiver.server.URL, Secret: "top-secret"})
+	if err != nil {
+		t.Fatalf("Failed to subscribe: %v", err)
+	}
+	if _, err := dispatcher.Subscribe(webhooks.Subscription{
+		URL:          filtered.server.URL,
+		MessageTypes: []string{models.MessageTypeRocketExploded},
+		Mission:      "test mission",
+	}); err != nil {
+		t.Fatalf("Failed to subscribe: %v", err)
+	}
+
+	rocketID := "webhook-rocket"
+	repo.ProcessMessage(createTestMessage(rocketID, 1, models.MessageTypeRocketLaunched))
+	repo.ProcessMessage(createTestMessage(rocketID, 2, models.MessageTypeRocketSpeedIncreased))
+	repo.ProcessMessage(createTestMessage(rocketID, 3, models.MessageTypeRocketExploded))
+	repo.ProcessMessage(createTestMessage("other-rocket", 1, models.MessageTypeRocketLaunched))
+	repo.ProcessMessage(createTestMessage("other-rocket", 2, models.MessageTypeRocketMissionChanged))
+	repo.ProcessMessage(createTestMessage("other-rocket", 3, models.MessageTypeRocketExploded)) // Not on "Test Mission" anymore
+
+	waitFor(t, "all events", func() bool { return len(receiver.received()) == 6 })
+	waitFor(t, "filtered events", func() bool { return len(filtered.received()) == 1 })
+
+	requests := receiver.received()
+	for i, request := range requests[:3] {
+		if request.event.RocketID != rocketID || request.event.MessageNumber != i+1 || request.event.Type != webhooks.EventStateChanged {
+			t.Errorf("Event %d: unexpected event %+v", i, request.event)
+		}
+		if !webhooks.Verify(all.Secret, request.body, request.header.Get(webhooks.HeaderSignature)) {
+			t.Errorf("Event %d: invalid signature %q", i, request.header.Get(webhooks.HeaderSignature))
+		}
+	}
+	if requests[0].event.Before != nil || requests[1].event.Before.Speed != 1000 || requests[1].event.After.Speed != 1500 {
+		t.Errorf("Expected before and after states, got %+v and %+v", requests[0].event, requests[1].event)
+	}
+	if webhooks.Verify("wrong-secret", requests[0].body, requests[0].header.Get(webhooks.HeaderSignature)) {
+		t.Error("Expected signature not to verify with another secret")
+	}
+
+	if event := filtered.received()[0].event; event.RocketID != rocketID || event.MessageType != models.MessageTypeRocketExploded {
+		t.Errorf("Expected only the explosion of %s, got %+v", rocketID, event)
+	}
+}
+
+// Test that failed attempts are retried and logged
+func TestWebhookRetries(t *testing.T) {
+	repo, dispatcher := createWebhookRepository(t)
+	receiver := newWebhookReceiver(t, http.StatusServiceUnavailable, http.StatusInternalServerError, http.StatusOK)
+
+	subscription, _ := dispatcher.Subscribe(webhooks.Subscription{URL: receiver.server.URL})
+	repo.ProcessMessage(createTestMessage("retry-rocket", 1, models.MessageTypeRocketLaunched))
+
+	waitFor(t, "three attempts", func() bool {
+		deliveries, _ := dispatcher.Deliveries(subscription.ID)
+		return len(deliveries) == 3
+	})
+
+	deliveries, _ := dispatcher.Deliveries(subscription.ID)
+	if !deliveries[0].Success || deliveries[0].Attempt != 3 || deliveries[0].StatusCode != http.StatusOK {
+		t.Errorf("Expected third attempt to succeed, got %+v", deliveries[0])
+	}
+	if deliveries[2].Success || deliveries[2].StatusCode != http.StatusServiceUnavailable || deliveries[2].Error == "" {
+		t.Errorf("Expected first attempt to fail, got %+v", deliveries[2])
+	}
+
+	requests := receiver.received()
+	if requests[0].header.Get(webhooks.HeaderDelivery) != requests[2].header.Get(webhooks.HeaderDelivery) {
+		t.Error("Expected retries to carry the same delivery ID")
+	}
+	if current, _ := dispatcher.Get(subscription.ID); !current.Enabled || current.ConsecutiveFailures != 0 {
+		t.Errorf("Expected subscription to stay enabled, got %+v", current)
+	}
+}
+
+// Test that a subscription is disabled after repeated failures and can be re-enabled
+func TestWebhookAutoDisable(t *testing.T) {
+	repo, dispatcher := createWebhookRepository(t)
+	receiver := newWebhookReceiver(t, http.StatusInternalServerError)
+
+	subscription, _ := dispatcher.Subscribe(webhooks.Subscription{URL: receiver.server.URL})
+	repo.ProcessMessage(createTestMessage("failing-rocket", 1, models.MessageTypeRocketLaunched))
+	repo.ProcessMessage(createTestMessage("failing-rocket", 2, models.MessageTypeRocketSpeedIncreased))
+
+	waitFor(t, "subscription to be disabled", func() bool {
+		current, _ := dispatcher.Get(subscription.ID)
+		return !current.Enabled
+	})
+
+	current, _ := dispatcher.Get(subscription.ID)
+	if current.ConsecutiveFailures != 2 || current.DisabledReason == "" || current.DisabledAt == nil {
+		t.Errorf("Expected subscription disabled after 2 failed events, got %+v", current)
+	}
+	if attempts := len(receiver.received()); attempts != 6 {
+		t.Errorf("Expected 3 attempts for each of 2 events, got %d", attempts)
+	}
+
+	// Events are not delivered while disabled
+	repo.ProcessMessage(createTestMessage("failing-rocket", 3, models.MessageTypeRocketSpeedIncreased))
+	time.Sleep(20 * time.Millisecond)
+	if attempts := len(receiver.received()); attempts != 6 {
+		t.Errorf("Expected no delivery while disabled, got %d attempts", attempts)
+	}
+
+	receiver.mutex.Lock()
+	receiver.statuses = []int{http.StatusOK}
+	receiver.mutex.Unlock()
+
+	if enabled, _ := dispatcher.Enable(subscription.ID); !enabled.Enabled || enabled.ConsecutiveFailures != 0 {
+		t.Fatalf("Expected subscription to be re-enabled, got %+v", enabled)
+	}
+	repo.ProcessMessage(createTestMessage("failing-rocket", 4, models.MessageTypeRocketSpeedIncreased))
+	waitFor(t, "delivery after re-enabling", func() bool {
+		requests := receiver.received()
+		return len(requests) == 7 && requests[6].event.MessageNumber == 4
+	})
+}
+
+// Test the webhook admin endpoints
+func TestHandleWebhooks(t *testing.T) {
+	handler := api.NewAPIHandler()
+	t.Cleanup(handler.Webhooks.Close)
+	receiver := newWebhookReceiver(t)
+
+	body, _ := json.Marshal(webhooks.Subscription{URL: receiver.server.URL, RocketID: "hooked-rocket"})
+	rr := httptest.NewRecorder()
+	handler.HandleCreateWebhook(rr, httptest.NewRequest(http.MethodPost, "/admin/webhooks", bytes.NewReader(body)))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, rr.Code)
+	}
+	var created webhooks.Subscription
+	json.NewDecoder(rr.Body).Decode(&created)
+	if created.ID == "" || created.Secret == "" || !created.Enabled {
+		t.Errorf("Expected an enabled subscription with a generated secret, got %+v", created)
+	}
+
+	invalid := []string{
+		`{"url": "not a url"}`,
+		`{"url": "ftp://example.com"}`,
+		`{"url": "http://example.com", "messageTypes": ["RocketLanded"]}`,
+		`{"url":`,
+	}
+	for _, body := range invalid {
+		rr = httptest.NewRecorder()
+		handler.HandleCreateWebhook(rr, httptest.NewRequest(http.MethodPost, "/admin/webhooks", bytes.NewBufferString(body)))
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected status %d, got %d", body, http.StatusBadRequest, rr.Code)
+		}
+	}
+
+	rr = httptest.NewRecorder()
+	handler.HandleListWebhooks(rr, httptest.NewRequest(http.MethodGet, "/admin/webhooks", nil))
+	var listed []webhooks.Subscription
+	json.NewDecoder(rr.Body).Decode(&listed)
+	if len(listed) != 1 || listed[0].Secret != "" {
+		t.Errorf("Expected one subscription without secret, got %+v", listed)
+	}
+
+	// Messages processed through the API are delivered
+	handler.Repository.ProcessMessage(createTestHTTPMessage("hooked-rocket", 1, models.MessageTypeRocketLaunched))
+	waitFor(t, "delivery", func() bool { return len(receiver.received()) == 1 })
+	// The attempt is logged once the receiver's response is read
+	waitFor(t, "delivery log", func() bool {
+		deliveries, _ := handler.Webhooks.Deliveries(created.ID)
+		return len(deliveries) == 1
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/webhooks/"+created.ID+"/deliveries", nil)
+	req.SetPathValue("id", created.ID)
+	rr = httptest.NewRecorder()
+	handler.HandleGetWebhookDeliveries(rr, req)
+	var deliveries []webhooks.Delivery
+	json.NewDecoder(rr.Body).Decode(&deliveries)
+	if len(deliveries) != 1 || !deliveries[0].Success {
+		t.Errorf("Expected one successful delivery, got %+v", deliveries)
+	}
+
+	req = httptest.NewRequest(http.MethodDelete, "/admin/webhooks/"+created.ID, nil)
+	req.SetPathValue("id", created.ID)
+	rr = httptest.NewRecorder()
+	handler.HandleDeleteWebhook(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Errorf("Expected status %d deleting, got %d", http.StatusOK, rr.Code)
+	}
+
+	rr = httptest.NewRecorder()
+	handler.HandleGetWebhook(rr, req)
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d after deleting, got %d", http.StatusNotFound, rr.Code)
+	}
+}
+
+// Test that a dispatcher created with zero options delivers with the default options
+func TestWebhookZeroOptions(t *testing.T) {
+	dispatcher := webhooks.NewDispatcher(webhooks.Options{})
+	t.Cleanup(dispatcher.Close)
+	repo := storage.NewRocketRepository()
+	repo.Subscribe(dispatcher.Observe)
+
+	receiver := newWebhookReceiver(t)
+	subscription, err := dispatcher.Subscribe(webhooks.Subscription{URL: receiver.server.URL})
+	if err != nil {
+		t.Fatalf("Failed to subscribe: %v", err)
+	}
+
+	repo.ProcessMessage(createTestMessage("zero-options-rocket", 1, models.MessageTypeRocketLaunched))
+	waitFor(t, "delivery", func() bool {
+		deliveries, _ := dispatcher.Deliveries(subscription.ID)
+		return len(deliveries) == 1 && deliveries[0].Success
+	})
+}
+
+// Test that silence and recovery are delivered to subscriptions without a message type filter
+func TestWebhookSilenceEvents(t *testing.T) {
+	options := webhooks.DefaultOptions()
+	dispatcher := webhooks.NewDispatcher(options)
+	t.Cleanup(dispatcher.Close)
+
+	now := time.Date(2024, 3, 14, 19, 39, 0, 0, time.UTC)
+	repo := createSilenceRepository(&now, storage.SilenceByReceiveTime)
+	repo.Subscribe(dispatcher.Observe)
+
+	receiver := newWebhookReceiver(t)
+	typed := newWebhookReceiver(t)
+	dispatcher.Subscribe(webhooks.Subscription{URL: receiver.server.URL})
+	dispatcher.Subscribe(webhooks.Subscription{URL: typed.server.URL, MessageTypes: []string{models.MessageTypeRocketSpeedIncreased}})
+
+	repo.ProcessMessage(createTestMessage("silent-hook-rocket", 1, models.MessageTypeRocketLaunched))
+	now = now.Add(time.Hour)
+	repo.DetectSilence(now)
+	repo.ProcessMessage(createTestMessage("silent-hook-rocket", 2, models.MessageTypeRocketSpeedIncreased))
+
+	waitFor(t, "all events", func() bool { return len(receiver.received()) == 4 })
+	waitFor(t, "typed events", func() bool { return len(typed.received()) == 1 })
+
+	expected := []string{webhooks.EventStateChanged, webhooks.EventSilent, webhooks.EventRecovered, webhooks.EventStateChanged}
+	for i, request := range receiver.received() {
+		if request.event.Type != expected[i] || request.header.Get(webhooks.HeaderEvent) != expected[i] {
+			t.Errorf("Event %d: expected %s, got %s", i, expected[i], request.event.Type)
+		}
+	}
+	if silent := receiver.received()[1].event; silent.MessageType != "" || !silent.After.Silent {
+		t.Errorf("Expected silent event without message, got %+v", silent)
+	}
+}